@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+// runAncestor proves the block root of the configured slot inside the
+// block_roots of a recent anchor state, for blocks within 8192 slots of the
+// anchor whose own EIP-4788 entry may already be gone
+func (a *Application) runAncestor(ctx context.Context) error {
+	t, err := a.fetchAnchoredTarget(ctx, config.ModeAncestor)
+	if err != nil {
+		return err
+	}
+	if err := proof.CheckAncestorRange(t.TargetSlot, t.AnchorSlot); err != nil {
+		return err
+	}
+
+	schema, anchorState, err := a.fetchStateAt(ctx, t.Anchor)
+	if err != nil {
+		return err
+	}
+
+	proofData, err := proof.GenerateAncestorProof(t.Anchor, schema, anchorState.Data, t.TargetSlot, t.Next.Timestamp)
+	if err != nil {
+		return err
+	}
+	if proofData.FieldValue != "0x"+hex.EncodeToString(t.TargetRoot[:]) {
+		return fmt.Errorf("anchor block_roots holds %s for slot %d, but the fetched header hashes to 0x%x",
+			proofData.FieldValue, t.TargetSlot, t.TargetRoot)
+	}
+
+	name := fmt.Sprintf("ancestor block root at slot %d", t.TargetSlot)
+	return a.verifyProofs(ctx, map[string]proof.Data{name: proofData})
+}
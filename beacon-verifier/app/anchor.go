@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+// anchoredTarget is an older block that gets proven against a recent anchor
+// block whose root is still in the EIP-4788 buffer
+type anchoredTarget struct {
+	Target     beacon.HeaderData
+	TargetSlot uint64
+	TargetRoot [32]byte
+	Anchor     beacon.HeaderData
+	AnchorSlot uint64
+	// Next is the block after the anchor, whose timestamp keys the lookup
+	Next beacon.HeaderData
+}
+
+// fetchAnchoredTarget fetches the configured target slot and the anchor block
+func (a *Application) fetchAnchoredTarget(ctx context.Context, mode string) (anchoredTarget, error) {
+	var t anchoredTarget
+	if a.Config.Slot == "" {
+		return t, fmt.Errorf("%s mode needs the slot of the block to prove", mode)
+	}
+
+	var err error
+	t.Target, err = a.locateHeader(ctx, a.Config.Slot)
+	if err != nil {
+		return t, fmt.Errorf("error fetching target block %s: %w", a.Config.Slot, err)
+	}
+	if err := t.Target.VerifyRoot(nil); err != nil {
+		return t, err
+	}
+	if t.TargetRoot, err = t.Target.HashTreeRoot(); err != nil {
+		return t, err
+	}
+	if t.TargetSlot, err = strconv.ParseUint(t.Target.Slot, 10, 64); err != nil {
+		return t, fmt.Errorf("error parsing target slot: %w", err)
+	}
+
+	t.Anchor, t.Next, err = a.fetchHeaderPair(ctx, a.Config.AnchorSlot)
+	if err != nil {
+		return t, fmt.Errorf("error fetching anchor header: %w", err)
+	}
+	if t.AnchorSlot, err = strconv.ParseUint(t.Anchor.Slot, 10, 64); err != nil {
+		return t, fmt.Errorf("error parsing anchor slot: %w", err)
+	}
+	return t, nil
+}
+
+// fetchStateAt downloads the post-state of a header's slot and returns it
+// with the fork layout it is in
+func (a *Application) fetchStateAt(ctx context.Context, headerData beacon.HeaderData) (*beacon.Schema, *beacon.State, error) {
+	log.Printf("Fetching beacon state at slot %s...", headerData.Slot)
+	state, err := a.BeaconClient.FetchState(ctx, headerData.Slot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not fetch state at slot %s: %w", headerData.Slot, err)
+	}
+	if err := a.checkFork(headerData.Slot, state.Version); err != nil {
+		return nil, nil, err
+	}
+	schema, err := a.schemaAt(headerData.Slot, state.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, state, nil
+}
+
+// verifyProofs checks proofs on-chain and displays the results
+func (a *Application) verifyProofs(ctx context.Context, proofs map[string]proof.Data) error {
+	if !a.Web3Connected {
+		log.Println("Warning: No Ethereum connection available. Skipping on-chain verification.")
+		return nil
+	}
+
+	results := make(map[string]bool, len(proofs))
+	for name, proofData := range proofs {
+		log.Printf("\n=== Verifying %s on-chain ===", name)
+		result, err := proof.VerifyOnChain(ctx, a.EthereumClient, a.Config.Verification.VerifierAddress, proofData)
+		if err != nil {
+			log.Printf("Error performing onchain verification: %v", err)
+			continue
+		}
+		results[name] = result
+	}
+
+	a.displayResults(results)
+	return nil
+}
@@ -2,14 +2,20 @@ package app
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
 	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
 	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
 )
@@ -20,10 +26,22 @@ type Application struct {
 	BeaconClient   *beacon.Client
 	EthereumClient *ethclient.Client
 	Web3Connected  bool
+	// ForkSchedule is nil when the chain ID has no known schedule; the fork
+	// version reported by the beacon API is trusted instead
+	ForkSchedule *beacon.ForkSchedule
+	// Quorum is set when headers must be confirmed by several endpoints
+	Quorum *beacon.Quorum
+	// ChainSpec is the configuration reported by the beacon node, nil when
+	// the node did not serve it
+	ChainSpec *beacon.ChainSpec
+	// Clock converts between slots and time; nil when neither the node nor
+	// the configured chain ID provides a genesis time
+	Clock *chaintime.Clock
 }
 
-// NewApplication creates and initializes a new application instance
-func NewApplication(cfg *config.Config) (*Application, error) {
+// NewApplication creates and initializes a new application instance. ctx
+// bounds the connection checks made while initializing.
+func NewApplication(ctx context.Context, cfg *config.Config) (*Application, error) {
 	if len(cfg.BeaconAPI.Endpoints) == 0 {
 		return nil, fmt.Errorf("no beacon API endpoints configured")
 	}
@@ -33,13 +51,37 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 		BeaconClient:  beacon.NewClient(cfg.BeaconAPI.Endpoints[0]),
 		Web3Connected: false,
 	}
+	app.BeaconClient.Timeout = time.Duration(cfg.BeaconAPI.RequestTimeoutMs) * time.Millisecond
+	app.BeaconClient.Retry.MaxAttempts = cfg.BeaconAPI.RetryAttempts
+	if len(cfg.BeaconAPI.Endpoints) > 1 {
+		app.BeaconClient.Pool = beacon.NewPool(cfg.BeaconAPI.Endpoints...)
+		log.Printf("Routing beacon API requests over %d endpoints", len(cfg.BeaconAPI.Endpoints))
+	}
+	if cfg.BeaconAPI.Quorum > 0 {
+		if cfg.BeaconAPI.Quorum > len(cfg.BeaconAPI.Endpoints) {
+			return nil, fmt.Errorf("quorum of %d needs at least as many beacon API endpoints, %d configured",
+				cfg.BeaconAPI.Quorum, len(cfg.BeaconAPI.Endpoints))
+		}
+		app.Quorum = &beacon.Quorum{Threshold: cfg.BeaconAPI.Quorum}
+		for _, endpoint := range cfg.BeaconAPI.Endpoints {
+			client := beacon.NewClient(endpoint)
+			client.Timeout = app.BeaconClient.Timeout
+			client.Retry = app.BeaconClient.Retry
+			app.Quorum.Clients = append(app.Quorum.Clients, client)
+		}
+		log.Printf("Requiring %d of %d endpoints to agree on every verified header", cfg.BeaconAPI.Quorum, len(cfg.BeaconAPI.Endpoints))
+	}
+
+	if err := app.loadChainSpec(ctx); err != nil {
+		return nil, err
+	}
 
 	// Initialize Ethereum client for onchain verification
-	client, err := ethclient.Dial(cfg.EthereumNode.Endpoint)
+	client, err := ethclient.DialContext(ctx, cfg.EthereumNode.Endpoint)
 	if err != nil {
 		log.Printf("Warning: Error setting up Web3: %v", err)
 	} else {
-		chainID, err := client.ChainID(context.Background())
+		chainID, err := client.ChainID(ctx)
 		if err != nil {
 			log.Printf("Warning: Error getting chain ID: %v", err)
 		} else {
@@ -53,55 +95,180 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 	return app, nil
 }
 
+// loadChainSpec takes the fork schedule from the beacon node's chain spec.
+// The node's genesis validators root identifies the chain it follows, which
+// replaces the default chain ID unless one was set explicitly; an explicit
+// chain ID that the node contradicts is an error. Without a spec the
+// built-in schedule of the chain ID is used; without either, the fork
+// reported by the beacon API is trusted.
+func (a *Application) loadChainSpec(ctx context.Context) error {
+	chainID := a.Config.EthereumNode.ChainID
+	spec, err := a.BeaconClient.ChainSpec(ctx)
+	if err != nil {
+		known, knownErr := beacon.ForkScheduleForChain(chainID)
+		if knownErr != nil {
+			log.Printf("Warning: could not fetch the chain spec (%v) and %v. Falling back to the fork reported by the beacon API.", err, knownErr)
+			return nil
+		}
+		log.Printf("Warning: could not fetch the chain spec: %v. Using the built-in fork schedule of chain ID %d.", err, chainID)
+		a.ForkSchedule = &known
+		a.Clock, _ = chaintime.ForChain(chainID)
+		return nil
+	}
+
+	if err := spec.CheckPreset(); err != nil {
+		return err
+	}
+	detected, isKnown := beacon.ChainIDForGenesisValidatorsRoot(spec.GenesisValidatorsRoot)
+	if a.Config.EthereumNode.ChainIDSet() {
+		if _, err := beacon.ForkScheduleForChain(chainID); err == nil && detected != chainID {
+			return fmt.Errorf("beacon node follows %q with genesis validators root %x, not chain ID %d",
+				spec.ConfigName, spec.GenesisValidatorsRoot, chainID)
+		}
+	} else if isKnown && detected != chainID {
+		log.Printf("Beacon node follows chain ID %d; using it instead of the default %d", detected, chainID)
+		a.Config.EthereumNode.ChainID = detected
+	}
+	clock, err := chaintime.FromSpec(spec)
+	if err != nil {
+		return err
+	}
+	log.Printf("Beacon node follows %s: genesis at %s, %d-second slots, %d forks scheduled",
+		spec.ConfigName, clock.Genesis().Format(time.RFC3339), clock.SecondsPerSlot, len(spec.Forks.Forks))
+	a.ChainSpec = spec
+	a.ForkSchedule = &spec.Forks
+	a.Clock = clock
+	return nil
+}
+
 // Run executes the main application logic
-func (a *Application) Run() error {
+func (a *Application) Run(ctx context.Context) error {
+	if pool := a.BeaconClient.Pool; pool != nil {
+		go pool.Run(ctx, time.Duration(a.Config.BeaconAPI.ProbeIntervalMs)*time.Millisecond)
+	}
+
+	switch a.Config.Mode {
+	case config.ModeHeader, "":
+	case config.ModeHistorical:
+		return a.runHistorical(ctx)
+	case config.ModeAncestor:
+		return a.runAncestor(ctx)
+	case config.ModeSkipped:
+		return a.runSkipped(ctx)
+	case config.ModeChain:
+		return a.runChain(ctx)
+	case config.ModeLightClient:
+		return a.runLightClient(ctx)
+	case config.ModeFollow:
+		return a.runFollow(ctx)
+	default:
+		return fmt.Errorf("unknown mode %q", a.Config.Mode)
+	}
+
+	headerToVerify, nextFilledSlotHeader, err := a.fetchHeaderPair(ctx, a.Config.Slot)
+	if err != nil {
+		return err
+	}
+	return a.verifyHeader(ctx, headerToVerify, nextFilledSlotHeader)
+}
+
+// verifyHeader proves the configured fields of a header whose root the next
+// filled header links to, and optionally its EIP-4788 timestamp and
+// execution requests
+func (a *Application) verifyHeader(ctx context.Context, headerToVerify beacon.HeaderData, nextFilledSlotHeader beacon.HeaderData) error {
+	a.displayHeaderInfo(headerToVerify, nextFilledSlotHeader)
+
+	results, err := a.verifyFields(ctx, headerToVerify, nextFilledSlotHeader)
+	if err != nil {
+		return fmt.Errorf("error during verification: %w", err)
+	}
+
+	if a.Config.Verification.ProveTimestamp {
+		if err := a.proveTimestamp(ctx, headerToVerify, nextFilledSlotHeader); err != nil {
+			return fmt.Errorf("error proving the EIP-4788 timestamp: %w", err)
+		}
+		results["next block timestamp"] = true
+	}
+
+	if a.Config.Verification.VerifySignature {
+		if err := a.verifyProposerSignature(ctx, headerToVerify); err != nil {
+			return fmt.Errorf("error verifying the proposer signature: %w", err)
+		}
+		results["proposer signature"] = true
+	}
+
+	if a.Config.Verification.VerifyExecutionRequests {
+		requestResults, err := a.verifyExecutionRequests(ctx, headerToVerify, nextFilledSlotHeader)
+		if err != nil {
+			return fmt.Errorf("error verifying execution requests: %w", err)
+		}
+		maps.Copy(results, requestResults)
+	}
+
+	a.displayResults(results)
+
+	return nil
+}
+
+// fetchHeaderPair fetches the block a block identifier names together with
+// the next filled header, whose timestamp keys the EIP-4788 lookup. Without
+// an identifier, the block before the current head is used.
+func (a *Application) fetchHeaderPair(ctx context.Context, blockID string) (beacon.HeaderData, beacon.HeaderData, error) {
 	var (
 		err                                  error
 		nextFilledSlotHeader, headerToVerify beacon.HeaderData
 	)
-	if a.Config.Slot != "" {
-		log.Printf("Using specified slot %s for verification...", a.Config.Slot)
-		headerToVerify, err = a.fetchHeader(beacon.HeaderData{Slot: a.Config.Slot}, beacon.Requested)
+	if blockID != "" {
+		log.Printf("Using specified block %s for verification...", blockID)
+		headerToVerify, err = a.locateHeader(ctx, blockID)
 		if err != nil {
-			return fmt.Errorf("error fetching specified slot %s: %w", a.Config.Slot, err)
+			return beacon.HeaderData{}, beacon.HeaderData{}, fmt.Errorf("error fetching specified block %s: %w", blockID, err)
 		}
-		nextFilledSlotHeader, err = a.fetchHeader(headerToVerify, beacon.Next)
+		nextFilledSlotHeader, err = a.fetchHeader(ctx, headerToVerify, beacon.Next)
 		if err != nil {
-			return fmt.Errorf("error fetching previous header: %w", err)
+			return beacon.HeaderData{}, beacon.HeaderData{}, fmt.Errorf("error fetching previous header: %w", err)
 		}
 	} else {
-		nextFilledSlotHeader, err = a.fetchLatestHeader()
+		nextFilledSlotHeader, err = a.fetchLatestHeader(ctx)
 		if err != nil {
-			return fmt.Errorf("error fetching latest header: %w", err)
+			return beacon.HeaderData{}, beacon.HeaderData{}, fmt.Errorf("error fetching latest header: %w", err)
 		}
 
 		log.Println("No specific slot provided. Attempting to fetch a previous header for verification...")
-		headerToVerify, err = a.fetchHeader(nextFilledSlotHeader, beacon.Previous)
+		headerToVerify, err = a.fetchHeader(ctx, nextFilledSlotHeader, beacon.Previous)
 		if err != nil {
-			return fmt.Errorf("error fetching previous header: %w", err)
+			return beacon.HeaderData{}, beacon.HeaderData{}, fmt.Errorf("error fetching previous header: %w", err)
 		}
 	}
 
-	a.displayHeaderInfo(nextFilledSlotHeader, headerToVerify)
-
-	results, err := a.verifyFields(headerToVerify, nextFilledSlotHeader)
-	if err != nil {
-		return fmt.Errorf("error during verification: %w", err)
+	// Every proof is built from the local root; make sure the API agrees
+	// before anything is sent on-chain
+	if err := headerToVerify.VerifyRoot(&nextFilledSlotHeader); err != nil {
+		return beacon.HeaderData{}, beacon.HeaderData{}, err
 	}
-
-	a.displayResults(results)
-
-	return nil
+	return headerToVerify, nextFilledSlotHeader, nil
 }
 
-// fetchLatestHeader retrieves the latest beacon block header
-func (a *Application) fetchLatestHeader() (beacon.HeaderData, error) {
+// fetchLatestHeader retrieves the latest beacon block header. In quorum mode
+// the head is taken from one node and then confirmed by root, since nodes
+// need not agree on which block is the head at any moment.
+func (a *Application) fetchLatestHeader(ctx context.Context) (beacon.HeaderData, error) {
 	log.Printf("Fetching latest beacon block header from %s...", a.Config.BeaconAPI.Endpoints[0])
 
-	latestHeaderData, err := a.BeaconClient.FetchBlockHeader("head")
+	latestHeaderData, err := a.BeaconClient.FetchBlockHeader(ctx, "head")
 	if err != nil {
 		return beacon.HeaderData{}, fmt.Errorf("could not fetch latest beacon block header: %w", err)
 	}
+	if a.Quorum != nil {
+		root, err := latestHeaderData.HashTreeRoot()
+		if err != nil {
+			return beacon.HeaderData{}, err
+		}
+		latestHeaderData, err = a.fetchBlockHeader(ctx, "0x"+hex.EncodeToString(root[:]))
+		if err != nil {
+			return beacon.HeaderData{}, fmt.Errorf("could not confirm the latest beacon block header: %w", err)
+		}
+	}
 
 	latestSlot, err := strconv.ParseUint(latestHeaderData.Slot, 10, 64)
 	if err != nil {
@@ -112,26 +279,24 @@ func (a *Application) fetchLatestHeader() (beacon.HeaderData, error) {
 	return latestHeaderData, nil
 }
 
-// fetchHeader attempts to fetch an adjacent block header for verification.
-// The 'direction' parameter should be either Previous or Next.
 // fetchHeader attempts to fetch an adjacent block header for verification.
 // The 'direction' parameter should be either beacon.Previous, beacon.Next, or beacon.Requested.
-func (a *Application) fetchHeader(header beacon.HeaderData, direction beacon.Direction) (beacon.HeaderData, error) {
+// Up to SlotSearchWindow empty slots are skipped; failed requests are retried
+// by the beacon client itself.
+func (a *Application) fetchHeader(ctx context.Context, header beacon.HeaderData, direction beacon.Direction) (beacon.HeaderData, error) {
 	slot, err := strconv.ParseUint(header.Slot, 10, 64)
 	if err != nil {
 		return beacon.HeaderData{}, fmt.Errorf("error parsing header slot: %w", err)
 	}
 
-	maxAttempts := a.Config.BeaconAPI.RetryAttempts
+	window := a.Config.BeaconAPI.SlotSearchWindow
 	var targetSlot uint64
-	// logic above is to handle missing slots and not take into account, that url could be unreachable
-	for i := 1; i <= maxAttempts; i++ {
+	for i := 1; i <= window; i++ {
 		switch direction {
 		case beacon.Previous:
 			// For a previous header, subtract the offset. Check to avoid underflow.
 			if slot < uint64(i) {
-				// If we can't subtract i from slot, break.
-				break
+				return beacon.HeaderData{}, fmt.Errorf("no filled slot found before slot %d", slot)
 			}
 			targetSlot = slot - uint64(i)
 		case beacon.Next:
@@ -145,22 +310,44 @@ func (a *Application) fetchHeader(header beacon.HeaderData, direction beacon.Dir
 			return beacon.HeaderData{}, fmt.Errorf("invalid direction")
 		}
 
-		log.Printf("Fetching beacon block header at slot %d... (attempt %d/%d)", targetSlot, i, maxAttempts)
+		log.Printf("Fetching beacon block header at slot %d... (%d/%d)", targetSlot, i, window)
 
-		currentHeaderData, err := a.BeaconClient.FetchBlockHeader(strconv.FormatUint(targetSlot, 10))
-		if err == nil && currentHeaderData.Slot != "" {
+		currentHeaderData, err := a.fetchBlockHeader(ctx, strconv.FormatUint(targetSlot, 10))
+		switch {
+		case err == nil:
 			blockTimestamp := currentHeaderData.Timestamp
 			log.Printf("Successfully fetched block header at slot %d", targetSlot)
 			log.Printf("Block time: %s", time.Unix(blockTimestamp, 0).UTC().Format("2006-01-02 15:04:05 UTC"))
 			log.Printf("Block proposer: %s", currentHeaderData.ProposerIndex)
 			return currentHeaderData, nil
-		} else {
-			log.Printf("Error fetching block header at slot %d: %v", targetSlot, err)
+		case errors.Is(err, beacon.ErrSlotEmpty):
+			if direction == beacon.Requested {
+				return beacon.HeaderData{}, fmt.Errorf("slot %d: %w", targetSlot, err)
+			}
+			log.Printf("Slot %d is empty, moving on", targetSlot)
+		default:
+			return beacon.HeaderData{}, fmt.Errorf("error fetching block header at slot %d: %w", targetSlot, err)
 		}
 	}
 
-	log.Printf("Failed to fetch any valid beacon block header after %d attempts.", maxAttempts)
-	return beacon.HeaderData{}, fmt.Errorf("could not fetch any valid beacon block header")
+	log.Printf("Failed to fetch any valid beacon block header within %d slots.", window)
+	return beacon.HeaderData{}, fmt.Errorf("could not fetch any valid beacon block header: %d slots were empty", window)
+}
+
+// fetchBlockHeader fetches a header from the beacon API or, in quorum mode,
+// from every endpoint, failing unless enough of them agree on it
+func (a *Application) fetchBlockHeader(ctx context.Context, blockID string) (beacon.HeaderData, error) {
+	if a.Quorum == nil {
+		return a.BeaconClient.FetchBlockHeader(ctx, blockID)
+	}
+	header, disagreements, err := a.Quorum.FetchBlockHeader(ctx, blockID)
+	for _, d := range disagreements {
+		log.Printf("Warning: endpoint disagrees on block %s: %s", blockID, d)
+	}
+	if err == nil {
+		log.Printf("%d of %d endpoints agree on block %s", len(a.Quorum.Clients)-len(disagreements), len(a.Quorum.Clients), blockID)
+	}
+	return header, err
 }
 
 // displayHeaderInfo shows information about the header being verified
@@ -177,12 +364,22 @@ func (a *Application) displayHeaderInfo(headerData beacon.HeaderData, nextHeader
 	timeDifference := nextSlotTimestamp - blockTimestamp
 
 	log.Printf("\nNext filled slot timestamp: %d", nextSlotTimestamp)
+	if a.Clock == nil {
+		log.Printf("Time difference between blocks: %d seconds", timeDifference)
+		return
+	}
 	log.Printf("Time difference between blocks: %d seconds (%.2f slots)",
-		timeDifference, float64(timeDifference)/beacon.SecondsPerSlot)
+		timeDifference, float64(timeDifference)/float64(a.Clock.SecondsPerSlot))
+	if slot, err := strconv.ParseUint(headerData.Slot, 10, 64); err == nil {
+		if expected, err := a.Clock.ExecutionTimestamp(slot); err == nil && expected != uint64(blockTimestamp) {
+			log.Printf("Warning: block timestamp %d does not match the start of slot %d at %d", blockTimestamp, slot, expected)
+		}
+		log.Printf("Slot %d is in epoch %d, sync committee period %d", slot, a.Clock.EpochOf(slot), a.Clock.SyncPeriodOf(slot))
+	}
 }
 
-// verifyFields generates and verifies proofs for each field
-func (a *Application) verifyFields(headerData beacon.HeaderData, nextFilledSlotHeader beacon.HeaderData) (map[string]bool, error) {
+// verifyFields generates and verifies proofs for each configured field or path
+func (a *Application) verifyFields(ctx context.Context, headerData beacon.HeaderData, nextFilledSlotHeader beacon.HeaderData) (map[string]bool, error) {
 	proofResults := make(map[string]bool)
 
 	if !a.Web3Connected {
@@ -193,9 +390,14 @@ func (a *Application) verifyFields(headerData beacon.HeaderData, nextFilledSlotH
 	fields := a.Config.Verification.FieldsToVerify
 	nextSlotTimestamp := nextFilledSlotHeader.Timestamp
 
+	schema, sources, err := a.pathSources(ctx, headerData, fields)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, fieldName := range fields {
 		log.Printf("\n=== Generating proof for %s ===", fieldName)
-		proofData, err := proof.GenerateHeaderProof(headerData, fieldName, nextSlotTimestamp)
+		proofData, err := proof.GeneratePathProof(headerData, schema, sources, fieldName, nextSlotTimestamp)
 		if err != nil {
 			log.Printf("Error generating proof for %s: %v", fieldName, err)
 			continue
@@ -205,7 +407,7 @@ func (a *Application) verifyFields(headerData beacon.HeaderData, nextFilledSlotH
 
 		// Perform onchain verification
 		log.Println("\nPerforming onchain verification...")
-		result, err := proof.VerifyOnChain(a.EthereumClient, a.Config.Verification.VerifierAddress, proofData)
+		result, err := proof.VerifyOnChain(ctx, a.EthereumClient, a.Config.Verification.VerifierAddress, proofData)
 		if err != nil {
 			log.Printf("Error performing onchain verification: %v", err)
 		} else {
@@ -216,6 +418,226 @@ func (a *Application) verifyFields(headerData beacon.HeaderData, nextFilledSlotH
 	return proofResults, nil
 }
 
+// pathSources fetches the block body and state that the given paths descend
+// into and picks the fork layout of the verified slot
+func (a *Application) pathSources(ctx context.Context, headerData beacon.HeaderData, paths []string) (*beacon.Schema, proof.Sources, error) {
+	var (
+		sources proof.Sources
+		version string
+	)
+
+	needs := make(map[string]bool)
+	for _, path := range paths {
+		root, _, _ := strings.Cut(path, ".")
+		root, _, _ = strings.Cut(root, "[")
+		needs[root] = true
+	}
+
+	if needs[beacon.BodyPathRoot] {
+		block, err := a.fetchBlockOf(ctx, headerData)
+		if err != nil {
+			return nil, sources, err
+		}
+		if sources.Body, err = block.Body(); err != nil {
+			return nil, sources, err
+		}
+		version = block.Version
+	}
+
+	if needs[beacon.StatePathRoot] {
+		log.Printf("Fetching beacon state at slot %s (this can take a while)...", headerData.Slot)
+		state, err := a.BeaconClient.FetchState(ctx, headerData.Slot)
+		if err != nil {
+			return nil, sources, fmt.Errorf("could not fetch state at slot %s: %w", headerData.Slot, err)
+		}
+		if err := a.checkFork(headerData.Slot, state.Version); err != nil {
+			return nil, sources, err
+		}
+		sources.State = state.Data
+		version = state.Version
+	}
+
+	schema, err := a.schemaAt(headerData.Slot, version)
+	if err != nil {
+		return nil, sources, err
+	}
+	return schema, sources, nil
+}
+
+// schemaAt returns the fork layout at a slot, preferring the configured fork
+// schedule over the fork reported by the beacon API
+func (a *Application) schemaAt(slotStr string, reported string) (*beacon.Schema, error) {
+	if a.ForkSchedule != nil {
+		slot, err := strconv.ParseUint(slotStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing slot: %w", err)
+		}
+		return a.ForkSchedule.SchemaAtSlot(slot)
+	}
+	if reported != "" {
+		return beacon.SchemaFor(reported)
+	}
+	// Only header fields were requested; their layout is the same in every fork
+	return beacon.SchemaFor(beacon.Forks[len(beacon.Forks)-1])
+}
+
+// proveTimestamp proves that the next filled block is the child of the
+// verified block and carries the execution timestamp used as the EIP-4788
+// lookup key, so the key no longer comes from the API on faith
+func (a *Application) proveTimestamp(ctx context.Context, headerData beacon.HeaderData, nextFilledSlotHeader beacon.HeaderData) error {
+	verifiedRoot, err := headerData.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+
+	next, err := a.fetchBlockOf(ctx, nextFilledSlotHeader)
+	if err != nil {
+		return err
+	}
+	schema, err := a.schemaAt(nextFilledSlotHeader.Slot, next.Version)
+	if err != nil {
+		return err
+	}
+	body, err := next.Body()
+	if err != nil {
+		return err
+	}
+
+	tsProof, err := proof.GenerateTimestampProof(nextFilledSlotHeader, schema, body, verifiedRoot)
+	if err != nil {
+		return err
+	}
+	if err := tsProof.Verify(schema, verifiedRoot); err != nil {
+		return err
+	}
+
+	bundle, err := json.MarshalIndent(tsProof, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding timestamp proof: %w", err)
+	}
+	log.Printf("Timestamp proof:\n%s", bundle)
+	return nil
+}
+
+// verifyProposerSignature checks the proposer's signature over the header
+// against the public key proven from the header's own state
+func (a *Application) verifyProposerSignature(ctx context.Context, headerData beacon.HeaderData) error {
+	if a.ForkSchedule == nil {
+		return fmt.Errorf("no fork schedule known for chain ID %d to derive the signing domain", a.Config.EthereumNode.ChainID)
+	}
+
+	log.Printf("Fetching state at slot %s for the proposer's public key...", headerData.Slot)
+	state, err := a.BeaconClient.FetchState(ctx, headerData.Slot)
+	if err != nil {
+		return fmt.Errorf("could not fetch state at slot %s: %w", headerData.Slot, err)
+	}
+	if err := a.checkFork(headerData.Slot, state.Version); err != nil {
+		return err
+	}
+	schema, err := a.schemaAt(headerData.Slot, state.Version)
+	if err != nil {
+		return err
+	}
+
+	signature, err := proof.GenerateProposerSignature(headerData, *a.ForkSchedule, headerData, schema, state.Data)
+	if err != nil {
+		return err
+	}
+	if err := signature.Verify(*a.ForkSchedule, schema); err != nil {
+		return err
+	}
+
+	bundle, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding proposer signature: %w", err)
+	}
+	log.Printf("Proposer signature:\n%s", bundle)
+	return nil
+}
+
+// verifyExecutionRequests proves every deposit, withdrawal and consolidation
+// request carried by the block and verifies each proof on-chain
+func (a *Application) verifyExecutionRequests(ctx context.Context, headerData beacon.HeaderData, nextFilledSlotHeader beacon.HeaderData) (map[string]bool, error) {
+	proofResults := make(map[string]bool)
+
+	if !a.Web3Connected {
+		log.Println("Warning: No Ethereum connection available. Skipping execution request verification.")
+		return proofResults, nil
+	}
+
+	block, err := a.fetchBlockOf(ctx, headerData)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := proof.ExecutionRequestCounts(block)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kind := range beacon.ExecutionRequestKinds {
+		log.Printf("Block carries %d %s requests", counts[kind], kind)
+		for i := 0; i < counts[kind]; i++ {
+			name := fmt.Sprintf("execution_requests.%s[%d]", kind, i)
+			log.Printf("\n=== Generating proof for %s ===", name)
+
+			proofData, err := proof.GenerateExecutionRequestProof(headerData, block, kind, i, nextFilledSlotHeader.Timestamp)
+			if err != nil {
+				log.Printf("Error generating proof for %s: %v", name, err)
+				continue
+			}
+
+			log.Println("\nPerforming onchain verification...")
+			result, err := proof.VerifyOnChain(ctx, a.EthereumClient, a.Config.Verification.VerifierAddress, proofData)
+			if err != nil {
+				log.Printf("Error performing onchain verification: %v", err)
+			} else {
+				proofResults[name] = result
+			}
+		}
+	}
+
+	return proofResults, nil
+}
+
+// fetchBlockOf fetches the block of a header for proofs into its body,
+// checking its fork and that its body hashes to the header's body_root
+func (a *Application) fetchBlockOf(ctx context.Context, headerData beacon.HeaderData) (*beacon.Block, error) {
+	block, err := a.BeaconClient.FetchBlock(ctx, headerData.Slot)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch block at slot %s: %w", headerData.Slot, err)
+	}
+	if err := a.checkFork(headerData.Slot, block.Version); err != nil {
+		return nil, err
+	}
+	if err := beacon.CheckBlockBody(block, headerData); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// checkFork makes sure the fork reported by the beacon API matches the fork
+// schedule of the configured chain at the given slot
+func (a *Application) checkFork(slotStr string, reported string) error {
+	if a.ForkSchedule == nil {
+		return nil
+	}
+
+	slot, err := strconv.ParseUint(slotStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing slot: %w", err)
+	}
+
+	fork, err := a.ForkSchedule.ForkAtSlot(slot)
+	if err != nil {
+		return err
+	}
+	if fork.Name != reported {
+		return fmt.Errorf("beacon API reported fork %q for slot %d, but the fork schedule says %q", reported, slot, fork.Name)
+	}
+	return nil
+}
+
 // displayResults shows a summary of verification results
 func (a *Application) displayResults(results map[string]bool) {
 	if len(results) == 0 {
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+// runChain walks parent_root links back from the anchor block, either to the
+// configured slot or for ChainDepth blocks, and emits a header-chain bundle
+// whose anchor is verified on-chain
+func (a *Application) runChain(ctx context.Context) error {
+	depth := a.Config.Verification.ChainDepth
+	var targetSlot uint64
+	if a.Config.Slot != "" {
+		var err error
+		if targetSlot, err = a.locateSlot(ctx, a.Config.Slot); err != nil {
+			return err
+		}
+	} else if depth <= 0 {
+		return fmt.Errorf("chain mode needs a target slot or a positive chain depth")
+	}
+
+	anchor, next, err := a.fetchHeaderPair(ctx, a.Config.AnchorSlot)
+	if err != nil {
+		return fmt.Errorf("error fetching anchor header: %w", err)
+	}
+
+	headers := []beacon.HeaderData{anchor}
+	for {
+		current := headers[len(headers)-1]
+		slot, err := strconv.ParseUint(current.Slot, 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing slot: %w", err)
+		}
+		if a.Config.Slot != "" && slot <= targetSlot {
+			if slot != targetSlot {
+				return fmt.Errorf("slot %d has no block on the chain of anchor %s; the closest ancestor is at slot %d",
+					targetSlot, anchor.Slot, slot)
+			}
+			break
+		}
+		if a.Config.Slot == "" && len(headers) > depth {
+			break
+		}
+
+		log.Printf("Fetching parent %s of slot %d...", current.ParentRoot, slot)
+		parent, err := a.fetchBlockHeader(ctx, current.ParentRoot)
+		if err != nil {
+			return fmt.Errorf("could not fetch parent of slot %d: %w", slot, err)
+		}
+		if err := parent.VerifyRoot(&current); err != nil {
+			return err
+		}
+		headers = append(headers, parent)
+	}
+	slices.Reverse(headers)
+
+	chain, err := proof.NewHeaderChain(headers, next.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := json.MarshalIndent(chain, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding header chain: %w", err)
+	}
+	log.Printf("Header chain bundle:\n%s", bundle)
+
+	name := fmt.Sprintf("anchor parent_root at slot %s", anchor.Slot)
+	return a.verifyProofs(ctx, map[string]proof.Data{name: chain.AnchorProof})
+}
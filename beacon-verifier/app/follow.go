@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// runFollow subscribes to the node's event stream and verifies the parent of
+// every new head as it is announced, until ctx is cancelled. A failed
+// verification is reported and the next head is awaited.
+func (a *Application) runFollow(ctx context.Context) error {
+	events, err := a.BeaconClient.SubscribeEvents(ctx,
+		beacon.TopicHead, beacon.TopicChainReorg, beacon.TopicFinalizedCheckpoint)
+	if err != nil {
+		return err
+	}
+	log.Println("Following the chain head. Press Ctrl-C to stop.")
+
+	for event := range events {
+		switch e := event.(type) {
+		case *beacon.HeadEvent:
+			if e.ExecutionOptimistic {
+				log.Printf("Skipping optimistic head %s at slot %s", e.Block, e.Slot)
+				continue
+			}
+			if err := a.verifyHeadParent(ctx, e); err != nil && ctx.Err() == nil {
+				log.Printf("Warning: could not verify the parent of head %s at slot %s: %v", e.Block, e.Slot, err)
+			}
+		case *beacon.ChainReorgEvent:
+			log.Printf("Chain reorg at slot %s, depth %s: head %s replaced by %s", e.Slot, e.Depth, e.OldHeadBlock, e.NewHeadBlock)
+		case *beacon.FinalizedCheckpointEvent:
+			log.Printf("Epoch %s finalized at block %s", e.Epoch, e.Block)
+		}
+	}
+	log.Println("Stopped following the chain head.")
+	return nil
+}
+
+// verifyHeadParent verifies the block before a new head, whose root the
+// head's execution block stores in the EIP-4788 buffer
+func (a *Application) verifyHeadParent(ctx context.Context, head *beacon.HeadEvent) error {
+	log.Printf("New head %s at slot %s", head.Block, head.Slot)
+	next, err := a.fetchBlockHeader(ctx, head.Block)
+	if err != nil {
+		return fmt.Errorf("error fetching head: %w", err)
+	}
+	header, err := a.fetchBlockHeader(ctx, next.ParentRoot)
+	if err != nil {
+		return fmt.Errorf("error fetching parent: %w", err)
+	}
+	if err := header.VerifyRoot(&next); err != nil {
+		return err
+	}
+	return a.verifyHeader(ctx, header, next)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+// runHistorical proves the block root of the configured slot, which may be
+// far older than the EIP-4788 buffer, against a recent anchor block through
+// the anchor state's historical_summaries
+func (a *Application) runHistorical(ctx context.Context) error {
+	if a.ForkSchedule == nil {
+		return fmt.Errorf("historical mode needs a known fork schedule to index historical_summaries")
+	}
+
+	t, err := a.fetchAnchoredTarget(ctx, config.ModeHistorical)
+	if err != nil {
+		return err
+	}
+
+	// The summary of a period is appended once its last slot is processed, so
+	// the state at the first slot of the next period still holds its block_roots
+	summarySlot := (t.TargetSlot/beacon.SlotsPerHistoricalRoot + 1) * beacon.SlotsPerHistoricalRoot
+	if t.AnchorSlot < summarySlot {
+		return fmt.Errorf("slot %d is not summarized before slot %d; anchor %d is too recent for a historical proof, use %s mode",
+			t.TargetSlot, summarySlot, t.AnchorSlot, config.ModeAncestor)
+	}
+	summaryIndex, err := a.ForkSchedule.HistoricalSummaryIndex(t.TargetSlot)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Fetching beacon state at slot %d for the block_roots of period %d...", summarySlot, t.TargetSlot/beacon.SlotsPerHistoricalRoot)
+	summaryState, err := a.BeaconClient.FetchState(ctx, strconv.FormatUint(summarySlot, 10))
+	if err != nil {
+		return fmt.Errorf("could not fetch state at slot %d: %w", summarySlot, err)
+	}
+	periodRoots, ok := summaryState.Data["block_roots"].([]any)
+	if !ok {
+		return fmt.Errorf("state at slot %d has no block_roots", summarySlot)
+	}
+
+	schema, anchorState, err := a.fetchStateAt(ctx, t.Anchor)
+	if err != nil {
+		return err
+	}
+
+	proofData, err := proof.GenerateHistoricalProof(t.Anchor, schema, anchorState.Data, periodRoots, t.TargetSlot, summaryIndex, t.Next.Timestamp)
+	if err != nil {
+		return err
+	}
+	if proofData.FieldValue != "0x"+hex.EncodeToString(t.TargetRoot[:]) {
+		return fmt.Errorf("block_roots holds %s for slot %d, but the fetched header hashes to 0x%x",
+			proofData.FieldValue, t.TargetSlot, t.TargetRoot)
+	}
+
+	name := fmt.Sprintf("historical block root at slot %d", t.TargetSlot)
+	return a.verifyProofs(ctx, map[string]proof.Data{name: proofData})
+}
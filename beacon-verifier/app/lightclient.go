@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/lightclient"
+)
+
+// runLightClient bootstraps a light client from the trusted checkpoint,
+// follows the sync committees up to the latest finality update and runs the
+// header proofs on the finalized (or, with Optimistic, the latest attested)
+// header it ends on, so the header no longer comes from the API on faith
+func (a *Application) runLightClient(ctx context.Context) error {
+	if a.Config.Checkpoint == "" {
+		return fmt.Errorf("light client mode needs a trusted checkpoint block root")
+	}
+	if a.Clock == nil {
+		return fmt.Errorf("light client mode needs the chain's timing and fork schedule to verify sync aggregate signatures, "+
+			"and none is known for chain ID %d", a.Config.EthereumNode.ChainID)
+	}
+	trusted, err := hex.DecodeString(strings.TrimPrefix(a.Config.Checkpoint, "0x"))
+	if err != nil || len(trusted) != 32 {
+		return fmt.Errorf("invalid checkpoint block root %q", a.Config.Checkpoint)
+	}
+	var trustedRoot [32]byte
+	copy(trustedRoot[:], trusted)
+
+	log.Printf("Bootstrapping light client from checkpoint %s...", a.Config.Checkpoint)
+	data, err := a.BeaconClient.FetchLightClientBootstrap(ctx, a.Config.Checkpoint)
+	if err != nil {
+		return err
+	}
+	bootstrap, err := lightclient.ParseBootstrap(data)
+	if err != nil {
+		return err
+	}
+	store, err := lightclient.NewStore(trustedRoot, bootstrap, a.Clock)
+	if err != nil {
+		return fmt.Errorf("error verifying light client bootstrap: %w", err)
+	}
+	log.Printf("Bootstrapped at slot %d (sync committee period %d)", store.FinalizedHeader.Slot(), store.Period())
+
+	data, err = a.BeaconClient.FetchLightClientFinalityUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	finality, err := lightclient.ParseUpdate(data)
+	if err != nil {
+		return err
+	}
+	if err := a.syncPeriods(ctx, store, a.Clock.SyncPeriodOf(finality.SignatureSlot)); err != nil {
+		return err
+	}
+	if err := store.Apply(finality); err != nil {
+		return fmt.Errorf("error applying finality update: %w", err)
+	}
+	header := store.FinalizedHeader
+
+	if a.Config.Optimistic {
+		data, err := a.BeaconClient.FetchLightClientOptimisticUpdate(ctx)
+		if err != nil {
+			return err
+		}
+		optimistic, err := lightclient.ParseUpdate(data)
+		if err != nil {
+			return err
+		}
+		if err := store.Apply(optimistic); err != nil {
+			return fmt.Errorf("error applying optimistic update: %w", err)
+		}
+		header = store.OptimisticHeader
+	}
+
+	headerData, err := header.Verified()
+	if err != nil {
+		return err
+	}
+	log.Printf("Light client verified the header at slot %s with root %s", headerData.Slot, headerData.BlockRoot)
+
+	next, err := a.fetchHeader(ctx, headerData, beacon.Next)
+	if err != nil {
+		return fmt.Errorf("error fetching the block after slot %s: %w", headerData.Slot, err)
+	}
+	if err := headerData.VerifyRoot(&next); err != nil {
+		return err
+	}
+	return a.verifyHeader(ctx, headerData, next)
+}
+
+// syncPeriods applies the best update of every sync committee period from
+// the store's period up to, but not including, target
+func (a *Application) syncPeriods(ctx context.Context, store *lightclient.Store, target uint64) error {
+	for period := store.Period(); period < target; {
+		count := min(target-period, beacon.MaxRequestLightClientUpdates)
+		log.Printf("Fetching light client updates for periods %d to %d...", period, period+count-1)
+		updates, err := a.BeaconClient.FetchLightClientUpdates(ctx, period, count)
+		if err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return fmt.Errorf("beacon node has no light client update for period %d", period)
+		}
+
+		for _, data := range updates {
+			update, err := lightclient.ParseUpdate(data)
+			if err != nil {
+				return err
+			}
+			if err := store.Apply(update); err != nil {
+				return fmt.Errorf("error applying update attested at slot %s: %w", update.AttestedHeader.Beacon.Slot, err)
+			}
+			log.Printf("Applied update for period %d; finalized slot %d",
+				a.Clock.SyncPeriodOf(update.AttestedHeader.Slot()), store.FinalizedHeader.Slot())
+		}
+		period += uint64(len(updates))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/locator"
+)
+
+// resolver resolves configured block identifiers against the beacon API,
+// through the quorum when there is one, the chain clock and the execution
+// node
+func (a *Application) resolver() *locator.Resolver {
+	r := &locator.Resolver{
+		Header: a.fetchBlockHeader,
+		Checkpoints: func(ctx context.Context) (*beacon.FinalityCheckpoints, error) {
+			return a.BeaconClient.FetchFinalityCheckpoints(ctx, "head")
+		},
+		Clock: a.Clock,
+	}
+	if a.EthereumClient != nil {
+		r.ExecutionTimestamp = func(ctx context.Context, number uint64) (uint64, error) {
+			header, err := a.EthereumClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return 0, err
+			}
+			return header.Time, nil
+		}
+	}
+	return r
+}
+
+// locateHeader resolves a block identifier such as finalized-32 or
+// el:19426587 to the header of the block it names
+func (a *Application) locateHeader(ctx context.Context, id string) (beacon.HeaderData, error) {
+	loc, err := locator.Parse(id)
+	if err != nil {
+		return beacon.HeaderData{}, err
+	}
+	header, err := a.resolver().Resolve(ctx, loc)
+	if err != nil {
+		return beacon.HeaderData{}, err
+	}
+	if loc.Kind != locator.Slot || loc.Offset != 0 {
+		log.Printf("Block %s resolved to slot %s", loc, header.Slot)
+	}
+	log.Printf("Block time: %s", time.Unix(header.Timestamp, 0).UTC().Format("2006-01-02 15:04:05 UTC"))
+	log.Printf("Block proposer: %s", header.ProposerIndex)
+	return header, nil
+}
+
+// locateSlot resolves a block identifier to the slot it names, which may be
+// empty
+func (a *Application) locateSlot(ctx context.Context, id string) (uint64, error) {
+	loc, err := locator.Parse(id)
+	if err != nil {
+		return 0, err
+	}
+	slot, err := a.resolver().SlotOf(ctx, loc)
+	if err != nil {
+		return 0, fmt.Errorf("error locating %s: %w", loc, err)
+	}
+	if loc.Kind != locator.Slot || loc.Offset != 0 {
+		log.Printf("Block %s resolved to slot %d", loc, slot)
+	}
+	return slot, nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+// runSkipped proves that the configured slot was empty with two block_roots
+// branches from a later anchor state
+func (a *Application) runSkipped(ctx context.Context) error {
+	if a.Config.Slot == "" {
+		return fmt.Errorf("skipped mode needs the slot to prove empty")
+	}
+	slot, err := a.locateSlot(ctx, a.Config.Slot)
+	if err != nil {
+		return err
+	}
+
+	anchor, next, err := a.fetchHeaderPair(ctx, a.Config.AnchorSlot)
+	if err != nil {
+		return fmt.Errorf("error fetching anchor header: %w", err)
+	}
+	anchorSlot, err := strconv.ParseUint(anchor.Slot, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing anchor slot: %w", err)
+	}
+	if slot == 0 {
+		return fmt.Errorf("the genesis slot cannot be skipped")
+	}
+	// Both slot-1 and slot must still be recorded in the anchor state
+	if err := proof.CheckAncestorRange(slot-1, anchorSlot); err != nil {
+		return err
+	}
+	if err := proof.CheckAncestorRange(slot, anchorSlot); err != nil {
+		return err
+	}
+
+	schema, anchorState, err := a.fetchStateAt(ctx, anchor)
+	if err != nil {
+		return err
+	}
+
+	skipped, err := proof.GenerateSkippedSlotProof(anchor, schema, anchorState.Data, slot, next.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := json.MarshalIndent(skipped, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding skipped slot proof: %w", err)
+	}
+	log.Printf("Skipped slot proof:\n%s", bundle)
+
+	return a.verifyProofs(ctx, map[string]proof.Data{
+		fmt.Sprintf("block_roots entry for slot %d", slot-1): skipped.Previous,
+		fmt.Sprintf("block_roots entry for slot %d", slot):   skipped.Skipped,
+	})
+}
@@ -0,0 +1,204 @@
+// Package audit recomputes roots of data served by a beacon node and
+// reports where it disagrees with the roots the node committed to
+package audit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Mismatch is one field whose value or root differs between two sources
+type Mismatch struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// BlockReport is the result of auditing one block
+type BlockReport struct {
+	Slot             string     `json:"slot"`
+	Fork             string     `json:"fork"`
+	HeaderBodyRoot   string     `json:"headerBodyRoot"`
+	ComputedBodyRoot string     `json:"computedBodyRoot"`
+	BlindedBodyRoot  string     `json:"blindedBodyRoot,omitempty"`
+	Mismatches       []Mismatch `json:"mismatches"`
+}
+
+// OK reports whether the block matched its header everywhere
+func (r BlockReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// headerMessageFields are the header fields a block message repeats
+var headerMessageFields = []string{"slot", "proposer_index", "parent_root", "state_root"}
+
+// AuditBlock recomputes the body root of a full block for its fork and
+// compares it and the message fields with the header. When the blinded
+// block is given, every body field is also compared with it, descending
+// into subtrees whose roots differ, to pinpoint the mismatching fields.
+func AuditBlock(headerData beacon.HeaderData, block *beacon.Block, blinded *beacon.Block) (BlockReport, error) {
+	schema, err := beacon.SchemaFor(block.Version)
+	if err != nil {
+		return BlockReport{}, err
+	}
+	body, err := block.Body()
+	if err != nil {
+		return BlockReport{}, err
+	}
+
+	report := BlockReport{
+		Slot:           headerData.Slot,
+		Fork:           schema.Fork,
+		HeaderBodyRoot: strings.ToLower(headerData.BodyRoot),
+		Mismatches:     []Mismatch{},
+	}
+
+	for _, field := range headerMessageFields {
+		expected := headerField(headerData, field)
+		actual := fmt.Sprint(block.Message[field])
+		if !strings.EqualFold(expected, actual) {
+			report.add("message."+field, expected, actual)
+		}
+	}
+
+	bodyRoot, err := ssz.HashTreeRoot(schema.BeaconBlockBody, body)
+	if err != nil {
+		return BlockReport{}, fmt.Errorf("error hashing block body: %w", err)
+	}
+	report.ComputedBodyRoot = hexRoot(bodyRoot)
+	if report.ComputedBodyRoot != report.HeaderBodyRoot {
+		report.add("body_root", report.HeaderBodyRoot, report.ComputedBodyRoot)
+	}
+
+	if blinded == nil {
+		return report, nil
+	}
+	if !strings.EqualFold(blinded.Version, block.Version) {
+		return BlockReport{}, fmt.Errorf("blinded block is %s, full block is %s", blinded.Version, block.Version)
+	}
+	blindedBody, err := blinded.Body()
+	if err != nil {
+		return BlockReport{}, err
+	}
+	blindedRoot, err := ssz.HashTreeRoot(schema.BlindedBeaconBlockBody, blindedBody)
+	if err != nil {
+		return BlockReport{}, fmt.Errorf("error hashing blinded block body: %w", err)
+	}
+	report.BlindedBodyRoot = hexRoot(blindedRoot)
+
+	if err := report.compareBodies(schema, body, blindedBody); err != nil {
+		return BlockReport{}, err
+	}
+	return report, nil
+}
+
+// compareBodies compares the full body field by field with the blinded body,
+// whose execution payload header stands in for the payload
+func (r *BlockReport) compareBodies(schema *beacon.Schema, body, blinded map[string]any) error {
+	for _, f := range schema.BeaconBlockBody.Fields {
+		if f.Name == "execution_payload" {
+			payload, _ := body[f.Name].(map[string]any)
+			header, _ := blinded["execution_payload_header"].(map[string]any)
+			if err := r.comparePayload(schema, payload, header); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.diff(f.Type, []string{"body", f.Name}, blinded[f.Name], body[f.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// comparePayload matches payload fields with the header fields of the same
+// name, and list fields such as transactions with their _root counterparts
+func (r *BlockReport) comparePayload(schema *beacon.Schema, payload, header map[string]any) error {
+	for _, f := range schema.ExecutionPayloadHeader.Fields {
+		path := []string{"body", "execution_payload", f.Name}
+		if _, ok := schema.ExecutionPayload.FieldIndex(f.Name); ok {
+			if err := r.diff(f.Type, path, header[f.Name], payload[f.Name]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := strings.TrimSuffix(f.Name, "_root")
+		i, ok := schema.ExecutionPayload.FieldIndex(name)
+		if !ok {
+			return fmt.Errorf("payload header field %s has no payload counterpart", f.Name)
+		}
+		root, err := ssz.HashTreeRoot(schema.ExecutionPayload.Fields[i].Type, payload[name])
+		if err != nil {
+			return fmt.Errorf("error hashing execution_payload.%s: %w", name, err)
+		}
+		expected := strings.ToLower(fmt.Sprint(header[f.Name]))
+		if hexRoot(root) != expected {
+			r.add(ssz.FormatPath(path[:2])+"."+name, expected, hexRoot(root))
+		}
+	}
+	return nil
+}
+
+// diff records the differing subtrees of two values of type t under path
+func (r *BlockReport) diff(t *ssz.Type, path []string, expected, actual any) error {
+	diffs, err := ssz.Diff(t, expected, actual)
+	if err != nil {
+		return fmt.Errorf("error comparing %s: %w", ssz.FormatPath(path), err)
+	}
+	for _, d := range diffs {
+		full := append(append([]string{}, path...), d...)
+		r.add(ssz.FormatPath(full), describe(t, expected, d), describe(t, actual, d))
+	}
+	return nil
+}
+
+func (r *BlockReport) add(path, expected, actual string) {
+	r.Mismatches = append(r.Mismatches, Mismatch{Path: path, Expected: expected, Actual: actual})
+}
+
+// describe renders the value at a path: basic values and short byte strings
+// as they are, anything else as its hash tree root
+func describe(t *ssz.Type, v any, path []string) string {
+	loc, err := t.Locate(path...)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	value, err := ssz.Value(t, v, path...)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	if loc.Type.IsBasic() || loc.Type.Kind == ssz.KindByteVector && loc.Type.Size <= 32 {
+		return fmt.Sprint(value)
+	}
+	root, err := ssz.HashTreeRoot(loc.Type, value)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	return "root " + hexRoot(root)
+}
+
+// headerField returns a header field in its API string form
+func headerField(headerData beacon.HeaderData, field string) string {
+	switch field {
+	case "slot":
+		return headerData.Slot
+	case "proposer_index":
+		return headerData.ProposerIndex
+	case "parent_root":
+		return headerData.ParentRoot
+	case "state_root":
+		return headerData.StateRoot
+	case "body_root":
+		return headerData.BodyRoot
+	}
+	return ""
+}
+
+func hexRoot(root [32]byte) string {
+	return "0x" + hex.EncodeToString(root[:])
+}
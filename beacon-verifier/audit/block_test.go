@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupBlocks returns a Deneb header with matching full and blinded blocks
+func setupBlocks(t *testing.T) (beacon.HeaderData, *beacon.Block, *beacon.Block) {
+	t.Helper()
+
+	body := ssz.Zero(beacon.BeaconBlockBodyDenebType).(map[string]any)
+	body["graffiti"] = "0x" + strings.Repeat("67", 32)
+	payload := body["execution_payload"].(map[string]any)
+	payload["gas_used"] = "21000"
+	payload["transactions"] = []any{"0x02f870", "0x02f871"}
+	payload["withdrawals"] = []any{map[string]any{
+		"index":           "1",
+		"validator_index": "2",
+		"address":         "0x" + strings.Repeat("aa", 20),
+		"amount":          "3",
+	}}
+
+	header := make(map[string]any)
+	for _, f := range beacon.ExecutionPayloadHeaderDenebType.Fields {
+		if v, ok := payload[f.Name]; ok {
+			header[f.Name] = v
+			continue
+		}
+		name := strings.TrimSuffix(f.Name, "_root")
+		i, _ := beacon.ExecutionPayloadDenebType.FieldIndex(name)
+		root, err := ssz.HashTreeRoot(beacon.ExecutionPayloadDenebType.Fields[i].Type, payload[name])
+		if err != nil {
+			t.Fatalf("HashTreeRoot(%s) error = %v", name, err)
+		}
+		header[f.Name] = "0x" + hex.EncodeToString(root[:])
+	}
+	blindedBody := make(map[string]any)
+	for name, v := range body {
+		blindedBody[name] = v
+	}
+	delete(blindedBody, "execution_payload")
+	blindedBody["execution_payload_header"] = header
+
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyDenebType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	headerData := beacon.HeaderData{
+		Slot:          "123456",
+		ProposerIndex: "42",
+		ParentRoot:    "0x4a81947b35bdc11471fc7b42350427a3b9d2b92bf21d423ded6dcc5c66caad0e",
+		StateRoot:     "0x5bc9a4ef3cf09a315ffbc12872de6cc412a7abb55a5228cc21fbdb5fb797d7a8",
+		BodyRoot:      "0x" + hex.EncodeToString(bodyRoot[:]),
+	}
+
+	message := func(body map[string]any) map[string]any {
+		return map[string]any{
+			"slot":           headerData.Slot,
+			"proposer_index": headerData.ProposerIndex,
+			"parent_root":    headerData.ParentRoot,
+			"state_root":     headerData.StateRoot,
+			"body":           body,
+		}
+	}
+	full := &beacon.Block{Version: "deneb", Message: message(body)}
+	blinded := &beacon.Block{Version: "deneb", Message: message(blindedBody)}
+	return headerData, full, blinded
+}
+
+func mismatchPaths(report BlockReport) []string {
+	paths := make([]string, len(report.Mismatches))
+	for i, m := range report.Mismatches {
+		paths[i] = m.Path
+	}
+	return paths
+}
+
+func TestAuditBlockConsistent(t *testing.T) {
+	headerData, full, blinded := setupBlocks(t)
+
+	report, err := AuditBlock(headerData, full, blinded)
+	if err != nil {
+		t.Fatalf("AuditBlock() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("AuditBlock() mismatches = %v", report.Mismatches)
+	}
+	if report.BlindedBodyRoot != report.ComputedBodyRoot {
+		t.Errorf("blinded body root %s differs from full body root %s", report.BlindedBodyRoot, report.ComputedBodyRoot)
+	}
+}
+
+func TestAuditBlockMismatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(full *beacon.Block)
+		want   []string
+	}{
+		{
+			name: "Payload field",
+			tamper: func(full *beacon.Block) {
+				body, _ := full.Body()
+				body["execution_payload"].(map[string]any)["gas_used"] = "21001"
+			},
+			want: []string{"body_root", "body.execution_payload.gas_used"},
+		},
+		{
+			name: "Transaction",
+			tamper: func(full *beacon.Block) {
+				body, _ := full.Body()
+				body["execution_payload"].(map[string]any)["transactions"] = []any{"0x02f870"}
+			},
+			want: []string{"body_root", "body.execution_payload.transactions"},
+		},
+		{
+			name: "Withdrawal amount",
+			tamper: func(full *beacon.Block) {
+				body, _ := full.Body()
+				payload := body["execution_payload"].(map[string]any)
+				payload["withdrawals"] = []any{map[string]any{
+					"index":           "1",
+					"validator_index": "2",
+					"address":         "0x" + strings.Repeat("aa", 20),
+					"amount":          "4",
+				}}
+			},
+			want: []string{"body_root", "body.execution_payload.withdrawals"},
+		},
+		{
+			name: "Body field",
+			tamper: func(full *beacon.Block) {
+				body, _ := full.Body()
+				body["graffiti"] = "0x" + strings.Repeat("00", 32)
+			},
+			want: []string{"body_root", "body.graffiti"},
+		},
+		{
+			name: "Message field",
+			tamper: func(full *beacon.Block) {
+				full.Message["proposer_index"] = "43"
+			},
+			want: []string{"message.proposer_index"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headerData, full, blinded := setupBlocks(t)
+			// Give the full block its own body so tampering leaves the blinded one intact
+			body, _ := full.Body()
+			copied := make(map[string]any, len(body))
+			for k, v := range body {
+				copied[k] = v
+			}
+			payload := make(map[string]any)
+			for k, v := range body["execution_payload"].(map[string]any) {
+				payload[k] = v
+			}
+			copied["execution_payload"] = payload
+			full.Message["body"] = copied
+			tt.tamper(full)
+
+			report, err := AuditBlock(headerData, full, blinded)
+			if err != nil {
+				t.Fatalf("AuditBlock() error = %v", err)
+			}
+			got := mismatchPaths(report)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("mismatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditBlockWithoutBlinded(t *testing.T) {
+	headerData, full, _ := setupBlocks(t)
+	headerData.BodyRoot = "0x" + strings.Repeat("00", 32)
+
+	report, err := AuditBlock(headerData, full, nil)
+	if err != nil {
+		t.Fatalf("AuditBlock() error = %v", err)
+	}
+	if got := mismatchPaths(report); len(got) != 1 || got[0] != "body_root" {
+		t.Errorf("mismatches = %v, want [body_root]", got)
+	}
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// StateReport is the result of auditing the post-state of one block
+type StateReport struct {
+	Slot              string        `json:"slot"`
+	Fork              string        `json:"fork"`
+	Size              int           `json:"size"`
+	HeaderStateRoot   string        `json:"headerStateRoot"`
+	ComputedStateRoot string        `json:"computedStateRoot"`
+	Segments          ssz.TreeStats `json:"segments"`
+	Mismatches        []Mismatch    `json:"mismatches"`
+}
+
+// OK reports whether the state matched its header everywhere
+func (r StateReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// latestHeaderFields are the header fields the post-state repeats in
+// latest_block_header; its state_root stays zero until the next slot
+var latestHeaderFields = []string{"slot", "proposer_index", "parent_root", "body_root"}
+
+// StateAuditor recomputes state roots from serialized states. It keeps the
+// tree of the last state it audited, so auditing the state of an adjacent
+// slot next only rehashes the parts that changed in between.
+type StateAuditor struct {
+	prev *ssz.Tree
+}
+
+// Audit recomputes the root of the SSZ-encoded post-state of the block with
+// the given header and compares it with the header's state_root. It also
+// checks that the state's slot and latest_block_header match the header.
+func (a *StateAuditor) Audit(headerData beacon.HeaderData, fork string, data []byte) (StateReport, error) {
+	schema, err := beacon.SchemaFor(fork)
+	if err != nil {
+		return StateReport{}, err
+	}
+
+	// Build the new tree before releasing the previous one; peak memory is
+	// two serialized states
+	tree, err := ssz.NewTree(schema.BeaconState, data, a.prev)
+	if err != nil {
+		return StateReport{}, fmt.Errorf("error decoding state: %w", err)
+	}
+	a.prev = tree
+
+	report := StateReport{
+		Slot:              headerData.Slot,
+		Fork:              schema.Fork,
+		Size:              len(data),
+		HeaderStateRoot:   strings.ToLower(headerData.StateRoot),
+		ComputedStateRoot: hexRoot(tree.Root()),
+		Segments:          tree.Stats(),
+		Mismatches:        []Mismatch{},
+	}
+	if report.ComputedStateRoot != report.HeaderStateRoot {
+		report.add("state_root", report.HeaderStateRoot, report.ComputedStateRoot)
+	}
+
+	slot, err := fieldValue(tree, "slot")
+	if err != nil {
+		return StateReport{}, err
+	}
+	if slot != headerData.Slot {
+		report.add("state.slot", headerData.Slot, fmt.Sprint(slot))
+	}
+
+	latest, err := fieldValue(tree, "latest_block_header")
+	if err != nil {
+		return StateReport{}, err
+	}
+	latestHeader, _ := latest.(map[string]any)
+	for _, field := range latestHeaderFields {
+		expected := headerField(headerData, field)
+		actual := fmt.Sprint(latestHeader[field])
+		if !strings.EqualFold(expected, actual) {
+			report.add("state.latest_block_header."+field, expected, actual)
+		}
+	}
+	if zero := hexRoot([32]byte{}); latestHeader["state_root"] != zero {
+		report.add("state.latest_block_header.state_root", zero, fmt.Sprint(latestHeader["state_root"]))
+	}
+	return report, nil
+}
+
+func (r *StateReport) add(path, expected, actual string) {
+	r.Mismatches = append(r.Mismatches, Mismatch{Path: path, Expected: expected, Actual: actual})
+}
+
+// fieldValue decodes one top-level field of a state tree
+func fieldValue(tree *ssz.Tree, name string) (any, error) {
+	field, err := tree.Field(name)
+	if err != nil {
+		return nil, err
+	}
+	value, err := field.Value()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding state.%s: %w", name, err)
+	}
+	return value, nil
+}
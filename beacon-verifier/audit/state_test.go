@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupState returns a Deneb post-state for a block at slot, serialized, and
+// the header committing to it
+func setupState(t *testing.T, slot uint64, balances int) (beacon.HeaderData, []byte) {
+	t.Helper()
+
+	headerData := beacon.HeaderData{
+		Slot:          strconv.FormatUint(slot, 10),
+		ProposerIndex: "42",
+		ParentRoot:    "0x4a81947b35bdc11471fc7b42350427a3b9d2b92bf21d423ded6dcc5c66caad0e",
+		BodyRoot:      "0x67df26e0c9f5de4fe7b3f66f3591f84a9cf6e8cda7f5b3f23db5c3967a505c31",
+	}
+
+	state := ssz.Zero(beacon.BeaconStateDenebType).(map[string]any)
+	state["slot"] = headerData.Slot
+	state["latest_block_header"] = map[string]any{
+		"slot":           headerData.Slot,
+		"proposer_index": headerData.ProposerIndex,
+		"parent_root":    headerData.ParentRoot,
+		"state_root":     "0x" + strings.Repeat("00", 32),
+		"body_root":      headerData.BodyRoot,
+	}
+	items := make([]any, balances)
+	for i := range items {
+		items[i] = strconv.FormatUint(32000000000+slot+uint64(i%3), 10)
+	}
+	state["balances"] = items
+
+	data, err := ssz.Marshal(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	root, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	headerData.StateRoot = "0x" + hex.EncodeToString(root[:])
+	return headerData, data
+}
+
+func TestStateAuditorAdjacentSlots(t *testing.T) {
+	var auditor StateAuditor
+
+	headerData, data := setupState(t, 100, 3000)
+	first, err := auditor.Audit(headerData, beacon.Deneb, data)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if !first.OK() {
+		t.Errorf("Audit() mismatches = %v", first.Mismatches)
+	}
+
+	headerData, data = setupState(t, 101, 3000)
+	next, err := auditor.Audit(headerData, beacon.Deneb, data)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if !next.OK() {
+		t.Errorf("Audit() mismatches = %v", next.Mismatches)
+	}
+	// Only the balances changed; the roots vectors and mixes are reused
+	if next.Segments.Reused == 0 || next.Segments.Hashed >= first.Segments.Hashed {
+		t.Errorf("adjacent Segments = %+v, first %+v", next.Segments, first.Segments)
+	}
+}
+
+func TestStateAuditorMismatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(headerData *beacon.HeaderData)
+		want   []string
+	}{
+		{
+			name: "State root",
+			tamper: func(headerData *beacon.HeaderData) {
+				headerData.StateRoot = "0x" + strings.Repeat("11", 32)
+			},
+			want: []string{"state_root"},
+		},
+		{
+			name: "Body root",
+			tamper: func(headerData *beacon.HeaderData) {
+				headerData.BodyRoot = "0x" + strings.Repeat("22", 32)
+			},
+			want: []string{"state.latest_block_header.body_root"},
+		},
+		{
+			name: "Slot",
+			tamper: func(headerData *beacon.HeaderData) {
+				headerData.Slot = "101"
+			},
+			want: []string{"state.slot", "state.latest_block_header.slot"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headerData, data := setupState(t, 100, 10)
+			tt.tamper(&headerData)
+
+			var auditor StateAuditor
+			report, err := auditor.Audit(headerData, beacon.Deneb, data)
+			if err != nil {
+				t.Fatalf("Audit() error = %v", err)
+			}
+			var got []string
+			for _, m := range report.Mismatches {
+				got = append(got, m.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("mismatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateAuditorInvalidState(t *testing.T) {
+	headerData, data := setupState(t, 100, 10)
+
+	var auditor StateAuditor
+	if _, err := auditor.Audit(headerData, beacon.Deneb, data[:len(data)/2]); err == nil {
+		t.Error("Audit() expected error for a truncated state, got nil")
+	}
+	if _, err := auditor.Audit(headerData, "bellatrix", data); err == nil {
+		t.Error("Audit() expected error for an unsupported fork, got nil")
+	}
+}
@@ -1,11 +1,22 @@
 package beacon
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"maps"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
 )
 
 // APIResponse represents the top-level structure of a Beacon API response
@@ -20,6 +31,7 @@ type APIResponse struct {
 				StateRoot     string `json:"state_root"`
 				BodyRoot      string `json:"body_root"`
 			} `json:"message"`
+			Signature string `json:"signature"`
 		} `json:"header"`
 	} `json:"data"`
 }
@@ -37,9 +49,76 @@ type BlockResponse struct {
 	} `json:"data"`
 }
 
-// Client provides methods to interact with the Beacon API
+// Block is a full signed beacon block as returned by /eth/v2/beacon/blocks.
+// Message keeps the generic JSON shape so it can be merkleized against the
+// container layout of its fork.
+type Block struct {
+	Version   string
+	Message   map[string]any
+	Signature string
+}
+
+// blockEnvelope is the wire format of a full block response
+type blockEnvelope struct {
+	Version string `json:"version"`
+	Data    struct {
+		Message   map[string]any `json:"message"`
+		Signature string         `json:"signature"`
+	} `json:"data"`
+}
+
+// Body returns the body of the block message
+func (b *Block) Body() (map[string]any, error) {
+	body, ok := b.Message["body"].(map[string]any)
+	if !ok {
+		return nil, errors.New("block message has no body")
+	}
+	return body, nil
+}
+
+// State is a full beacon state as returned by /eth/v2/debug/beacon/states,
+// kept in the generic JSON shape like Block
+type State struct {
+	Version string
+	Data    map[string]any
+}
+
+// stateEnvelope is the wire format of a full state response
+type stateEnvelope struct {
+	Version string         `json:"version"`
+	Data    map[string]any `json:"data"`
+}
+
+// Client provides methods to interact with the Beacon API. Every method
+// takes a context and stops when it is cancelled.
 type Client struct {
 	BaseURL string
+	// HTTPClient sends the requests. Clients share DefaultHTTPClient unless
+	// it is replaced, so connections to a node are reused across them.
+	HTTPClient *http.Client
+	// Timeout bounds each request on top of the context's own deadline;
+	// zero leaves requests bounded by the context alone. State downloads,
+	// which run to hundreds of megabytes, are bounded only until their
+	// response headers arrive.
+	Timeout time.Duration
+	// Retry is applied to every request; the zero policy never retries
+	Retry RetryPolicy
+	// Pool, when set, replaces BaseURL with the healthiest of several nodes
+	Pool *Pool
+
+	specMu sync.Mutex
+	spec   *ChainSpec
+}
+
+// DefaultHTTPClient is the transport shared by clients that do not set
+// their own. It keeps more idle connections per host than the standard
+// transport, since the tool makes many small requests to one node.
+var DefaultHTTPClient = &http.Client{Transport: defaultTransport()}
+
+func defaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 16
+	return transport
 }
 
 // Direction represents the direction to fetch the block header
@@ -54,34 +133,163 @@ const (
 // NewClient creates a new Beacon API client
 func NewClient(baseURL string) *Client {
 	return &Client{
-		BaseURL: baseURL,
+		BaseURL:    baseURL,
+		HTTPClient: DefaultHTTPClient,
+		Retry:      DefaultRetryPolicy(),
 	}
 }
 
-// FetchBlockHeader fetches a beacon block header from the API
-func (c *Client) FetchBlockHeader(slot string) (HeaderData, error) {
-	return c.fetchBlockData(slot)
+// get sends a GET request for path with an optional Accept header
+func (c *Client) get(ctx context.Context, path string, accept string, headersOnly bool) (*http.Response, error) {
+	header := make(http.Header)
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	return c.request(ctx, path, header, headersOnly)
 }
 
-// fetchBlockData fetches beacon block header and timestamp from API
-func (c *Client) fetchBlockData(slot string) (HeaderData, error) {
+// request sends a GET request for path, retrying under the client's policy.
+// The response of the last attempt is returned whatever its status. With a
+// Pool a failed attempt moves on to the next healthy endpoint at once; the
+// policy's backoff applies once every endpoint has failed.
+func (c *Client) request(ctx context.Context, path string, header http.Header, headersOnly bool) (*http.Response, error) {
+	var tried []*endpoint
+	for n := 1; ; {
+		url, ep := c.BaseURL+path, (*endpoint)(nil)
+		if c.Pool != nil {
+			ep = c.Pool.pick(tried)
+			url = ep.url + path
+		}
+		start := time.Now()
+		resp, err := c.attempt(ctx, url, header, headersOnly)
+		if ep != nil && ctx.Err() == nil {
+			failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+			c.Pool.observe(ep, time.Since(start), failed)
+			if failed && c.Pool.untried(append(tried, ep)) {
+				log.Printf("Beacon endpoint %s failed, trying another", ep.url)
+				tried = append(tried, ep)
+				discard(resp)
+				continue
+			}
+		}
+		if n >= c.Retry.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			delay = c.Retry.backoff(n)
+		case c.Retry.retryable(resp.StatusCode):
+			delay = c.Retry.backoff(n)
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > c.Retry.MaxDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			discard(resp)
+		default:
+			return resp, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &TransportError{URL: url, Err: ctx.Err()}
+		}
+		n++
+		tried = nil
+	}
+}
+
+// discard closes a response that will not be read, draining a little of it
+// so the connection can be reused
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.CopyN(io.Discard, resp.Body, maxErrorBody)
+	resp.Body.Close()
+}
+
+// attempt sends a single GET request for url. Unless headersOnly is set, the
+// client's Timeout covers reading the body too; the timer is released when
+// the body is closed.
+func (c *Client) attempt(ctx context.Context, url string, header http.Header, headersOnly bool) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	var timer *time.Timer
+	timeout := fmt.Errorf("request timed out after %v: %w", c.Timeout, context.DeadlineExceeded)
+	if c.Timeout > 0 {
+		timer = time.AfterFunc(c.Timeout, func() { cancel(timeout) })
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel(nil)
+		return nil, err
+	}
+	maps.Copy(req.Header, header)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if cause := context.Cause(ctx); cause == timeout {
+			err = cause
+		}
+		cancel(nil)
+		return nil, &TransportError{URL: url, Err: err}
+	}
+	if headersOnly && timer != nil {
+		timer.Stop()
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: func() { cancel(nil) }}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's context once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// FetchBlockHeader fetches a beacon block header from the API with the
+// execution timestamp of its block. The block's body is not checked against
+// the header's body_root; callers that use the body run CheckBlockBody.
+func (c *Client) FetchBlockHeader(ctx context.Context, slot string) (HeaderData, error) {
+	return c.fetchBlockData(ctx, slot)
+}
+
+// FetchHeader fetches a beacon block header alone, without downloading its
+// block, so Timestamp is left zero
+func (c *Client) FetchHeader(ctx context.Context, blockID string) (HeaderData, error) {
 	var headerData HeaderData
 
-	// Fetch the header data
-	apiURL := fmt.Sprintf("%s/eth/v1/beacon/headers/%s", c.BaseURL, slot)
-	resp, err := http.Get(apiURL)
+	resp, err := c.get(ctx, "/eth/v1/beacon/headers/"+blockID, "", false)
 	if err != nil {
 		return headerData, fmt.Errorf("error fetching header: %w", err)
 	}
 	defer resp.Body.Close()
+	apiURL := resp.Request.URL.String()
 
-	if resp.StatusCode != http.StatusOK {
-		return headerData, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	if err := checkResponse(resp, apiURL, blockNotFound(blockID)); err != nil {
+		return headerData, fmt.Errorf("error fetching header: %w", err)
 	}
 
 	var apiResp APIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return headerData, fmt.Errorf("error decoding API response: %w", err)
+		return headerData, &DecodeError{URL: apiURL, Err: err}
 	}
 
 	headerData.Slot = apiResp.Data.Header.Message.Slot
@@ -89,34 +297,320 @@ func (c *Client) fetchBlockData(slot string) (HeaderData, error) {
 	headerData.ParentRoot = apiResp.Data.Header.Message.ParentRoot
 	headerData.StateRoot = apiResp.Data.Header.Message.StateRoot
 	headerData.BodyRoot = apiResp.Data.Header.Message.BodyRoot
+	headerData.Signature = apiResp.Data.Header.Signature
 
 	// Add block root if available
 	if apiResp.Data.Root != "" {
 		headerData.BlockRoot = apiResp.Data.Root
 	}
+	return headerData, nil
+}
+
+// Checkpoint is an epoch and the root of the block at its start
+type Checkpoint struct {
+	Epoch string `json:"epoch"`
+	Root  string `json:"root"`
+}
+
+// FinalityCheckpoints are the justified and finalized checkpoints recorded in
+// a state
+type FinalityCheckpoints struct {
+	PreviousJustified Checkpoint `json:"previous_justified"`
+	CurrentJustified  Checkpoint `json:"current_justified"`
+	Finalized         Checkpoint `json:"finalized"`
+}
+
+// FetchFinalityCheckpoints fetches the finality checkpoints of a state
+func (c *Client) FetchFinalityCheckpoints(ctx context.Context, stateID string) (*FinalityCheckpoints, error) {
+	var envelope struct {
+		Data *FinalityCheckpoints `json:"data"`
+	}
+	if err := c.fetchJSON(ctx, "/eth/v1/beacon/states/"+stateID+"/finality_checkpoints", &envelope); err != nil {
+		return nil, fmt.Errorf("error fetching finality checkpoints: %w", err)
+	}
+	if envelope.Data == nil {
+		return nil, errors.New("finality checkpoints response has no data")
+	}
+	return envelope.Data, nil
+}
+
+// FetchBlock fetches a full beacon block from the API
+func (c *Client) FetchBlock(ctx context.Context, blockID string) (*Block, error) {
+	block, _, err := c.fetchBlock(ctx, "/eth/v2/beacon/blocks/"+blockID, false, blockNotFound(blockID))
+	return block, err
+}
+
+// FetchBlindedBlock fetches a block with its execution payload replaced by
+// the payload header. The body comes from a different code path in most
+// clients, which makes it a useful second opinion on the full block.
+func (c *Client) FetchBlindedBlock(ctx context.Context, blockID string) (*Block, error) {
+	block, _, err := c.fetchBlock(ctx, "/eth/v1/beacon/blinded_blocks/"+blockID, true, blockNotFound(blockID))
+	return block, err
+}
 
-	// Fetch the block to get the timestamp
-	blockURL := fmt.Sprintf("%s/eth/v2/beacon/blocks/%s", c.BaseURL, slot)
-	blockResp, err := http.Get(blockURL)
+// blockNotFound is what a 404 for a block ID means: a slot or name without
+// a block was skipped, but a missing root is a block the node has pruned or
+// not yet imported
+func blockNotFound(blockID string) error {
+	if strings.HasPrefix(blockID, "0x") {
+		return ErrNotFound
+	}
+	return ErrSlotEmpty
+}
+
+// fetchBlock fetches and decodes a signed block, in SSZ when the node serves
+// it, and returns the URL it came from. A 404 maps to notFound.
+func (c *Client) fetchBlock(ctx context.Context, path string, blinded bool, notFound error) (*Block, string, error) {
+	resp, err := c.getPreferSSZ(ctx, path, false)
 	if err != nil {
-		return headerData, fmt.Errorf("error fetching block data: %w", err)
+		return nil, "", fmt.Errorf("error fetching block: %w", err)
 	}
-	defer blockResp.Body.Close()
+	defer resp.Body.Close()
+	blockURL := resp.Request.URL.String()
 
-	if blockResp.StatusCode == http.StatusOK {
-		var blockData BlockResponse
-		if err := json.NewDecoder(blockResp.Body).Decode(&blockData); err != nil {
-			return HeaderData{}, fmt.Errorf("error decoding block response: %w", err)
+	if err := checkResponse(resp, blockURL, notFound); err != nil {
+		return nil, blockURL, err
+	}
+
+	if isSSZ(resp) {
+		layout := func(s *Schema) *ssz.Type { return s.SignedBeaconBlock }
+		if blinded {
+			layout = func(s *Schema) *ssz.Type { return s.SignedBlindedBeaconBlock }
 		}
-		// Extract timestamp
-		timestampStr := blockData.Data.Message.Body.ExecutionPayload.Timestamp
-		if timestampStr != "" {
-			headerData.Timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
-			if err != nil {
-				return HeaderData{}, fmt.Errorf("error parsing timestamp: %w", err)
-			}
-			return headerData, nil
+		signed, version, err := decodeSSZ(resp, blockURL, layout)
+		if err != nil {
+			return nil, blockURL, err
 		}
+		message, _ := signed["message"].(map[string]any)
+		signature, _ := signed["signature"].(string)
+		return &Block{Version: version, Message: message, Signature: signature}, blockURL, nil
+	}
+
+	var envelope blockEnvelope
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil {
+		return nil, blockURL, &DecodeError{URL: blockURL, Err: err}
+	}
+	if envelope.Data.Message == nil {
+		return nil, blockURL, &DecodeError{URL: blockURL, Err: errors.New("block response has no message")}
+	}
+
+	return &Block{
+		Version:   envelope.Version,
+		Message:   envelope.Data.Message,
+		Signature: envelope.Data.Signature,
+	}, blockURL, nil
+}
+
+// FetchState fetches a full beacon state from the debug API. States are
+// large; stateID should be a slot or state root the node still holds.
+func (c *Client) FetchState(ctx context.Context, stateID string) (*State, error) {
+	resp, err := c.getPreferSSZ(ctx, "/eth/v2/debug/beacon/states/"+stateID, true)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching state: %w", err)
+	}
+	defer resp.Body.Close()
+	stateURL := resp.Request.URL.String()
+
+	if err := checkResponse(resp, stateURL, ErrNotFound); err != nil {
+		return nil, err
+	}
+
+	if isSSZ(resp) {
+		data, version, err := decodeSSZ(resp, stateURL, func(s *Schema) *ssz.Type { return s.BeaconState })
+		if err != nil {
+			return nil, err
+		}
+		return &State{Version: version, Data: data}, nil
+	}
+
+	var envelope stateEnvelope
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil {
+		return nil, &DecodeError{URL: stateURL, Err: err}
+	}
+	if envelope.Data == nil {
+		return nil, &DecodeError{URL: stateURL, Err: errors.New("state response has no data")}
+	}
+
+	return &State{Version: envelope.Version, Data: envelope.Data}, nil
+}
+
+// Media types of beacon API responses
+const (
+	mediaSSZ  = "application/octet-stream"
+	mediaJSON = "application/json"
+	// acceptSSZ asks for SSZ and accepts JSON from nodes without it
+	acceptSSZ = mediaSSZ + ";q=1, " + mediaJSON + ";q=0.9"
+)
+
+// getPreferSSZ requests path preferring SSZ. Nodes that ignore the
+// preference answer in JSON; those that refuse it with 406 are asked again
+// for JSON, and so are those answering in SSZ for a fork without a known
+// layout, such as the pre-Capella forks.
+func (c *Client) getPreferSSZ(ctx context.Context, path string, headersOnly bool) (*http.Response, error) {
+	resp, err := c.get(ctx, path, acceptSSZ, headersOnly)
+	if err != nil || !needsJSON(resp) {
+		return resp, err
+	}
+	discard(resp)
+	return c.get(ctx, path, mediaJSON, headersOnly)
+}
+
+// needsJSON reports whether a response to an SSZ request has to be asked for
+// again in JSON
+func needsJSON(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusNotAcceptable {
+		return true
+	}
+	if resp.StatusCode != http.StatusOK || !isSSZ(resp) {
+		return false
+	}
+	version := resp.Header.Get("Eth-Consensus-Version")
+	if version == "" {
+		return false
+	}
+	_, err := SchemaFor(version)
+	return err != nil
+}
+
+// isSSZ reports whether a response body is SSZ
+func isSSZ(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), mediaSSZ)
+}
+
+// decodeSSZ reads an SSZ response into the generic JSON shape, using the
+// layout of the fork named by its Eth-Consensus-Version header
+func decodeSSZ(resp *http.Response, url string, layout func(*Schema) *ssz.Type) (map[string]any, string, error) {
+	version := resp.Header.Get("Eth-Consensus-Version")
+	if version == "" {
+		return nil, "", &DecodeError{URL: url, Err: errors.New("no Eth-Consensus-Version header")}
+	}
+	schema, err := SchemaFor(version)
+	if err != nil {
+		return nil, "", &DecodeError{URL: url, Err: err}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, max(resp.ContentLength, 0)))
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, "", &TransportError{URL: url, Err: err}
+	}
+	value, err := ssz.Unmarshal(layout(schema), buf.Bytes())
+	if err != nil {
+		return nil, "", &DecodeError{URL: url, Err: err}
+	}
+	data, ok := value.(map[string]any)
+	if !ok {
+		return nil, "", &DecodeError{URL: url, Err: errors.New("SSZ response is not a container")}
+	}
+	return data, strings.ToLower(version), nil
+}
+
+// FetchStateSSZ fetches a beacon state as raw SSZ bytes together with the
+// fork named in the Eth-Consensus-Version header. The bytes are read into a
+// single buffer sized from Content-Length, since mainnet states run to
+// hundreds of megabytes.
+func (c *Client) FetchStateSSZ(ctx context.Context, stateID string) ([]byte, string, error) {
+	resp, err := c.get(ctx, "/eth/v2/debug/beacon/states/"+stateID, mediaSSZ, true)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching state: %w", err)
+	}
+	defer resp.Body.Close()
+	stateURL := resp.Request.URL.String()
+
+	if err := checkResponse(resp, stateURL, ErrNotFound); err != nil {
+		return nil, "", err
+	}
+	if contentType := resp.Header.Get("Content-Type"); !isSSZ(resp) {
+		return nil, "", &DecodeError{URL: stateURL, Err: fmt.Errorf("content type %q, expected SSZ", contentType)}
+	}
+	version := resp.Header.Get("Eth-Consensus-Version")
+	if version == "" {
+		return nil, "", &DecodeError{URL: stateURL, Err: errors.New("no Eth-Consensus-Version header")}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, max(resp.ContentLength, 0)))
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, "", fmt.Errorf("error reading state: %w", err)
+	}
+	return buf.Bytes(), version, nil
+}
+
+// fetchBlockData fetches beacon block header and timestamp from API. A
+// skipped slot fails with an error matching ErrSlotEmpty; a block root the
+// node does not hold, or a block it lost after serving the header, with
+// ErrNotFound.
+func (c *Client) fetchBlockData(ctx context.Context, slot string) (HeaderData, error) {
+	headerData, err := c.FetchHeader(ctx, slot)
+	if err != nil {
+		return HeaderData{}, err
+	}
+
+	// Fetch the block to get the timestamp. It is fetched by the header's
+	// own root: by slot or name, another node of the pool or a moved head
+	// could return a different block.
+	root, err := headerData.HashTreeRoot()
+	if err != nil {
+		return HeaderData{}, fmt.Errorf("invalid header: %w", err)
+	}
+	block, blockURL, err := c.fetchBlock(ctx, "/eth/v2/beacon/blocks/0x"+hex.EncodeToString(root[:]), false, ErrNotFound)
+	if err != nil {
+		return HeaderData{}, fmt.Errorf("error fetching block data: %w", err)
+	}
+	if err := checkBlockHeader(block, headerData); err != nil {
+		return HeaderData{}, &DecodeError{URL: blockURL, Err: err}
+	}
+	body, err := block.Body()
+	if err != nil {
+		return HeaderData{}, &DecodeError{URL: blockURL, Err: err}
+	}
+	payload, _ := body["execution_payload"].(map[string]any)
+	timestampStr, _ := payload["timestamp"].(string)
+	if timestampStr == "" {
+		return HeaderData{}, &DecodeError{URL: blockURL, Err: errors.New("block has no execution payload timestamp")}
+	}
+	headerData.Timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return HeaderData{}, &DecodeError{URL: blockURL, Err: fmt.Errorf("error parsing timestamp: %w", err)}
+	}
+	return headerData, nil
+}
+
+// checkBlockHeader checks the header fields a block carries outside its
+// body, which is cheap enough for every header fetch
+func checkBlockHeader(block *Block, header HeaderData) error {
+	for name, want := range map[string]string{
+		"slot":           header.Slot,
+		"proposer_index": header.ProposerIndex,
+		"parent_root":    header.ParentRoot,
+		"state_root":     header.StateRoot,
+	} {
+		if got := fmt.Sprint(block.Message[name]); !strings.EqualFold(got, want) {
+			return fmt.Errorf("block has %s %s, not the header's %s", name, got, want)
+		}
+	}
+	return nil
+}
+
+// CheckBlockBody checks that a block's body hashes to the header's body_root,
+// so proofs built from the body hold against the header
+func CheckBlockBody(block *Block, header HeaderData) error {
+	schema, err := SchemaFor(block.Version)
+	if err != nil {
+		return err
+	}
+	body, err := block.Body()
+	if err != nil {
+		return err
+	}
+	bodyRoot, err := ssz.HashTreeRoot(schema.BeaconBlockBody, body)
+	if err != nil {
+		return fmt.Errorf("error hashing block body: %w", err)
+	}
+	if got := "0x" + hex.EncodeToString(bodyRoot[:]); !strings.EqualFold(got, header.BodyRoot) {
+		return fmt.Errorf("block body at slot %s hashes to %s, not the header's body_root %s", header.Slot, got, header.BodyRoot)
 	}
-	return HeaderData{}, errors.New("block data not found")
+	return nil
 }
@@ -1,11 +1,17 @@
 package beacon
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
 )
 
 // setupTestServer creates a test HTTP server that returns predefined responses
@@ -16,7 +22,7 @@ func setupTestServer(t *testing.T, headerHandler func(w http.ResponseWriter, r *
 		switch {
 		case r.URL.Path == "/eth/v1/beacon/headers/head" || r.URL.Path == "/eth/v1/beacon/headers/123456":
 			headerHandler(w, r)
-		case r.URL.Path == "/eth/v2/beacon/blocks/head" || r.URL.Path == "/eth/v2/beacon/blocks/123456":
+		case strings.HasPrefix(r.URL.Path, "/eth/v2/beacon/blocks/"):
 			blockHandler(w, r)
 		default:
 			http.NotFound(w, r)
@@ -43,10 +49,21 @@ func createValidHeaderResponse() APIResponse {
 }
 
 // Helper function to create a valid block response
-func createValidBlockResponse() BlockResponse {
-	var resp BlockResponse
-	resp.Data.Message.Body.ExecutionPayload.Timestamp = "1651234567"
-	return resp
+func createValidBlockResponse() map[string]any {
+	return blockResponseFor(createValidHeaderResponse(), "1651234567")
+}
+
+// blockResponseFor returns a block of no known fork whose header fields are
+// those of a header response, so it hashes to the same root
+func blockResponseFor(header APIResponse, timestamp string) map[string]any {
+	message := header.Data.Header.Message
+	return map[string]any{"data": map[string]any{"message": map[string]any{
+		"slot":           message.Slot,
+		"proposer_index": message.ProposerIndex,
+		"parent_root":    message.ParentRoot,
+		"state_root":     message.StateRoot,
+		"body":           map[string]any{"execution_payload": map[string]any{"timestamp": timestamp}},
+	}}}
 }
 
 func TestNewClient(t *testing.T) {
@@ -74,7 +91,7 @@ func TestFetchBlockHeader_Success(t *testing.T) {
 	)
 
 	client := NewClient(server.URL)
-	headerData, err := client.FetchBlockHeader("123456")
+	headerData, err := client.FetchBlockHeader(context.Background(), "123456")
 
 	// Verify no error occurred
 	if err != nil {
@@ -112,7 +129,7 @@ func TestFetchBlockHeader_HeaderRequestFails(t *testing.T) {
 	)
 
 	client := NewClient(server.URL)
-	_, err := client.FetchBlockHeader("123456")
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
 
 	// Verify an error was returned
 	if err == nil {
@@ -135,7 +152,7 @@ func TestFetchBlockHeader_InvalidHeaderJson(t *testing.T) {
 	)
 
 	client := NewClient(server.URL)
-	_, err := client.FetchBlockHeader("123456")
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
 
 	// Verify an error was returned
 	if err == nil {
@@ -157,17 +174,12 @@ func TestFetchBlockHeader_BlockRequestFails(t *testing.T) {
 	)
 
 	client := NewClient(server.URL)
-	headerData, err := client.FetchBlockHeader("123456")
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
 
-	// Test should still pass because block request failure uses fallback timestamp
-	if err != nil {
-		t.Fatalf("FetchBlockHeader() error = %v", err)
-	}
-
-	// Should be using current time as fallback
-	now := time.Now().Unix()
-	if headerData.Timestamp == 0 || headerData.Timestamp > now+1 || headerData.Timestamp < now-5 {
-		t.Errorf("headerData.Timestamp = %d, expected approximately %d", headerData.Timestamp, now)
+	// The timestamp keys the EIP-4788 lookup, so it is never made up
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("FetchBlockHeader() error = %v, want an *APIError with status 500", err)
 	}
 }
 
@@ -186,17 +198,11 @@ func TestFetchBlockHeader_InvalidBlockJson(t *testing.T) {
 	)
 
 	client := NewClient(server.URL)
-	headerData, err := client.FetchBlockHeader("123456")
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
 
-	// Should still pass with fallback timestamp
-	if err != nil {
-		t.Fatalf("FetchBlockHeader() error = %v", err)
-	}
-
-	// Should be using current time as fallback
-	now := time.Now().Unix()
-	if headerData.Timestamp == 0 || headerData.Timestamp > now+1 || headerData.Timestamp < now-5 {
-		t.Errorf("headerData.Timestamp = %d, expected approximately %d", headerData.Timestamp, now)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("FetchBlockHeader() error = %v, want a *DecodeError", err)
 	}
 }
 
@@ -209,24 +215,443 @@ func TestFetchBlockHeader_InvalidTimestamp(t *testing.T) {
 			json.NewEncoder(w).Encode(resp)
 		},
 		func(w http.ResponseWriter, r *http.Request) {
-			var resp BlockResponse
-			resp.Data.Message.Body.ExecutionPayload.Timestamp = "not-a-number"
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(resp)
+			json.NewEncoder(w).Encode(blockResponseFor(createValidHeaderResponse(), "not-a-number"))
+		},
+	)
+
+	client := NewClient(server.URL)
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), "not-a-number") {
+		t.Fatalf("FetchBlockHeader() error = %v, want a *DecodeError for the timestamp", err)
+	}
+}
+
+func TestFetchBlockHeader_BlockByRoot(t *testing.T) {
+	header := createValidHeaderResponse()
+	var blockPaths []string
+	server := setupTestServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(header)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			blockPaths = append(blockPaths, r.URL.Path)
+			// The head moved on: the block served is a different one
+			moved := createValidHeaderResponse()
+			moved.Data.Header.Message.Slot = "123457"
+			json.NewEncoder(w).Encode(blockResponseFor(moved, "1651234579"))
+		},
+	)
+
+	_, err := NewClient(server.URL).FetchBlockHeader(context.Background(), "head")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), "not the header's 123456") {
+		t.Fatalf("FetchBlockHeader() error = %v, want a *DecodeError for the mismatched block", err)
+	}
+
+	root, _ := HeaderData{
+		Slot:          header.Data.Header.Message.Slot,
+		ProposerIndex: header.Data.Header.Message.ProposerIndex,
+		ParentRoot:    header.Data.Header.Message.ParentRoot,
+		StateRoot:     header.Data.Header.Message.StateRoot,
+		BodyRoot:      header.Data.Header.Message.BodyRoot,
+	}.HashTreeRoot()
+	if want := "/eth/v2/beacon/blocks/0x" + hex.EncodeToString(root[:]); len(blockPaths) != 1 || blockPaths[0] != want {
+		t.Errorf("block requested at %q, want %q", blockPaths, want)
+	}
+}
+
+func TestFetchBlockHeader_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    error
+		message string
+	}{
+		{"Skipped slot", http.StatusNotFound, `{"code":404,"message":"NOT_FOUND: beacon block at slot 123456"}`,
+			ErrSlotEmpty, "NOT_FOUND: beacon block at slot 123456"},
+		{"Syncing", http.StatusServiceUnavailable, `{"code":503,"message":"Beacon node is currently syncing"}`,
+			ErrNodeSyncing, "Beacon node is currently syncing"},
+		{"Plain error body", http.StatusNotFound, "not found", ErrSlotEmpty, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupTestServer(t,
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+				},
+				nil,
+			)
+
+			_, err := NewClient(server.URL).FetchBlockHeader(context.Background(), "123456")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("FetchBlockHeader() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("FetchBlockHeader() error = %v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("APIError = %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, tt.status, tt.message)
+			}
+		})
+	}
+
+	t.Run("Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewClient(server.URL).FetchBlockHeader(context.Background(), "123456")
+		var transportErr *TransportError
+		if !errors.As(err, &transportErr) {
+			t.Fatalf("FetchBlockHeader() error = %v, want a *TransportError", err)
+		}
+		if errors.Is(err, ErrSlotEmpty) {
+			t.Error("an unreachable node must not look like an empty slot")
+		}
+	})
+}
+
+func TestFetchBlockNotFoundByRoot(t *testing.T) {
+	// The header is served, but the node no longer has its block
+	server := setupTestServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(createValidHeaderResponse())
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
 		},
 	)
+	client := NewClient(server.URL)
+
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrSlotEmpty) {
+		t.Errorf("FetchBlockHeader() error = %v, want ErrNotFound for a filled slot", err)
+	}
+	root := "0x" + strings.Repeat("ab", 32)
+	if _, err := client.FetchBlock(context.Background(), root); !errors.Is(err, ErrNotFound) || errors.Is(err, ErrSlotEmpty) {
+		t.Errorf("FetchBlock(root) error = %v, want ErrNotFound", err)
+	}
+	if _, err := client.FetchBlock(context.Background(), "123456"); !errors.Is(err, ErrSlotEmpty) {
+		t.Errorf("FetchBlock(slot) error = %v, want ErrSlotEmpty", err)
+	}
+}
+
+func TestFetchStateSSZ(t *testing.T) {
+	state := []byte{1, 2, 3, 4}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v2/debug/beacon/states/123456" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept") != "application/octet-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Eth-Consensus-Version", "electra")
+		w.Write(state)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	data, version, err := client.FetchStateSSZ(context.Background(), "123456")
+	if err != nil {
+		t.Fatalf("FetchStateSSZ() error = %v", err)
+	}
+	if string(data) != string(state) || version != "electra" {
+		t.Errorf("FetchStateSSZ() = %x, %s, want %x, electra", data, version, state)
+	}
+
+	if _, _, err := client.FetchStateSSZ(context.Background(), "head"); err == nil {
+		t.Error("FetchStateSSZ() expected error for a missing state, got nil")
+	}
+}
+
+func TestFetchFinalityCheckpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/beacon/states/head/finality_checkpoints" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"execution_optimistic":false,"finalized":true,"data":{
+			"previous_justified":{"epoch":"9","root":"0x09"},
+			"current_justified":{"epoch":"10","root":"0x0a"},
+			"finalized":{"epoch":"8","root":"0x08"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	checkpoints, err := client.FetchFinalityCheckpoints(context.Background(), "head")
+	if err != nil {
+		t.Fatalf("FetchFinalityCheckpoints() error = %v", err)
+	}
+	if checkpoints.CurrentJustified != (Checkpoint{Epoch: "10", Root: "0x0a"}) ||
+		checkpoints.PreviousJustified.Epoch != "9" || checkpoints.Finalized.Root != "0x08" {
+		t.Errorf("FetchFinalityCheckpoints() = %+v", checkpoints)
+	}
+
+	client.Retry = RetryPolicy{}
+	if _, err := client.FetchFinalityCheckpoints(context.Background(), "finalized"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FetchFinalityCheckpoints() for a missing state error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	client := NewClient(server.URL)
+	client.Timeout = 50 * time.Millisecond
+	// The timeout bounds each attempt; measure a single one
+	client.Retry = RetryPolicy{}
+	start := time.Now()
+	_, err := client.FetchBlockHeader(context.Background(), "123456")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchBlockHeader() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchBlockHeader() took %v despite a %v timeout", elapsed, client.Timeout)
+	}
+
+	client.Timeout = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.FetchBlock(ctx, "head"); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchBlock() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestClientTimeoutStateBody(t *testing.T) {
+	// States stream for longer than the request timeout once headers arrive
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Eth-Consensus-Version", "deneb")
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 4; i++ {
+			w.Write([]byte{byte(i)})
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.Timeout = 50 * time.Millisecond
+	data, _, err := client.FetchStateSSZ(context.Background(), "head")
+	if err != nil {
+		t.Fatalf("FetchStateSSZ() error = %v", err)
+	}
+	if len(data) != 4 {
+		t.Errorf("FetchStateSSZ() returned %d bytes, want 4", len(data))
+	}
+}
+
+// sszBlockServer serves slot 123456 as an SSZ Deneb block to clients that
+// ask for SSZ and as JSON to the rest; with refuseSSZ it answers requests
+// preferring SSZ with 406 instead
+func sszBlockServer(t *testing.T, refuseSSZ bool) (*httptest.Server, map[string]any) {
+	schema, err := SchemaFor(Deneb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := ssz.Zero(schema.SignedBeaconBlock).(map[string]any)
+	message := signed["message"].(map[string]any)
+	message["slot"] = "123456"
+	payload := message["body"].(map[string]any)["execution_payload"].(map[string]any)
+	payload["timestamp"] = "1651234567"
+	encoded, err := ssz.Marshal(schema.SignedBeaconBlock, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyRoot, err := ssz.HashTreeRoot(schema.BeaconBlockBody, message["body"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var header APIResponse
+	header.Data.Header.Message.Slot = "123456"
+	header.Data.Header.Message.ProposerIndex = "0"
+	header.Data.Header.Message.ParentRoot = "0x" + strings.Repeat("00", 32)
+	header.Data.Header.Message.StateRoot = "0x" + strings.Repeat("00", 32)
+	header.Data.Header.Message.BodyRoot = "0x" + hex.EncodeToString(bodyRoot[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/eth/v1/beacon/headers/123456":
+			json.NewEncoder(w).Encode(header)
+		case strings.HasPrefix(r.URL.Path, "/eth/v2/beacon/blocks/"):
+			if strings.HasPrefix(r.Header.Get("Accept"), "application/octet-stream") {
+				if refuseSSZ {
+					w.WriteHeader(http.StatusNotAcceptable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.Header().Set("Eth-Consensus-Version", "deneb")
+				w.Write(encoded)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"version": "deneb", "data": signed})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, message
+}
+
+func TestFetchBlockSSZ(t *testing.T) {
+	schema, err := SchemaFor(Deneb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, refuseSSZ := range []bool{false, true} {
+		server, message := sszBlockServer(t, refuseSSZ)
+		client := NewClient(server.URL)
+
+		block, err := client.FetchBlock(context.Background(), "123456")
+		if err != nil {
+			t.Fatalf("FetchBlock() refuseSSZ=%v error = %v", refuseSSZ, err)
+		}
+		if block.Version != Deneb {
+			t.Errorf("FetchBlock() version = %q, want deneb", block.Version)
+		}
+		body, err := block.Body()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ssz.HashTreeRoot(schema.BeaconBlockBody, body)
+		if err != nil {
+			t.Fatalf("HashTreeRoot() of the fetched body error = %v", err)
+		}
+		want, _ := ssz.HashTreeRoot(schema.BeaconBlockBody, message["body"])
+		if got != want {
+			t.Errorf("fetched body root = %x, want %x (refuseSSZ=%v)", got, want, refuseSSZ)
+		}
 
+		header, err := client.FetchBlockHeader(context.Background(), "123456")
+		if err != nil {
+			t.Fatalf("FetchBlockHeader() error = %v", err)
+		}
+		if header.Timestamp != 1651234567 {
+			t.Errorf("FetchBlockHeader() timestamp = %d, want 1651234567", header.Timestamp)
+		}
+	}
+}
+
+func TestCheckBlockBody(t *testing.T) {
+	server, message := sszBlockServer(t, true)
 	client := NewClient(server.URL)
-	headerData, err := client.FetchBlockHeader("123456")
 
-	// Should still pass with fallback timestamp
+	header, err := client.FetchBlockHeader(context.Background(), "123456")
 	if err != nil {
 		t.Fatalf("FetchBlockHeader() error = %v", err)
 	}
+	block, err := client.FetchBlock(context.Background(), "123456")
+	if err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if err := CheckBlockBody(block, header); err != nil {
+		t.Errorf("CheckBlockBody() error = %v", err)
+	}
+
+	// A node serving a body that does not match body_root still returns the
+	// header; only the body check fails
+	message["body"].(map[string]any)["execution_payload"].(map[string]any)["gas_used"] = "1"
+	header, err = client.FetchBlockHeader(context.Background(), "123456")
+	if err != nil {
+		t.Fatalf("FetchBlockHeader() with a tampered body error = %v", err)
+	}
+	if block, err = client.FetchBlock(context.Background(), "123456"); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if err := CheckBlockBody(block, header); err == nil || !strings.Contains(err.Error(), "not the header's body_root") {
+		t.Errorf("CheckBlockBody() of a tampered body error = %v", err)
+	}
+}
 
-	// Should be using current time as fallback
-	now := time.Now().Unix()
-	if headerData.Timestamp == 0 || headerData.Timestamp > now+1 || headerData.Timestamp < now-5 {
-		t.Errorf("headerData.Timestamp = %d, expected approximately %d", headerData.Timestamp, now)
+func TestFetchBlockSSZErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		body    []byte
+	}{
+		{"MissingVersion", "", []byte{1}},
+		{"UnknownForkEvenForJSON", "bellatrix", []byte{1}},
+		{"Truncated", "deneb", []byte{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/octet-stream")
+				if tt.version != "" {
+					w.Header().Set("Eth-Consensus-Version", tt.version)
+				}
+				w.Write(tt.body)
+			}))
+			t.Cleanup(server.Close)
+
+			var decodeErr *DecodeError
+			if _, err := NewClient(server.URL).FetchBlock(context.Background(), "head"); !errors.As(err, &decodeErr) {
+				t.Errorf("FetchBlock() error = %v, want *DecodeError", err)
+			}
+		})
+	}
+}
+
+func TestFetchBlockSSZUnknownFork(t *testing.T) {
+	header := createValidHeaderResponse()
+	var accepts []string
+	server := setupTestServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(header)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			accepts = append(accepts, r.Header.Get("Accept"))
+			// Bellatrix has no layout in the registry, so its SSZ cannot be
+			// decoded; the JSON form still carries the payload timestamp
+			if strings.HasPrefix(r.Header.Get("Accept"), "application/octet-stream") {
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.Header().Set("Eth-Consensus-Version", "bellatrix")
+				w.Write([]byte{1, 2, 3})
+				return
+			}
+			resp := blockResponseFor(header, "1651234567")
+			resp["version"] = "bellatrix"
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		},
+	)
+	client := NewClient(server.URL)
+
+	block, err := client.FetchBlock(context.Background(), "123456")
+	if err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if block.Version != "bellatrix" {
+		t.Errorf("FetchBlock() version = %q, want bellatrix", block.Version)
+	}
+	if len(accepts) != 2 || accepts[1] != "application/json" {
+		t.Errorf("Accept headers = %q, want SSZ then JSON", accepts)
+	}
+
+	headerData, err := client.FetchBlockHeader(context.Background(), "123456")
+	if err != nil {
+		t.Fatalf("FetchBlockHeader() error = %v", err)
+	}
+	if headerData.Timestamp != 1651234567 {
+		t.Errorf("FetchBlockHeader() timestamp = %d, want 1651234567", headerData.Timestamp)
 	}
 }
@@ -0,0 +1,466 @@
+package beacon
+
+import (
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Mainnet preset values used by the container definitions
+const (
+	MaxProposerSlashings               = 16
+	MaxAttesterSlashings               = 2
+	MaxAttestations                    = 128
+	MaxDeposits                        = 16
+	MaxVoluntaryExits                  = 16
+	MaxBLSToExecutionChanges           = 16
+	MaxBlobCommitmentsPerBlock         = 4096
+	MaxValidatorsPerCommittee          = 2048
+	MaxCommitteesPerSlot               = 64
+	MaxAttesterSlashingsElectra        = 1
+	MaxAttestationsElectra             = 8
+	MaxDepositRequestsPerPayload       = 8192
+	MaxWithdrawalRequestsPerPayload    = 16
+	MaxConsolidationRequestsPerPayload = 2
+	SyncCommitteeSize                  = 512
+	DepositContractTreeDepth           = 32
+	MaxBytesPerTransaction             = 1 << 30
+	MaxTransactionsPerPayload          = 1 << 20
+	MaxWithdrawalsPerPayload           = 16
+	MaxExtraDataBytes                  = 32
+	BytesPerLogsBloom                  = 256
+	SlotsPerEpoch                      = 32
+	SlotsPerHistoricalRoot             = 8192
+	EpochsPerHistoricalVector          = 65536
+	EpochsPerSlashingsVector           = 8192
+	EpochsPerEth1VotingPeriod          = 64
+	HistoricalRootsLimit               = 1 << 24
+	ValidatorRegistryLimit             = 1 << 40
+	PendingDepositsLimit               = 1 << 27
+	PendingPartialWithdrawalsLimit     = 1 << 27
+	PendingConsolidationsLimit         = 1 << 18
+	MinSeedLookahead                   = 1
+	EpochsPerSyncCommitteePeriod       = 256
+	MinSyncCommitteeParticipants       = 1
+	MaxRequestLightClientUpdates       = 128
+)
+
+// Primitive SSZ aliases from the consensus specs
+var (
+	Root             = ssz.Bytes(32)
+	Hash32           = ssz.Bytes(32)
+	BLSPubkey        = ssz.Bytes(48)
+	BLSSignature     = ssz.Bytes(96)
+	ExecutionAddress = ssz.Bytes(20)
+	KZGCommitment    = ssz.Bytes(48)
+	Version          = ssz.Bytes(4)
+)
+
+// Containers shared by every supported fork
+var (
+	BeaconBlockHeaderType = ssz.Container("BeaconBlockHeader",
+		ssz.F("slot", ssz.Uint64),
+		ssz.F("proposer_index", ssz.Uint64),
+		ssz.F("parent_root", Root),
+		ssz.F("state_root", Root),
+		ssz.F("body_root", Root),
+	)
+
+	SignedBeaconBlockHeaderType = ssz.Container("SignedBeaconBlockHeader",
+		ssz.F("message", BeaconBlockHeaderType),
+		ssz.F("signature", BLSSignature),
+	)
+
+	Eth1DataType = ssz.Container("Eth1Data",
+		ssz.F("deposit_root", Root),
+		ssz.F("deposit_count", ssz.Uint64),
+		ssz.F("block_hash", Hash32),
+	)
+
+	CheckpointType = ssz.Container("Checkpoint",
+		ssz.F("epoch", ssz.Uint64),
+		ssz.F("root", Root),
+	)
+
+	AttestationDataType = ssz.Container("AttestationData",
+		ssz.F("slot", ssz.Uint64),
+		ssz.F("index", ssz.Uint64),
+		ssz.F("beacon_block_root", Root),
+		ssz.F("source", CheckpointType),
+		ssz.F("target", CheckpointType),
+	)
+
+	ProposerSlashingType = ssz.Container("ProposerSlashing",
+		ssz.F("signed_header_1", SignedBeaconBlockHeaderType),
+		ssz.F("signed_header_2", SignedBeaconBlockHeaderType),
+	)
+
+	DepositDataType = ssz.Container("DepositData",
+		ssz.F("pubkey", BLSPubkey),
+		ssz.F("withdrawal_credentials", ssz.Bytes(32)),
+		ssz.F("amount", ssz.Uint64),
+		ssz.F("signature", BLSSignature),
+	)
+
+	DepositType = ssz.Container("Deposit",
+		ssz.F("proof", ssz.Vector(ssz.Bytes(32), DepositContractTreeDepth+1)),
+		ssz.F("data", DepositDataType),
+	)
+
+	SignedVoluntaryExitType = ssz.Container("SignedVoluntaryExit",
+		ssz.F("message", ssz.Container("VoluntaryExit",
+			ssz.F("epoch", ssz.Uint64),
+			ssz.F("validator_index", ssz.Uint64),
+		)),
+		ssz.F("signature", BLSSignature),
+	)
+
+	SyncAggregateType = ssz.Container("SyncAggregate",
+		ssz.F("sync_committee_bits", ssz.Bitvector(SyncCommitteeSize)),
+		ssz.F("sync_committee_signature", BLSSignature),
+	)
+
+	WithdrawalType = ssz.Container("Withdrawal",
+		ssz.F("index", ssz.Uint64),
+		ssz.F("validator_index", ssz.Uint64),
+		ssz.F("address", ExecutionAddress),
+		ssz.F("amount", ssz.Uint64),
+	)
+
+	SignedBLSToExecutionChangeType = ssz.Container("SignedBLSToExecutionChange",
+		ssz.F("message", ssz.Container("BLSToExecutionChange",
+			ssz.F("validator_index", ssz.Uint64),
+			ssz.F("from_bls_pubkey", BLSPubkey),
+			ssz.F("to_execution_address", ExecutionAddress),
+		)),
+		ssz.F("signature", BLSSignature),
+	)
+)
+
+// State containers shared by every supported fork
+var (
+	ForkType = ssz.Container("Fork",
+		ssz.F("previous_version", Version),
+		ssz.F("current_version", Version),
+		ssz.F("epoch", ssz.Uint64),
+	)
+
+	ValidatorType = ssz.Container("Validator",
+		ssz.F("pubkey", BLSPubkey),
+		ssz.F("withdrawal_credentials", ssz.Bytes(32)),
+		ssz.F("effective_balance", ssz.Uint64),
+		ssz.F("slashed", ssz.Boolean),
+		ssz.F("activation_eligibility_epoch", ssz.Uint64),
+		ssz.F("activation_epoch", ssz.Uint64),
+		ssz.F("exit_epoch", ssz.Uint64),
+		ssz.F("withdrawable_epoch", ssz.Uint64),
+	)
+
+	SyncCommitteeType = ssz.Container("SyncCommittee",
+		ssz.F("pubkeys", ssz.Vector(BLSPubkey, SyncCommitteeSize)),
+		ssz.F("aggregate_pubkey", BLSPubkey),
+	)
+
+	HistoricalSummaryType = ssz.Container("HistoricalSummary",
+		ssz.F("block_summary_root", Root),
+		ssz.F("state_summary_root", Root),
+	)
+)
+
+// Capella containers
+var (
+	ExecutionPayloadCapellaType = ssz.Container("ExecutionPayload",
+		ssz.F("parent_hash", Hash32),
+		ssz.F("fee_recipient", ExecutionAddress),
+		ssz.F("state_root", ssz.Bytes(32)),
+		ssz.F("receipts_root", ssz.Bytes(32)),
+		ssz.F("logs_bloom", ssz.Bytes(BytesPerLogsBloom)),
+		ssz.F("prev_randao", ssz.Bytes(32)),
+		ssz.F("block_number", ssz.Uint64),
+		ssz.F("gas_limit", ssz.Uint64),
+		ssz.F("gas_used", ssz.Uint64),
+		ssz.F("timestamp", ssz.Uint64),
+		ssz.F("extra_data", ssz.ByteList(MaxExtraDataBytes)),
+		ssz.F("base_fee_per_gas", ssz.Uint256),
+		ssz.F("block_hash", Hash32),
+		ssz.F("transactions", ssz.List(ssz.ByteList(MaxBytesPerTransaction), MaxTransactionsPerPayload)),
+		ssz.F("withdrawals", ssz.List(WithdrawalType, MaxWithdrawalsPerPayload)),
+	)
+
+	ExecutionPayloadHeaderCapellaType = ssz.Container("ExecutionPayloadHeader",
+		ssz.F("parent_hash", Hash32),
+		ssz.F("fee_recipient", ExecutionAddress),
+		ssz.F("state_root", ssz.Bytes(32)),
+		ssz.F("receipts_root", ssz.Bytes(32)),
+		ssz.F("logs_bloom", ssz.Bytes(BytesPerLogsBloom)),
+		ssz.F("prev_randao", ssz.Bytes(32)),
+		ssz.F("block_number", ssz.Uint64),
+		ssz.F("gas_limit", ssz.Uint64),
+		ssz.F("gas_used", ssz.Uint64),
+		ssz.F("timestamp", ssz.Uint64),
+		ssz.F("extra_data", ssz.ByteList(MaxExtraDataBytes)),
+		ssz.F("base_fee_per_gas", ssz.Uint256),
+		ssz.F("block_hash", Hash32),
+		ssz.F("transactions_root", Root),
+		ssz.F("withdrawals_root", Root),
+	)
+
+	BeaconBlockBodyCapellaType = ssz.Container("BeaconBlockBody",
+		ssz.F("randao_reveal", BLSSignature),
+		ssz.F("eth1_data", Eth1DataType),
+		ssz.F("graffiti", ssz.Bytes(32)),
+		ssz.F("proposer_slashings", ssz.List(ProposerSlashingType, MaxProposerSlashings)),
+		ssz.F("attester_slashings", ssz.List(AttesterSlashingDenebType, MaxAttesterSlashings)),
+		ssz.F("attestations", ssz.List(AttestationDenebType, MaxAttestations)),
+		ssz.F("deposits", ssz.List(DepositType, MaxDeposits)),
+		ssz.F("voluntary_exits", ssz.List(SignedVoluntaryExitType, MaxVoluntaryExits)),
+		ssz.F("sync_aggregate", SyncAggregateType),
+		ssz.F("execution_payload", ExecutionPayloadCapellaType),
+		ssz.F("bls_to_execution_changes", ssz.List(SignedBLSToExecutionChangeType, MaxBLSToExecutionChanges)),
+	)
+
+	BeaconStateCapellaType = ssz.Container("BeaconState",
+		ssz.F("genesis_time", ssz.Uint64),
+		ssz.F("genesis_validators_root", Root),
+		ssz.F("slot", ssz.Uint64),
+		ssz.F("fork", ForkType),
+		ssz.F("latest_block_header", BeaconBlockHeaderType),
+		ssz.F("block_roots", ssz.Vector(Root, SlotsPerHistoricalRoot)),
+		ssz.F("state_roots", ssz.Vector(Root, SlotsPerHistoricalRoot)),
+		ssz.F("historical_roots", ssz.List(Root, HistoricalRootsLimit)),
+		ssz.F("eth1_data", Eth1DataType),
+		ssz.F("eth1_data_votes", ssz.List(Eth1DataType, EpochsPerEth1VotingPeriod*SlotsPerEpoch)),
+		ssz.F("eth1_deposit_index", ssz.Uint64),
+		ssz.F("validators", ssz.List(ValidatorType, ValidatorRegistryLimit)),
+		ssz.F("balances", ssz.List(ssz.Uint64, ValidatorRegistryLimit)),
+		ssz.F("randao_mixes", ssz.Vector(ssz.Bytes(32), EpochsPerHistoricalVector)),
+		ssz.F("slashings", ssz.Vector(ssz.Uint64, EpochsPerSlashingsVector)),
+		ssz.F("previous_epoch_participation", ssz.List(ssz.Uint8, ValidatorRegistryLimit)),
+		ssz.F("current_epoch_participation", ssz.List(ssz.Uint8, ValidatorRegistryLimit)),
+		ssz.F("justification_bits", ssz.Bitvector(4)),
+		ssz.F("previous_justified_checkpoint", CheckpointType),
+		ssz.F("current_justified_checkpoint", CheckpointType),
+		ssz.F("finalized_checkpoint", CheckpointType),
+		ssz.F("inactivity_scores", ssz.List(ssz.Uint64, ValidatorRegistryLimit)),
+		ssz.F("current_sync_committee", SyncCommitteeType),
+		ssz.F("next_sync_committee", SyncCommitteeType),
+		ssz.F("latest_execution_payload_header", ExecutionPayloadHeaderCapellaType),
+		ssz.F("next_withdrawal_index", ssz.Uint64),
+		ssz.F("next_withdrawal_validator_index", ssz.Uint64),
+		ssz.F("historical_summaries", ssz.List(HistoricalSummaryType, HistoricalRootsLimit)),
+	)
+)
+
+// Deneb containers
+var (
+	IndexedAttestationDenebType = ssz.Container("IndexedAttestation",
+		ssz.F("attesting_indices", ssz.List(ssz.Uint64, MaxValidatorsPerCommittee)),
+		ssz.F("data", AttestationDataType),
+		ssz.F("signature", BLSSignature),
+	)
+
+	AttesterSlashingDenebType = ssz.Container("AttesterSlashing",
+		ssz.F("attestation_1", IndexedAttestationDenebType),
+		ssz.F("attestation_2", IndexedAttestationDenebType),
+	)
+
+	AttestationDenebType = ssz.Container("Attestation",
+		ssz.F("aggregation_bits", ssz.Bitlist(MaxValidatorsPerCommittee)),
+		ssz.F("data", AttestationDataType),
+		ssz.F("signature", BLSSignature),
+	)
+
+	ExecutionPayloadDenebType = ssz.Container("ExecutionPayload",
+		ssz.F("parent_hash", Hash32),
+		ssz.F("fee_recipient", ExecutionAddress),
+		ssz.F("state_root", ssz.Bytes(32)),
+		ssz.F("receipts_root", ssz.Bytes(32)),
+		ssz.F("logs_bloom", ssz.Bytes(BytesPerLogsBloom)),
+		ssz.F("prev_randao", ssz.Bytes(32)),
+		ssz.F("block_number", ssz.Uint64),
+		ssz.F("gas_limit", ssz.Uint64),
+		ssz.F("gas_used", ssz.Uint64),
+		ssz.F("timestamp", ssz.Uint64),
+		ssz.F("extra_data", ssz.ByteList(MaxExtraDataBytes)),
+		ssz.F("base_fee_per_gas", ssz.Uint256),
+		ssz.F("block_hash", Hash32),
+		ssz.F("transactions", ssz.List(ssz.ByteList(MaxBytesPerTransaction), MaxTransactionsPerPayload)),
+		ssz.F("withdrawals", ssz.List(WithdrawalType, MaxWithdrawalsPerPayload)),
+		ssz.F("blob_gas_used", ssz.Uint64),
+		ssz.F("excess_blob_gas", ssz.Uint64),
+	)
+
+	ExecutionPayloadHeaderDenebType = ExecutionPayloadHeaderCapellaType.Extend("ExecutionPayloadHeader",
+		ssz.F("blob_gas_used", ssz.Uint64),
+		ssz.F("excess_blob_gas", ssz.Uint64),
+	)
+
+	BeaconBlockBodyDenebType = ssz.Container("BeaconBlockBody",
+		ssz.F("randao_reveal", BLSSignature),
+		ssz.F("eth1_data", Eth1DataType),
+		ssz.F("graffiti", ssz.Bytes(32)),
+		ssz.F("proposer_slashings", ssz.List(ProposerSlashingType, MaxProposerSlashings)),
+		ssz.F("attester_slashings", ssz.List(AttesterSlashingDenebType, MaxAttesterSlashings)),
+		ssz.F("attestations", ssz.List(AttestationDenebType, MaxAttestations)),
+		ssz.F("deposits", ssz.List(DepositType, MaxDeposits)),
+		ssz.F("voluntary_exits", ssz.List(SignedVoluntaryExitType, MaxVoluntaryExits)),
+		ssz.F("sync_aggregate", SyncAggregateType),
+		ssz.F("execution_payload", ExecutionPayloadDenebType),
+		ssz.F("bls_to_execution_changes", ssz.List(SignedBLSToExecutionChangeType, MaxBLSToExecutionChanges)),
+		ssz.F("blob_kzg_commitments", ssz.List(KZGCommitment, MaxBlobCommitmentsPerBlock)),
+	)
+)
+
+// Electra containers
+var (
+	IndexedAttestationElectraType = ssz.Container("IndexedAttestation",
+		ssz.F("attesting_indices", ssz.List(ssz.Uint64, MaxValidatorsPerCommittee*MaxCommitteesPerSlot)),
+		ssz.F("data", AttestationDataType),
+		ssz.F("signature", BLSSignature),
+	)
+
+	AttesterSlashingElectraType = ssz.Container("AttesterSlashing",
+		ssz.F("attestation_1", IndexedAttestationElectraType),
+		ssz.F("attestation_2", IndexedAttestationElectraType),
+	)
+
+	AttestationElectraType = ssz.Container("Attestation",
+		ssz.F("aggregation_bits", ssz.Bitlist(MaxValidatorsPerCommittee*MaxCommitteesPerSlot)),
+		ssz.F("data", AttestationDataType),
+		ssz.F("signature", BLSSignature),
+		ssz.F("committee_bits", ssz.Bitvector(MaxCommitteesPerSlot)),
+	)
+
+	PendingDepositType = ssz.Container("PendingDeposit",
+		ssz.F("pubkey", BLSPubkey),
+		ssz.F("withdrawal_credentials", ssz.Bytes(32)),
+		ssz.F("amount", ssz.Uint64),
+		ssz.F("signature", BLSSignature),
+		ssz.F("slot", ssz.Uint64),
+	)
+
+	PendingPartialWithdrawalType = ssz.Container("PendingPartialWithdrawal",
+		ssz.F("validator_index", ssz.Uint64),
+		ssz.F("amount", ssz.Uint64),
+		ssz.F("withdrawable_epoch", ssz.Uint64),
+	)
+
+	PendingConsolidationType = ssz.Container("PendingConsolidation",
+		ssz.F("source_index", ssz.Uint64),
+		ssz.F("target_index", ssz.Uint64),
+	)
+
+	DepositRequestType = ssz.Container("DepositRequest",
+		ssz.F("pubkey", BLSPubkey),
+		ssz.F("withdrawal_credentials", ssz.Bytes(32)),
+		ssz.F("amount", ssz.Uint64),
+		ssz.F("signature", BLSSignature),
+		ssz.F("index", ssz.Uint64),
+	)
+
+	WithdrawalRequestType = ssz.Container("WithdrawalRequest",
+		ssz.F("source_address", ExecutionAddress),
+		ssz.F("validator_pubkey", BLSPubkey),
+		ssz.F("amount", ssz.Uint64),
+	)
+
+	ConsolidationRequestType = ssz.Container("ConsolidationRequest",
+		ssz.F("source_address", ExecutionAddress),
+		ssz.F("source_pubkey", BLSPubkey),
+		ssz.F("target_pubkey", BLSPubkey),
+	)
+
+	ExecutionRequestsType = ssz.Container("ExecutionRequests",
+		ssz.F("deposits", ssz.List(DepositRequestType, MaxDepositRequestsPerPayload)),
+		ssz.F("withdrawals", ssz.List(WithdrawalRequestType, MaxWithdrawalRequestsPerPayload)),
+		ssz.F("consolidations", ssz.List(ConsolidationRequestType, MaxConsolidationRequestsPerPayload)),
+	)
+
+	BeaconBlockBodyElectraType = ssz.Container("BeaconBlockBody",
+		ssz.F("randao_reveal", BLSSignature),
+		ssz.F("eth1_data", Eth1DataType),
+		ssz.F("graffiti", ssz.Bytes(32)),
+		ssz.F("proposer_slashings", ssz.List(ProposerSlashingType, MaxProposerSlashings)),
+		ssz.F("attester_slashings", ssz.List(AttesterSlashingElectraType, MaxAttesterSlashingsElectra)),
+		ssz.F("attestations", ssz.List(AttestationElectraType, MaxAttestationsElectra)),
+		ssz.F("deposits", ssz.List(DepositType, MaxDeposits)),
+		ssz.F("voluntary_exits", ssz.List(SignedVoluntaryExitType, MaxVoluntaryExits)),
+		ssz.F("sync_aggregate", SyncAggregateType),
+		ssz.F("execution_payload", ExecutionPayloadDenebType),
+		ssz.F("bls_to_execution_changes", ssz.List(SignedBLSToExecutionChangeType, MaxBLSToExecutionChanges)),
+		ssz.F("blob_kzg_commitments", ssz.List(KZGCommitment, MaxBlobCommitmentsPerBlock)),
+		ssz.F("execution_requests", ExecutionRequestsType),
+	)
+)
+
+// Blinded body layouts as served by /eth/v1/beacon/blinded_blocks. They hash
+// to the same root as the full body because the payload header commits to
+// the payload.
+var (
+	BlindedBeaconBlockBodyCapellaType = blindedBody(BeaconBlockBodyCapellaType, ExecutionPayloadHeaderCapellaType)
+	BlindedBeaconBlockBodyDenebType   = blindedBody(BeaconBlockBodyDenebType, ExecutionPayloadHeaderDenebType)
+	BlindedBeaconBlockBodyElectraType = blindedBody(BeaconBlockBodyElectraType, ExecutionPayloadHeaderDenebType)
+)
+
+// BeaconState layouts, which only ever grow by appending fields
+var (
+	BeaconStateDenebType = replaceField(BeaconStateCapellaType,
+		"latest_execution_payload_header", ExecutionPayloadHeaderDenebType)
+
+	BeaconStateElectraType = BeaconStateDenebType.Extend("BeaconState",
+		ssz.F("deposit_requests_start_index", ssz.Uint64),
+		ssz.F("deposit_balance_to_consume", ssz.Uint64),
+		ssz.F("exit_balance_to_consume", ssz.Uint64),
+		ssz.F("earliest_exit_epoch", ssz.Uint64),
+		ssz.F("consolidation_balance_to_consume", ssz.Uint64),
+		ssz.F("earliest_consolidation_epoch", ssz.Uint64),
+		ssz.F("pending_deposits", ssz.List(PendingDepositType, PendingDepositsLimit)),
+		ssz.F("pending_partial_withdrawals", ssz.List(PendingPartialWithdrawalType, PendingPartialWithdrawalsLimit)),
+		ssz.F("pending_consolidations", ssz.List(PendingConsolidationType, PendingConsolidationsLimit)),
+	)
+
+	BeaconStateFuluType = BeaconStateElectraType.Extend("BeaconState",
+		ssz.F("proposer_lookahead", ssz.Vector(ssz.Uint64, (MinSeedLookahead+1)*SlotsPerEpoch)),
+	)
+)
+
+// blindedBody returns a copy of a body with the execution payload swapped
+// for its header
+func blindedBody(body *ssz.Type, payloadHeader *ssz.Type) *ssz.Type {
+	fields := make([]ssz.Field, len(body.Fields))
+	copy(fields, body.Fields)
+	i, ok := body.FieldIndex("execution_payload")
+	if !ok {
+		panic("blindedBody: body has no execution_payload")
+	}
+	fields[i] = ssz.F("execution_payload_header", payloadHeader)
+	return ssz.Container("BlindedBeaconBlockBody", fields...)
+}
+
+// signedBlock returns the SignedBeaconBlock layout around a body, as served
+// in SSZ by the blocks endpoints
+func signedBlock(body *ssz.Type) *ssz.Type {
+	return ssz.Container("SignedBeaconBlock",
+		ssz.F("message", ssz.Container("BeaconBlock",
+			ssz.F("slot", ssz.Uint64),
+			ssz.F("proposer_index", ssz.Uint64),
+			ssz.F("parent_root", Root),
+			ssz.F("state_root", Root),
+			ssz.F("body", body),
+		)),
+		ssz.F("signature", BLSSignature),
+	)
+}
+
+// replaceField returns a copy of a container with one field's type swapped
+func replaceField(t *ssz.Type, name string, fieldType *ssz.Type) *ssz.Type {
+	fields := make([]ssz.Field, len(t.Fields))
+	copy(fields, t.Fields)
+	i, ok := t.FieldIndex(name)
+	if !ok {
+		panic("replaceField: unknown field " + name)
+	}
+	fields[i].Type = fieldType
+	return ssz.Container(t.Name, fields...)
+}
+
+// ExecutionRequestKinds lists the execution_requests lists in container order
+var ExecutionRequestKinds = []string{"deposits", "withdrawals", "consolidations"}
@@ -0,0 +1,74 @@
+package beacon
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+func TestBeaconBlockHeaderTypeMatchesSerialization(t *testing.T) {
+	var header BlockHeader
+	err := header.FromAPIResponse(HeaderData{
+		Slot:          "123456",
+		ProposerIndex: "42",
+		ParentRoot:    "0x4a81947b35bdc11471fc7b42350427a3b9d2b92bf21d423ded6dcc5c66caad0e",
+		StateRoot:     "0x5bc9a4ef3cf09a315ffbc12872de6cc412a7abb55a5228cc21fbdb5fb797d7a8",
+		BodyRoot:      "0x67df26e0c9f5de4fe7b3f66f3591f84a9cf6e8cda7f5b3f23db5c3967a505c31",
+	})
+	if err != nil {
+		t.Fatalf("FromAPIResponse() error = %v", err)
+	}
+
+	tree, err := merkle.NewTree(header.SerializeForMerkleization())
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+
+	root, err := ssz.HashTreeRoot(BeaconBlockHeaderType, header.Value())
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	if !bytes.Equal(root[:], tree.Root()) {
+		t.Errorf("HashTreeRoot() = %x, want %x", root, tree.Root())
+	}
+}
+
+func TestBlockBodyGeneralizedIndices(t *testing.T) {
+	tests := []struct {
+		name   string
+		typ    *ssz.Type
+		path   []string
+		gindex uint64
+	}{
+		// EXECUTION_PAYLOAD_GINDEX from the light client specs
+		{"Deneb execution_payload", BeaconBlockBodyDenebType, []string{"execution_payload"}, 25},
+		{"Electra execution_payload", BeaconBlockBodyElectraType, []string{"execution_payload"}, 25},
+		{"Electra execution_requests", BeaconBlockBodyElectraType, []string{"execution_requests"}, 28},
+		{"Electra first deposit request", BeaconBlockBodyElectraType,
+			[]string{"execution_requests", "deposits", "0"}, (28<<2|0)<<14 | 0},
+		{"Electra second consolidation request", BeaconBlockBodyElectraType,
+			[]string{"execution_requests", "consolidations", "1"}, (28<<2|2)<<2 | 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.typ.GeneralizedIndex(tt.path...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			if got != tt.gindex {
+				t.Errorf("GeneralizedIndex() = %d, want %d", got, tt.gindex)
+			}
+		})
+	}
+}
+
+func TestBlockBodyZeroValuesHash(t *testing.T) {
+	for _, typ := range []*ssz.Type{BeaconBlockBodyDenebType, BeaconBlockBodyElectraType} {
+		if _, err := ssz.HashTreeRoot(typ, ssz.Zero(typ)); err != nil {
+			t.Errorf("HashTreeRoot(%s) error = %v", typ.Name, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package beacon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Errors the beacon API reports through its status codes. They are matched
+// with errors.Is against the *APIError the client returns.
+var (
+	// ErrSlotEmpty means the node has no block at the requested slot or
+	// named block ID, usually because the slot was skipped
+	ErrSlotEmpty = errors.New("no block at the requested block id")
+	// ErrNotFound means a requested resource, such as a pruned state or a
+	// block root the node does not hold, does not exist
+	ErrNotFound = errors.New("resource not found")
+	// ErrNodeSyncing means the node cannot serve the request until it has
+	// caught up with the chain
+	ErrNodeSyncing = errors.New("beacon node is syncing")
+)
+
+// APIError is a non-200 response. Code and Message come from the JSON error
+// body beacon nodes send, when there is one.
+type APIError struct {
+	URL        string
+	StatusCode int
+	Code       int
+	Message    string
+	// kind is the sentinel error the status code maps to, if any
+	kind error
+}
+
+// Error describes the status and the node's own message
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API returned status code %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API returned status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns ErrSlotEmpty, ErrNotFound or ErrNodeSyncing when the status
+// code means one of them
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// TransportError is a request that got no response at all: the node was
+// unreachable, the connection dropped, or the context ended
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request to %s failed: %v", e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is a 200 response whose body could not be decoded
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error decoding response from %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// maxErrorBody caps how much of an error response is read
+const maxErrorBody = 64 << 10
+
+// checkResponse returns an *APIError for any non-200 response. A 404 maps to
+// notFound, which callers set to ErrSlotEmpty for slots and named block IDs
+// and ErrNotFound otherwise.
+func checkResponse(resp *http.Response, url string, notFound error) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	apiErr := &APIError{URL: url, StatusCode: resp.StatusCode}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		apiErr.kind = notFound
+	case http.StatusServiceUnavailable:
+		apiErr.kind = ErrNodeSyncing
+	}
+
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err == nil {
+		apiErr.Code = body.Code
+		apiErr.Message = body.Message
+	}
+	return apiErr
+}
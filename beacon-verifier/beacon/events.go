@@ -0,0 +1,259 @@
+package beacon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event stream topics served by /eth/v1/events
+const (
+	TopicHead                = "head"
+	TopicBlock               = "block"
+	TopicFinalizedCheckpoint = "finalized_checkpoint"
+	TopicChainReorg          = "chain_reorg"
+)
+
+// Event is one message of the beacon node event stream: a *HeadEvent,
+// *BlockEvent, *FinalizedCheckpointEvent or *ChainReorgEvent
+type Event interface {
+	Topic() string
+}
+
+// HeadEvent announces a new head of the canonical chain
+type HeadEvent struct {
+	Slot                      string `json:"slot"`
+	Block                     string `json:"block"`
+	State                     string `json:"state"`
+	EpochTransition           bool   `json:"epoch_transition"`
+	PreviousDutyDependentRoot string `json:"previous_duty_dependent_root"`
+	CurrentDutyDependentRoot  string `json:"current_duty_dependent_root"`
+	ExecutionOptimistic       bool   `json:"execution_optimistic"`
+}
+
+// BlockEvent announces a block that passed gossip or API validation, which
+// need not become the head
+type BlockEvent struct {
+	Slot                string `json:"slot"`
+	Block               string `json:"block"`
+	ExecutionOptimistic bool   `json:"execution_optimistic"`
+}
+
+// FinalizedCheckpointEvent announces a newly finalized checkpoint
+type FinalizedCheckpointEvent struct {
+	Block               string `json:"block"`
+	State               string `json:"state"`
+	Epoch               string `json:"epoch"`
+	ExecutionOptimistic bool   `json:"execution_optimistic"`
+}
+
+// ChainReorgEvent announces that the head moved to a block that does not
+// descend from the previous head
+type ChainReorgEvent struct {
+	Slot                string `json:"slot"`
+	Depth               string `json:"depth"`
+	OldHeadBlock        string `json:"old_head_block"`
+	NewHeadBlock        string `json:"new_head_block"`
+	OldHeadState        string `json:"old_head_state"`
+	NewHeadState        string `json:"new_head_state"`
+	Epoch               string `json:"epoch"`
+	ExecutionOptimistic bool   `json:"execution_optimistic"`
+}
+
+func (*HeadEvent) Topic() string                { return TopicHead }
+func (*BlockEvent) Topic() string               { return TopicBlock }
+func (*FinalizedCheckpointEvent) Topic() string { return TopicFinalizedCheckpoint }
+func (*ChainReorgEvent) Topic() string          { return TopicChainReorg }
+
+// newEvent returns an empty event for a topic, or nil for unknown topics
+func newEvent(topic string) Event {
+	switch topic {
+	case TopicHead:
+		return &HeadEvent{}
+	case TopicBlock:
+		return &BlockEvent{}
+	case TopicFinalizedCheckpoint:
+		return &FinalizedCheckpointEvent{}
+	case TopicChainReorg:
+		return &ChainReorgEvent{}
+	}
+	return nil
+}
+
+// Delays before reconnecting a dropped event stream. A node may replace the
+// initial delay with the stream's retry field.
+const (
+	eventReconnectDelay    = time.Second
+	maxEventReconnectDelay = 30 * time.Second
+)
+
+// SubscribeEvents streams events on topics until ctx is done, when the
+// returned channel is closed. The first connection is made before
+// returning so that a bad endpoint or topic fails at once; after that a
+// dropped stream is reconnected with backoff, resuming from the last event
+// ID the node sent. Events are delivered in order and the stream waits for
+// the reader, so a slow consumer delays but does not lose events.
+func (c *Client) SubscribeEvents(ctx context.Context, topics ...string) (<-chan Event, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("no event topics given")
+	}
+	for _, topic := range topics {
+		if newEvent(topic) == nil {
+			return nil, fmt.Errorf("unknown event topic %q", topic)
+		}
+	}
+
+	s := &eventStream{client: c, path: "/eth/v1/events?topics=" + strings.Join(topics, ","), delay: eventReconnectDelay}
+	body, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for {
+			err := s.read(ctx, body, events)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Beacon event stream dropped (%v); reconnecting", err)
+
+			for body = nil; body == nil; {
+				timer := time.NewTimer(s.delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				if body, err = s.connect(ctx); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					s.delay = min(2*s.delay, maxEventReconnectDelay)
+					log.Printf("Could not reconnect the beacon event stream (%v); retrying in %v", err, s.delay)
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// eventStream is the state kept across reconnections of one subscription
+type eventStream struct {
+	client      *Client
+	path        string
+	lastEventID string
+	delay       time.Duration
+	// retry is the reconnection delay last requested by the node
+	retry time.Duration
+}
+
+// connect opens the stream, asking the node to replay events after the last
+// one received
+func (s *eventStream) connect(ctx context.Context) (io.ReadCloser, error) {
+	header := http.Header{"Accept": {"text/event-stream"}}
+	if s.lastEventID != "" {
+		header.Set("Last-Event-ID", s.lastEventID)
+	}
+	resp, err := s.client.request(ctx, s.path, header, true)
+	if err != nil {
+		return nil, fmt.Errorf("error subscribing to events: %w", err)
+	}
+	url := resp.Request.URL.String()
+	if err := checkResponse(resp, url, ErrNotFound); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		resp.Body.Close()
+		return nil, &DecodeError{URL: url, Err: fmt.Errorf("content type %q, expected an event stream", contentType)}
+	}
+
+	s.delay = eventReconnectDelay
+	if s.retry > 0 {
+		s.delay = s.retry
+	}
+	return resp.Body, nil
+}
+
+// maxEventSize bounds a single line of the event stream
+const maxEventSize = 1 << 20
+
+// read dispatches the events of one connection until it ends, and closes it
+func (s *eventStream) read(ctx context.Context, body io.ReadCloser, events chan<- Event) error {
+	defer body.Close()
+	// Closing the body also ends a read blocked waiting for the next event
+	stop := context.AfterFunc(ctx, func() { body.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	var topic, id string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line ends the event
+			if len(data) > 0 {
+				if id != "" {
+					s.lastEventID = id
+				}
+				if event := parseEvent(topic, strings.Join(data, "\n")); event != nil {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+			topic, id, data = "", "", nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			// Comment, sent by some nodes as a keep-alive
+		case "event":
+			topic = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			id = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("stream closed by the node")
+}
+
+// parseEvent decodes an event's data, logging and dropping malformed or
+// unknown events
+func parseEvent(topic, data string) Event {
+	event := newEvent(topic)
+	if event == nil {
+		log.Printf("Ignoring beacon event of unknown topic %q", topic)
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), event); err != nil {
+		log.Printf("Ignoring malformed %s event: %v", topic, err)
+		return nil
+	}
+	return event
+}
@@ -0,0 +1,129 @@
+package beacon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// eventServer streams a batch of events per connection and then hangs up;
+// lastIDs records the Last-Event-ID of every connection
+func eventServer(t *testing.T, batches [][]string, lastIDs *[]string) *httptest.Server {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/events" || r.Header.Get("Accept") != "text/event-stream" {
+			http.NotFound(w, r)
+			return
+		}
+		n := int(connections.Add(1)) - 1
+		*lastIDs = append(*lastIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		if n >= len(batches) {
+			// Keep the last connection open until the client leaves
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		for _, event := range batches[n] {
+			fmt.Fprint(w, event)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("event channel closed early")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return nil
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	var lastIDs []string
+	server := eventServer(t, [][]string{
+		{
+			"retry: 10\n\n",
+			": keep-alive\n\n",
+			"event: head\nid: 1\ndata: {\"slot\":\"10\",\"block\":\"0xaa\",\"state\":\"0xbb\",\"epoch_transition\":false}\n\n",
+			"event: block\nid: 2\ndata: {\"slot\":\"11\",\"block\":\"0xcc\",\"execution_optimistic\":true}\n\n",
+			"event: head\nid: 3\ndata: {not json}\n\n",
+		},
+		{
+			"event: chain_reorg\nid: 4\ndata: {\"slot\":\"12\",\"depth\":\"2\",\n",
+			"data: \"old_head_block\":\"0x01\",\"new_head_block\":\"0x02\",\"epoch\":\"0\"}\n\n",
+			"event: finalized_checkpoint\nid: 5\ndata: {\"block\":\"0xdd\",\"state\":\"0xee\",\"epoch\":\"3\"}\n\n",
+		},
+	}, &lastIDs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := NewClient(server.URL)
+	events, err := client.SubscribeEvents(ctx, TopicHead, TopicBlock, TopicChainReorg, TopicFinalizedCheckpoint)
+	if err != nil {
+		t.Fatalf("SubscribeEvents() error = %v", err)
+	}
+
+	head, ok := nextEvent(t, events).(*HeadEvent)
+	if !ok || head.Slot != "10" || head.Block != "0xaa" || head.State != "0xbb" {
+		t.Errorf("first event = %+v, want the head at slot 10", head)
+	}
+	block, ok := nextEvent(t, events).(*BlockEvent)
+	if !ok || block.Slot != "11" || !block.ExecutionOptimistic {
+		t.Errorf("second event = %+v, want the optimistic block at slot 11", block)
+	}
+	// The malformed head is dropped and the stream reconnects
+	reorg, ok := nextEvent(t, events).(*ChainReorgEvent)
+	if !ok || reorg.Depth != "2" || reorg.OldHeadBlock != "0x01" || reorg.NewHeadBlock != "0x02" {
+		t.Errorf("third event = %+v, want the reorg split over two data lines", reorg)
+	}
+	finalized, ok := nextEvent(t, events).(*FinalizedCheckpointEvent)
+	if !ok || finalized.Epoch != "3" || finalized.Block != "0xdd" {
+		t.Errorf("fourth event = %+v, want the checkpoint at epoch 3", finalized)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("received an event after cancelling")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event channel not closed after cancelling")
+	}
+
+	if len(lastIDs) < 2 || lastIDs[0] != "" || lastIDs[1] != "3" {
+		t.Errorf("Last-Event-ID per connection = %q, want \"\" then \"3\"", lastIDs)
+	}
+}
+
+func TestSubscribeEventsErrors(t *testing.T) {
+	var lastIDs []string
+	server := eventServer(t, nil, &lastIDs)
+	client := NewClient(server.URL)
+	client.Retry = RetryPolicy{}
+
+	if _, err := client.SubscribeEvents(context.Background()); err == nil {
+		t.Error("SubscribeEvents() without topics expected error, got nil")
+	}
+	if _, err := client.SubscribeEvents(context.Background(), TopicHead, "attestation"); err == nil {
+		t.Error("SubscribeEvents() with an unsupported topic expected error, got nil")
+	}
+
+	client.BaseURL += "/missing"
+	if _, err := client.SubscribeEvents(context.Background(), TopicHead); err == nil {
+		t.Error("SubscribeEvents() against a missing endpoint expected error, got nil")
+	}
+}
@@ -0,0 +1,332 @@
+package beacon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Fork names as reported in the beacon API "version" fields
+const (
+	Capella = "capella"
+	Deneb   = "deneb"
+	Electra = "electra"
+	Fulu    = "fulu"
+)
+
+// Path roots that descend from the block header into the body or the state
+const (
+	BodyPathRoot  = "body"
+	StatePathRoot = "state"
+)
+
+// PathRootFields maps each path root to the header field committing to it
+var PathRootFields = map[string]string{
+	BodyPathRoot:  "body_root",
+	StatePathRoot: "state_root",
+}
+
+// Schema holds the container layouts of one fork
+type Schema struct {
+	Fork                   string
+	BeaconBlockHeader      *ssz.Type
+	BeaconBlockBody        *ssz.Type
+	BlindedBeaconBlockBody *ssz.Type
+	ExecutionPayload       *ssz.Type
+	ExecutionPayloadHeader *ssz.Type
+	BeaconState            *ssz.Type
+	// Signed block layouts, needed only to decode SSZ responses
+	SignedBeaconBlock        *ssz.Type
+	SignedBlindedBeaconBlock *ssz.Type
+}
+
+// Forks lists the supported forks in activation order
+var Forks = []string{Capella, Deneb, Electra, Fulu}
+
+var schemas = map[string]*Schema{
+	Capella: {
+		Fork:                     Capella,
+		BeaconBlockHeader:        BeaconBlockHeaderType,
+		BeaconBlockBody:          BeaconBlockBodyCapellaType,
+		BlindedBeaconBlockBody:   BlindedBeaconBlockBodyCapellaType,
+		ExecutionPayload:         ExecutionPayloadCapellaType,
+		ExecutionPayloadHeader:   ExecutionPayloadHeaderCapellaType,
+		BeaconState:              BeaconStateCapellaType,
+		SignedBeaconBlock:        signedBlock(BeaconBlockBodyCapellaType),
+		SignedBlindedBeaconBlock: signedBlock(BlindedBeaconBlockBodyCapellaType),
+	},
+	Deneb: {
+		Fork:                     Deneb,
+		BeaconBlockHeader:        BeaconBlockHeaderType,
+		BeaconBlockBody:          BeaconBlockBodyDenebType,
+		BlindedBeaconBlockBody:   BlindedBeaconBlockBodyDenebType,
+		ExecutionPayload:         ExecutionPayloadDenebType,
+		ExecutionPayloadHeader:   ExecutionPayloadHeaderDenebType,
+		BeaconState:              BeaconStateDenebType,
+		SignedBeaconBlock:        signedBlock(BeaconBlockBodyDenebType),
+		SignedBlindedBeaconBlock: signedBlock(BlindedBeaconBlockBodyDenebType),
+	},
+	Electra: {
+		Fork:                     Electra,
+		BeaconBlockHeader:        BeaconBlockHeaderType,
+		BeaconBlockBody:          BeaconBlockBodyElectraType,
+		BlindedBeaconBlockBody:   BlindedBeaconBlockBodyElectraType,
+		ExecutionPayload:         ExecutionPayloadDenebType,
+		ExecutionPayloadHeader:   ExecutionPayloadHeaderDenebType,
+		BeaconState:              BeaconStateElectraType,
+		SignedBeaconBlock:        signedBlock(BeaconBlockBodyElectraType),
+		SignedBlindedBeaconBlock: signedBlock(BlindedBeaconBlockBodyElectraType),
+	},
+	Fulu: {
+		Fork:                     Fulu,
+		BeaconBlockHeader:        BeaconBlockHeaderType,
+		BeaconBlockBody:          BeaconBlockBodyElectraType,
+		BlindedBeaconBlockBody:   BlindedBeaconBlockBodyElectraType,
+		ExecutionPayload:         ExecutionPayloadDenebType,
+		ExecutionPayloadHeader:   ExecutionPayloadHeaderDenebType,
+		BeaconState:              BeaconStateFuluType,
+		SignedBeaconBlock:        signedBlock(BeaconBlockBodyElectraType),
+		SignedBlindedBeaconBlock: signedBlock(BlindedBeaconBlockBodyElectraType),
+	},
+}
+
+// SchemaFor returns the container layouts of a fork by name
+func SchemaFor(fork string) (*Schema, error) {
+	schema, ok := schemas[strings.ToLower(fork)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported fork %q. Must be one of %v", fork, Forks)
+	}
+	return schema, nil
+}
+
+// Locate resolves a path relative to the beacon block root. Header fields
+// are addressed directly; paths starting with "body" or "state" continue
+// below body_root and state_root into the fork's body and state layouts.
+func (s *Schema) Locate(path ...string) (ssz.Location, error) {
+	if len(path) == 0 {
+		return s.BeaconBlockHeader.Locate()
+	}
+
+	field, ok := PathRootFields[path[0]]
+	if !ok {
+		loc, err := s.BeaconBlockHeader.Locate(path...)
+		if _, known := s.BeaconBlockHeader.FieldIndex(path[0]); err != nil && !known {
+			return ssz.Location{}, fmt.Errorf("%w (or %q / %q)", err, BodyPathRoot, StatePathRoot)
+		}
+		return loc, err
+	}
+
+	outer, err := s.BeaconBlockHeader.GeneralizedIndex(field)
+	if err != nil {
+		return ssz.Location{}, err
+	}
+	loc, err := s.Container(path[0]).Locate(path[1:]...)
+	if err != nil {
+		return ssz.Location{}, err
+	}
+	loc.GeneralizedIndex, err = merkle.ConcatGeneralizedIndices(outer, loc.GeneralizedIndex)
+	if err != nil {
+		return ssz.Location{}, err
+	}
+	return loc, nil
+}
+
+// Container returns the layout a path root descends into, or nil
+func (s *Schema) Container(pathRoot string) *ssz.Type {
+	switch pathRoot {
+	case BodyPathRoot:
+		return s.BeaconBlockBody
+	case StatePathRoot:
+		return s.BeaconState
+	}
+	return nil
+}
+
+// GeneralizedIndex returns the generalized index of a path relative to the
+// beacon block root
+func (s *Schema) GeneralizedIndex(path ...string) (uint64, error) {
+	loc, err := s.Locate(path...)
+	if err != nil {
+		return 0, err
+	}
+	return loc.GeneralizedIndex, nil
+}
+
+// PathOf decodes a generalized index relative to the beacon block root back
+// into a path, continuing below body_root and state_root into the fork's
+// body and state layouts
+func (s *Schema) PathOf(gindex uint64) ([]string, error) {
+	path, rest, err := s.BeaconBlockHeader.PathOf(gindex)
+	if err != nil {
+		return nil, err
+	}
+	if rest == 1 {
+		return path, nil
+	}
+
+	for root, field := range PathRootFields {
+		if len(path) != 1 || path[0] != field {
+			continue
+		}
+		inner, innerRest, err := s.Container(root).PathOf(rest)
+		if err != nil {
+			return nil, err
+		}
+		if innerRest != 1 {
+			return nil, fmt.Errorf("generalized index %d points inside the value at %s", gindex,
+				ssz.FormatPath(append([]string{root}, inner...)))
+		}
+		return append([]string{root}, inner...), nil
+	}
+	return nil, fmt.Errorf("generalized index %d points inside the value at %s", gindex, ssz.FormatPath(path))
+}
+
+// ForkEpoch is the activation of one fork on a chain
+type ForkEpoch struct {
+	Name    string
+	Epoch   uint64
+	Version [4]byte
+}
+
+// ForkSchedule lists fork activations of a chain in epoch order
+type ForkSchedule struct {
+	Forks         []ForkEpoch
+	SlotsPerEpoch uint64
+	// GenesisValidatorsRoot is zero when unknown, which rules out computing
+	// signing domains
+	GenesisValidatorsRoot [32]byte
+}
+
+// NewForkSchedule builds a schedule, sorting the activations by epoch
+func NewForkSchedule(slotsPerEpoch uint64, forks ...ForkEpoch) ForkSchedule {
+	sorted := make([]ForkEpoch, len(forks))
+	copy(sorted, forks)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Epoch < sorted[j].Epoch })
+	return ForkSchedule{Forks: sorted, SlotsPerEpoch: slotsPerEpoch}
+}
+
+// ForkAtEpoch returns the latest fork activated at or before epoch
+func (s ForkSchedule) ForkAtEpoch(epoch uint64) (ForkEpoch, error) {
+	for i := len(s.Forks) - 1; i >= 0; i-- {
+		if s.Forks[i].Epoch <= epoch {
+			return s.Forks[i], nil
+		}
+	}
+	return ForkEpoch{}, fmt.Errorf("no supported fork is active at epoch %d", epoch)
+}
+
+// ForkAtSlot returns the fork active at slot
+func (s ForkSchedule) ForkAtSlot(slot uint64) (ForkEpoch, error) {
+	if s.SlotsPerEpoch == 0 {
+		return ForkEpoch{}, fmt.Errorf("fork schedule has no slots per epoch")
+	}
+	return s.ForkAtEpoch(slot / s.SlotsPerEpoch)
+}
+
+// SchemaAtSlot returns the container layouts in effect at slot
+func (s ForkSchedule) SchemaAtSlot(slot uint64) (*Schema, error) {
+	fork, err := s.ForkAtSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	return SchemaFor(fork.Name)
+}
+
+// Fork returns the activation of a fork by name
+func (s ForkSchedule) Fork(name string) (ForkEpoch, bool) {
+	for _, f := range s.Forks {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return ForkEpoch{}, false
+}
+
+// HistoricalSummaryIndex returns the index in state.historical_summaries of
+// the summary covering slot. Summaries start with the Capella period.
+func (s ForkSchedule) HistoricalSummaryIndex(slot uint64) (uint64, error) {
+	capella, ok := s.Fork(Capella)
+	if !ok {
+		return 0, fmt.Errorf("fork schedule has no Capella activation")
+	}
+	first := capella.Epoch * s.SlotsPerEpoch / SlotsPerHistoricalRoot
+	period := slot / SlotsPerHistoricalRoot
+	if period < first {
+		return 0, fmt.Errorf("slot %d predates historical summaries (first period %d)", slot, first)
+	}
+	return period - first, nil
+}
+
+// knownForkSchedules holds the supported fork activations of public chains,
+// keyed by execution chain ID
+var knownForkSchedules = map[int]ForkSchedule{
+	// Mainnet
+	1: NewForkSchedule(SlotsPerEpoch,
+		ForkEpoch{Capella, 194048, [4]byte{0x03, 0x00, 0x00, 0x00}},
+		ForkEpoch{Deneb, 269568, [4]byte{0x04, 0x00, 0x00, 0x00}},
+		ForkEpoch{Electra, 364032, [4]byte{0x05, 0x00, 0x00, 0x00}},
+		ForkEpoch{Fulu, 411392, [4]byte{0x06, 0x00, 0x00, 0x00}},
+	).WithGenesisValidatorsRoot("0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"),
+	// Holesky
+	17000: NewForkSchedule(SlotsPerEpoch,
+		ForkEpoch{Capella, 256, [4]byte{0x04, 0x01, 0x70, 0x00}},
+		ForkEpoch{Deneb, 29696, [4]byte{0x05, 0x01, 0x70, 0x00}},
+		ForkEpoch{Electra, 115968, [4]byte{0x06, 0x01, 0x70, 0x00}},
+		ForkEpoch{Fulu, 165120, [4]byte{0x07, 0x01, 0x70, 0x00}},
+	).WithGenesisValidatorsRoot("0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1"),
+	// Sepolia
+	11155111: NewForkSchedule(SlotsPerEpoch,
+		ForkEpoch{Capella, 56832, [4]byte{0x90, 0x00, 0x00, 0x72}},
+		ForkEpoch{Deneb, 132608, [4]byte{0x90, 0x00, 0x00, 0x73}},
+		ForkEpoch{Electra, 222464, [4]byte{0x90, 0x00, 0x00, 0x74}},
+		ForkEpoch{Fulu, 272640, [4]byte{0x90, 0x00, 0x00, 0x75}},
+	).WithGenesisValidatorsRoot("0xd8ea171f3c94aea21ebc42a1ed61052acf3f9209c00e4efbaaddac09ed9b8078"),
+	// Hoodi
+	560048: NewForkSchedule(SlotsPerEpoch,
+		ForkEpoch{Capella, 0, [4]byte{0x40, 0x00, 0x09, 0x10}},
+		ForkEpoch{Deneb, 0, [4]byte{0x50, 0x00, 0x09, 0x10}},
+		ForkEpoch{Electra, 2048, [4]byte{0x60, 0x00, 0x09, 0x10}},
+		ForkEpoch{Fulu, 50688, [4]byte{0x70, 0x00, 0x09, 0x10}},
+	).WithGenesisValidatorsRoot("0x212f13fc4df078b6cb7db228f1c8307566dcecf900867401a92023d7ba99cb5f"),
+}
+
+// knownGenesisTimes holds the genesis times of the chains in
+// knownForkSchedules, keyed by execution chain ID
+var knownGenesisTimes = map[int]uint64{
+	1:        1606824023, // Mainnet
+	17000:    1695902400, // Holesky
+	11155111: 1655733600, // Sepolia
+	560048:   1742213400, // Hoodi
+}
+
+// GenesisTimeForChain returns the genesis time of a known chain
+func GenesisTimeForChain(chainID int) (uint64, error) {
+	genesis, ok := knownGenesisTimes[chainID]
+	if !ok {
+		return 0, fmt.Errorf("no genesis time known for chain ID %d", chainID)
+	}
+	return genesis, nil
+}
+
+// ForkScheduleForChain returns the fork schedule of a known chain
+func ForkScheduleForChain(chainID int) (ForkSchedule, error) {
+	schedule, ok := knownForkSchedules[chainID]
+	if !ok {
+		return ForkSchedule{}, fmt.Errorf("no fork schedule known for chain ID %d", chainID)
+	}
+	return schedule, nil
+}
+
+// ChainIDForGenesisValidatorsRoot returns the chain ID of the known chain
+// with a genesis validators root. ok is false for other chains.
+func ChainIDForGenesisValidatorsRoot(root [32]byte) (chainID int, ok bool) {
+	for id, schedule := range knownForkSchedules {
+		if schedule.GenesisValidatorsRoot == root {
+			return id, true
+		}
+	}
+	return 0, false
+}
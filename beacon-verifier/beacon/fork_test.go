@@ -0,0 +1,275 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+func TestBeaconStateGeneralizedIndices(t *testing.T) {
+	tests := []struct {
+		fork   string
+		path   []string
+		gindex uint64
+	}{
+		// FINALIZED_ROOT_GINDEX, CURRENT_SYNC_COMMITTEE_GINDEX and
+		// NEXT_SYNC_COMMITTEE_GINDEX from the light client specs
+		{Capella, []string{"finalized_checkpoint", "root"}, 105},
+		{Deneb, []string{"finalized_checkpoint", "root"}, 105},
+		{Deneb, []string{"current_sync_committee"}, 54},
+		{Deneb, []string{"next_sync_committee"}, 55},
+		{Electra, []string{"finalized_checkpoint", "root"}, 169},
+		{Electra, []string{"current_sync_committee"}, 86},
+		{Electra, []string{"next_sync_committee"}, 87},
+		{Fulu, []string{"finalized_checkpoint", "root"}, 169},
+		{Deneb, []string{"randao_mixes", "5"}, (32+13)<<16 | 5},
+		{Electra, []string{"randao_mixes", "5"}, (64+13)<<16 | 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fork+" "+ssz.FormatPath(tt.path), func(t *testing.T) {
+			schema, err := SchemaFor(tt.fork)
+			if err != nil {
+				t.Fatalf("SchemaFor() error = %v", err)
+			}
+			got, err := schema.BeaconState.GeneralizedIndex(tt.path...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			if got != tt.gindex {
+				t.Errorf("GeneralizedIndex() = %d, want %d", got, tt.gindex)
+			}
+		})
+	}
+}
+
+func TestSchemaLocateFromBlockRoot(t *testing.T) {
+	tests := []struct {
+		name   string
+		fork   string
+		path   []string
+		gindex uint64
+	}{
+		{"header field", Deneb, []string{"slot"}, 8},
+		{"body root", Deneb, []string{"body"}, 12},
+		{"state root", Deneb, []string{"state"}, 11},
+		{"body field", Deneb, []string{"body", "execution_payload"}, 12<<4 | 9},
+		{"payload field", Electra, []string{"body", "execution_payload", "timestamp"}, (12<<4|9)<<5 | 9},
+		{"Deneb state field", Deneb, []string{"state", "randao_mixes", "5"}, ((11<<5|13)<<16 | 5)},
+		{"Electra state field", Electra, []string{"state", "randao_mixes", "5"}, ((11<<6|13)<<16 | 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := SchemaFor(tt.fork)
+			if err != nil {
+				t.Fatalf("SchemaFor() error = %v", err)
+			}
+			got, err := schema.GeneralizedIndex(tt.path...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			if got != tt.gindex {
+				t.Errorf("GeneralizedIndex() = %d, want %d", got, tt.gindex)
+			}
+		})
+	}
+}
+
+func TestSchemaLocateErrors(t *testing.T) {
+	schema, err := SchemaFor(Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path []string
+	}{
+		{"unknown header field", []string{"graffiti"}},
+		{"Electra field on Deneb", []string{"body", "execution_requests"}},
+		{"Electra state field on Deneb", []string{"state", "pending_deposits"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := schema.Locate(tt.path...); err == nil {
+				t.Error("Locate() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	for _, fork := range Forks {
+		schema, err := SchemaFor(fork)
+		if err != nil {
+			t.Fatalf("SchemaFor(%s) error = %v", fork, err)
+		}
+		if schema.Fork != fork {
+			t.Errorf("SchemaFor(%s).Fork = %s", fork, schema.Fork)
+		}
+	}
+
+	if _, err := SchemaFor("ELECTRA"); err != nil {
+		t.Errorf("SchemaFor() should ignore case, got %v", err)
+	}
+	if _, err := SchemaFor("bellatrix"); err == nil {
+		t.Error("SchemaFor(bellatrix) expected error, got nil")
+	}
+}
+
+func TestForkScheduleAtSlot(t *testing.T) {
+	schedule, err := ForkScheduleForChain(17000)
+	if err != nil {
+		t.Fatalf("ForkScheduleForChain() error = %v", err)
+	}
+
+	tests := []struct {
+		slot uint64
+		fork string
+	}{
+		{256 * SlotsPerEpoch, Capella},
+		{29696*SlotsPerEpoch - 1, Capella},
+		{29696 * SlotsPerEpoch, Deneb},
+		{115968*SlotsPerEpoch - 1, Deneb},
+		{115968 * SlotsPerEpoch, Electra},
+		{165120 * SlotsPerEpoch, Fulu},
+	}
+
+	for _, tt := range tests {
+		got, err := schedule.ForkAtSlot(tt.slot)
+		if err != nil {
+			t.Fatalf("ForkAtSlot(%d) error = %v", tt.slot, err)
+		}
+		if got.Name != tt.fork {
+			t.Errorf("ForkAtSlot(%d) = %s, want %s", tt.slot, got.Name, tt.fork)
+		}
+		schema, err := schedule.SchemaAtSlot(tt.slot)
+		if err != nil {
+			t.Fatalf("SchemaAtSlot(%d) error = %v", tt.slot, err)
+		}
+		if schema.Fork != tt.fork {
+			t.Errorf("SchemaAtSlot(%d) = %s, want %s", tt.slot, schema.Fork, tt.fork)
+		}
+	}
+
+	if _, err := schedule.ForkAtSlot(0); err == nil {
+		t.Error("ForkAtSlot(0) expected error before Capella, got nil")
+	}
+	if _, err := ForkScheduleForChain(12345); err == nil {
+		t.Error("ForkScheduleForChain(12345) expected error, got nil")
+	}
+}
+
+func TestChainIDForGenesisValidatorsRoot(t *testing.T) {
+	for _, chainID := range []int{1, 17000} {
+		schedule, err := ForkScheduleForChain(chainID)
+		if err != nil {
+			t.Fatalf("ForkScheduleForChain(%d) error = %v", chainID, err)
+		}
+		if got, ok := ChainIDForGenesisValidatorsRoot(schedule.GenesisValidatorsRoot); !ok || got != chainID {
+			t.Errorf("ChainIDForGenesisValidatorsRoot() = %d, %v, want %d", got, ok, chainID)
+		}
+	}
+	if _, ok := ChainIDForGenesisValidatorsRoot([32]byte{1}); ok {
+		t.Error("ChainIDForGenesisValidatorsRoot() of an unknown root reported a chain")
+	}
+}
+
+func TestBeaconStateZeroValuesHash(t *testing.T) {
+	for _, fork := range Forks {
+		schema, err := SchemaFor(fork)
+		if err != nil {
+			t.Fatalf("SchemaFor(%s) error = %v", fork, err)
+		}
+		for _, typ := range []*ssz.Type{schema.BeaconBlockBody, schema.BeaconState} {
+			if _, err := ssz.HashTreeRoot(typ, ssz.Zero(typ)); err != nil {
+				t.Errorf("HashTreeRoot(%s) error = %v", typ.Name, err)
+			}
+		}
+	}
+}
+
+func TestSchemaPathOf(t *testing.T) {
+	paths := [][]string{
+		{"slot"},
+		{"body", "execution_payload", "timestamp"},
+		{"body", "execution_requests", "deposits", "0"},
+		{"state", "finalized_checkpoint", "root"},
+		{"state", "validators", "123", "effective_balance"},
+		{"state", "randao_mixes", "65535"},
+	}
+
+	schema, err := SchemaFor(Electra)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	for _, path := range paths {
+		t.Run(ssz.FormatPath(path), func(t *testing.T) {
+			gindex, err := schema.GeneralizedIndex(path...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			got, err := schema.PathOf(gindex)
+			if err != nil {
+				t.Fatalf("PathOf(%d) error = %v", gindex, err)
+			}
+			if ssz.FormatPath(got) != ssz.FormatPath(path) {
+				t.Errorf("PathOf(%d) = %s, want %s", gindex, ssz.FormatPath(got), ssz.FormatPath(path))
+			}
+		})
+	}
+
+	// The second chunk of the 48-byte validator pubkey has no name
+	pubkey, err := schema.GeneralizedIndex("state", "validators", "0", "pubkey")
+	if err != nil {
+		t.Fatalf("GeneralizedIndex() error = %v", err)
+	}
+	if _, err := schema.PathOf(pubkey<<1 | 1); err == nil {
+		t.Error("PathOf() inside a byte vector expected error, got nil")
+	}
+}
+
+func TestHistoricalSummaryIndex(t *testing.T) {
+	schedule, err := ForkScheduleForChain(1)
+	if err != nil {
+		t.Fatalf("ForkScheduleForChain() error = %v", err)
+	}
+
+	// Mainnet Capella activated at slot 6209536, the start of period 758
+	capellaSlot := uint64(194048 * SlotsPerEpoch)
+	tests := []struct {
+		slot  uint64
+		index uint64
+	}{
+		{capellaSlot, 0},
+		{capellaSlot + SlotsPerHistoricalRoot - 1, 0},
+		{capellaSlot + 3*SlotsPerHistoricalRoot + 5, 3},
+	}
+	for _, tt := range tests {
+		got, err := schedule.HistoricalSummaryIndex(tt.slot)
+		if err != nil {
+			t.Fatalf("HistoricalSummaryIndex(%d) error = %v", tt.slot, err)
+		}
+		if got != tt.index {
+			t.Errorf("HistoricalSummaryIndex(%d) = %d, want %d", tt.slot, got, tt.index)
+		}
+	}
+
+	if _, err := schedule.HistoricalSummaryIndex(capellaSlot - 1); err == nil {
+		t.Error("HistoricalSummaryIndex() before Capella expected error, got nil")
+	}
+}
+
+func TestGenesisTimeForChain(t *testing.T) {
+	// Every chain with a built-in schedule also has a clock
+	for chainID := range knownForkSchedules {
+		if _, err := GenesisTimeForChain(chainID); err != nil {
+			t.Errorf("GenesisTimeForChain(%d) error = %v", chainID, err)
+		}
+	}
+	if _, err := GenesisTimeForChain(12345); err == nil {
+		t.Error("GenesisTimeForChain(12345) expected error, got nil")
+	}
+}
@@ -3,7 +3,11 @@ package beacon
 import (
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
 )
 
 // Constants from Ethereum spec
@@ -30,6 +34,8 @@ type HeaderData struct {
 	BodyRoot      string `json:"body_root"`
 	BlockRoot     string `json:"block_root"`
 	Timestamp     int64  `json:"timestamp"`
+	// Signature is the proposer's signature over the header, when known
+	Signature string `json:"signature,omitempty"`
 }
 
 // FromAPIResponse creates a BlockHeader from an API response data
@@ -105,6 +111,80 @@ func (b *BlockHeader) SerializeForMerkleization() [][]byte {
 	return serialized
 }
 
+// Value returns the header in the generic JSON shape used by the ssz package
+func (b *BlockHeader) Value() map[string]any {
+	return map[string]any{
+		"slot":           strconv.FormatUint(b.Slot, 10),
+		"proposer_index": strconv.FormatUint(b.ProposerIndex, 10),
+		"parent_root":    "0x" + hex.EncodeToString(b.ParentRoot),
+		"state_root":     "0x" + hex.EncodeToString(b.StateRoot),
+		"body_root":      "0x" + hex.EncodeToString(b.BodyRoot),
+	}
+}
+
+// HashTreeRoot computes the beacon block root of the header
+func (b *BlockHeader) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashTreeRoot(BeaconBlockHeaderType, b.Value())
+}
+
+// HashTreeRoot computes the beacon block root of the header data
+func (d HeaderData) HashTreeRoot() ([32]byte, error) {
+	var header BlockHeader
+	if err := header.FromAPIResponse(d); err != nil {
+		return [32]byte{}, fmt.Errorf("error processing header data: %w", err)
+	}
+	return header.HashTreeRoot()
+}
+
+// RootMismatchError reports that the locally computed root of a header
+// disagrees with roots the beacon API reported for it
+type RootMismatchError struct {
+	Slot     string
+	Computed string
+	// Mismatches maps each disagreeing field, "block_root" of the header
+	// response or "next.parent_root" of the following header, to its value
+	Mismatches map[string]string
+}
+
+// Error lists the disagreeing fields in a stable order
+func (e *RootMismatchError) Error() string {
+	fields := make([]string, 0, len(e.Mismatches))
+	for field := range e.Mismatches {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s %s", field, e.Mismatches[field])
+	}
+	return fmt.Sprintf("header at slot %s hashes to %s, but the API reported %s",
+		e.Slot, e.Computed, strings.Join(parts, ", "))
+}
+
+// VerifyRoot compares the locally computed root of the header with the root
+// in the API response and, when next is given, with next's parent_root. It
+// returns a *RootMismatchError naming the fields that differ.
+func (d HeaderData) VerifyRoot(next *HeaderData) error {
+	root, err := d.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	computed := "0x" + hex.EncodeToString(root[:])
+
+	mismatches := make(map[string]string)
+	if d.BlockRoot != "" && !strings.EqualFold(d.BlockRoot, computed) {
+		mismatches["block_root"] = d.BlockRoot
+	}
+	if next != nil && !strings.EqualFold(next.ParentRoot, computed) {
+		mismatches["next.parent_root"] = next.ParentRoot
+	}
+	if len(mismatches) > 0 {
+		return &RootMismatchError{Slot: d.Slot, Computed: computed, Mismatches: mismatches}
+	}
+	return nil
+}
+
 // Utility functions
 
 // Helper function to write uint64 in little-endian format
@@ -3,7 +3,9 @@ package beacon
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -229,3 +231,71 @@ func TestWriteUint64LittleEndian(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderDataVerifyRoot(t *testing.T) {
+	header := HeaderData{
+		Slot:          "123456",
+		ProposerIndex: "42",
+		ParentRoot:    "0x4a81947b35bdc11471fc7b42350427a3b9d2b92bf21d423ded6dcc5c66caad0e",
+		StateRoot:     "0x5bc9a4ef3cf09a315ffbc12872de6cc412a7abb55a5228cc21fbdb5fb797d7a8",
+		BodyRoot:      "0x67df26e0c9f5de4fe7b3f66f3591f84a9cf6e8cda7f5b3f23db5c3967a505c31",
+	}
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	rootHex := "0x" + hex.EncodeToString(root[:])
+	other := "0x" + strings.Repeat("11", 32)
+
+	tests := []struct {
+		name           string
+		blockRoot      string
+		nextParentRoot string
+		wantFields     []string
+	}{
+		{"All match", rootHex, rootHex, nil},
+		{"No API root", "", rootHex, nil},
+		{"Upper case hex", "0x" + strings.ToUpper(rootHex[2:]), "", nil},
+		{"API root differs", other, rootHex, []string{"block_root"}},
+		{"Next parent differs", rootHex, other, []string{"next.parent_root"}},
+		{"Both differ", other, other, []string{"block_root", "next.parent_root"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := header
+			h.BlockRoot = tt.blockRoot
+			var next *HeaderData
+			if tt.nextParentRoot != "" {
+				next = &HeaderData{ParentRoot: tt.nextParentRoot}
+			}
+
+			err := h.VerifyRoot(next)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Errorf("VerifyRoot() error = %v", err)
+				}
+				return
+			}
+
+			var mismatch *RootMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("VerifyRoot() error = %v, want *RootMismatchError", err)
+			}
+			if mismatch.Computed != rootHex {
+				t.Errorf("Computed = %s, want %s", mismatch.Computed, rootHex)
+			}
+			if len(mismatch.Mismatches) != len(tt.wantFields) {
+				t.Errorf("Mismatches = %v, want fields %v", mismatch.Mismatches, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := mismatch.Mismatches[field]; !ok {
+					t.Errorf("Mismatches = %v, missing %s", mismatch.Mismatches, field)
+				}
+				if !strings.Contains(err.Error(), field) {
+					t.Errorf("Error() = %q, should name %s", err, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// LightClientData is a light client bootstrap, update, finality update or
+// optimistic update from /eth/v1/beacon/light_client, kept in the generic
+// JSON shape like Block
+type LightClientData struct {
+	Version string
+	Data    map[string]any
+}
+
+// lightClientEnvelope is the wire format of a light client response
+type lightClientEnvelope struct {
+	Version string         `json:"version"`
+	Data    map[string]any `json:"data"`
+}
+
+// FetchLightClientBootstrap fetches the bootstrap for a trusted block root,
+// which should be a finalized epoch boundary block
+func (c *Client) FetchLightClientBootstrap(ctx context.Context, blockRoot string) (*LightClientData, error) {
+	var envelope lightClientEnvelope
+	if err := c.fetchJSON(ctx, fmt.Sprintf("/eth/v1/beacon/light_client/bootstrap/%s", blockRoot), &envelope); err != nil {
+		return nil, fmt.Errorf("error fetching light client bootstrap: %w", err)
+	}
+	return envelope.lightClientData()
+}
+
+// FetchLightClientUpdates fetches the best update of count consecutive sync
+// committee periods starting at startPeriod. Nodes serve at most
+// MaxRequestLightClientUpdates per request and may return fewer.
+func (c *Client) FetchLightClientUpdates(ctx context.Context, startPeriod, count uint64) ([]*LightClientData, error) {
+	var envelopes []lightClientEnvelope
+	path := fmt.Sprintf("/eth/v1/beacon/light_client/updates?start_period=%d&count=%d", startPeriod, count)
+	if err := c.fetchJSON(ctx, path, &envelopes); err != nil {
+		return nil, fmt.Errorf("error fetching light client updates: %w", err)
+	}
+
+	updates := make([]*LightClientData, len(envelopes))
+	for i, envelope := range envelopes {
+		update, err := envelope.lightClientData()
+		if err != nil {
+			return nil, fmt.Errorf("update %d: %w", i, err)
+		}
+		updates[i] = update
+	}
+	return updates, nil
+}
+
+// FetchLightClientFinalityUpdate fetches the latest finality update
+func (c *Client) FetchLightClientFinalityUpdate(ctx context.Context) (*LightClientData, error) {
+	var envelope lightClientEnvelope
+	if err := c.fetchJSON(ctx, "/eth/v1/beacon/light_client/finality_update", &envelope); err != nil {
+		return nil, fmt.Errorf("error fetching light client finality update: %w", err)
+	}
+	return envelope.lightClientData()
+}
+
+// FetchLightClientOptimisticUpdate fetches the latest optimistic update
+func (c *Client) FetchLightClientOptimisticUpdate(ctx context.Context) (*LightClientData, error) {
+	var envelope lightClientEnvelope
+	if err := c.fetchJSON(ctx, "/eth/v1/beacon/light_client/optimistic_update", &envelope); err != nil {
+		return nil, fmt.Errorf("error fetching light client optimistic update: %w", err)
+	}
+	return envelope.lightClientData()
+}
+
+// fetchJSON fetches and decodes a JSON response, mapping 404 to ErrNotFound
+func (c *Client) fetchJSON(ctx context.Context, path string, out any) error {
+	resp, err := c.get(ctx, path, "", false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	url := resp.Request.URL.String()
+
+	if err := checkResponse(resp, url, ErrNotFound); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return &DecodeError{URL: url, Err: err}
+	}
+	return nil
+}
+
+func (e lightClientEnvelope) lightClientData() (*LightClientData, error) {
+	if e.Data == nil {
+		return nil, errors.New("light client response has no data")
+	}
+	return &LightClientData{Version: e.Version, Data: e.Data}, nil
+}
@@ -0,0 +1,237 @@
+package beacon
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Pool spreads a client's requests over several beacon nodes serving the
+// same chain. Each request goes to the healthiest endpoint, judged by
+// recent latency and error rate; an endpoint that keeps failing is taken out
+// of rotation until a probe finds it healthy again.
+type Pool struct {
+	// HTTPClient sends the probes; nil uses DefaultHTTPClient
+	HTTPClient *http.Client
+	// ProbeTimeout bounds each health probe
+	ProbeTimeout time.Duration
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+// Health tracking parameters
+const (
+	// healthDecay is the weight of the newest observation in the moving
+	// averages of latency and error rate
+	healthDecay = 0.2
+	// maxConsecutiveFailures takes an endpoint out of rotation
+	maxConsecutiveFailures = 3
+	// errorPenalty scales latency by the error rate when ranking endpoints
+	errorPenalty = 10
+	// unmeasuredLatency stands in for the latency of an endpoint that has
+	// only failed so far
+	unmeasuredLatency = time.Second
+)
+
+// endpoint is the health record of one node
+type endpoint struct {
+	url       string
+	latency   time.Duration
+	errorRate float64
+	failures  int
+	down      bool
+	requests  uint64
+}
+
+// EndpointStats is a snapshot of an endpoint's health
+type EndpointStats struct {
+	URL       string
+	Latency   time.Duration
+	ErrorRate float64
+	Down      bool
+	Requests  uint64
+}
+
+// NewPool creates a pool over urls; the first is preferred while the
+// endpoints are equally healthy
+func NewPool(urls ...string) *Pool {
+	pool := &Pool{ProbeTimeout: 5 * time.Second}
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &endpoint{url: url})
+	}
+	return pool
+}
+
+// score ranks endpoints; lower is better. An endpoint that has not been
+// used yet scores zero so that it is measured early on.
+func (e *endpoint) score() float64 {
+	latency := e.latency
+	if latency == 0 && e.errorRate > 0 {
+		latency = unmeasuredLatency
+	}
+	return float64(latency) * (1 + errorPenalty*e.errorRate)
+}
+
+// pick returns the healthiest endpoint not in tried. Endpoints that are down
+// are used only when nothing else is left, and tried ones only when every
+// endpoint has been tried.
+func (p *Pool) pick(tried []*endpoint) *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *endpoint
+	rank := func(e *endpoint) (int, float64) {
+		class := 0
+		if e.down {
+			class++
+		}
+		if slices.Contains(tried, e) {
+			class += 2
+		}
+		return class, e.score()
+	}
+	for _, e := range p.endpoints {
+		if best == nil {
+			best = e
+			continue
+		}
+		class, score := rank(e)
+		bestClass, bestScore := rank(best)
+		if class < bestClass || class == bestClass && score < bestScore {
+			best = e
+		}
+	}
+	best.requests++
+	return best
+}
+
+// untried reports whether an endpoint outside tried is still in rotation
+func (p *Pool) untried(tried []*endpoint) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.endpoints {
+		if !e.down && !slices.Contains(tried, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// observe records the outcome of a request to e
+func (p *Pool) observe(e *endpoint, latency time.Duration, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	e.errorRate += healthDecay * (failure - e.errorRate)
+	if !failed {
+		if e.latency == 0 {
+			e.latency = latency
+		} else {
+			e.latency += time.Duration(healthDecay * float64(latency-e.latency))
+		}
+		e.failures = 0
+		return
+	}
+	e.failures++
+	if !e.down && e.failures >= maxConsecutiveFailures {
+		e.down = true
+		log.Printf("Beacon endpoint %s failed %d times in a row, taking it out of rotation", e.url, e.failures)
+	}
+}
+
+// Probe checks every endpoint that is out of rotation against the node
+// health endpoint and restores those that answer
+func (p *Pool) Probe(ctx context.Context) {
+	p.mu.Lock()
+	var down []*endpoint
+	for _, e := range p.endpoints {
+		if e.down {
+			down = append(down, e)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, e := range down {
+		start := time.Now()
+		if err := p.probe(ctx, e.url); err != nil {
+			log.Printf("Beacon endpoint %s still unhealthy: %v", e.url, err)
+			continue
+		}
+		p.mu.Lock()
+		e.down = false
+		e.failures = 0
+		e.latency = time.Since(start)
+		p.mu.Unlock()
+		log.Printf("Beacon endpoint %s is healthy again", e.url)
+	}
+}
+
+// probe asks a node whether it is synced. The health endpoint answers 200
+// when ready and 206 while syncing; only the former counts as healthy.
+func (p *Pool) probe(ctx context.Context, url string) error {
+	if p.ProbeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.ProbeTimeout)
+		defer cancel()
+	}
+	healthURL := url + "/eth/v1/node/health"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return err
+	}
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &TransportError{URL: healthURL, Err: err}
+	}
+	defer resp.Body.Close()
+	io.CopyN(io.Discard, resp.Body, maxErrorBody)
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{URL: healthURL, StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	return nil
+}
+
+// Run probes endpoints that are out of rotation every interval until ctx
+// is done
+func (p *Pool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Probe(ctx)
+		}
+	}
+}
+
+// Stats returns the health of every endpoint in configuration order
+func (p *Pool) Stats() []EndpointStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]EndpointStats, len(p.endpoints))
+	for i, e := range p.endpoints {
+		stats[i] = EndpointStats{
+			URL:       e.url,
+			Latency:   e.latency,
+			ErrorRate: e.errorRate,
+			Down:      e.down,
+			Requests:  e.requests,
+		}
+	}
+	return stats
+}
@@ -0,0 +1,166 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// poolNode is a test beacon node whose health can be switched
+type poolNode struct {
+	url      string
+	healthy  atomic.Bool
+	requests atomic.Int32
+}
+
+func newPoolNode(t *testing.T, healthy bool) *poolNode {
+	node := &poolNode{}
+	node.healthy.Store(healthy)
+	handle := func(w http.ResponseWriter, body any) {
+		node.requests.Add(1)
+		if !node.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(body)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/eth/v1/node/health":
+			if node.healthy.Load() {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		case r.URL.Path == "/eth/v1/beacon/headers/123456":
+			handle(w, createValidHeaderResponse())
+		case strings.HasPrefix(r.URL.Path, "/eth/v2/beacon/blocks/"):
+			handle(w, createValidBlockResponse())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	node.url = server.URL
+	return node
+}
+
+func newPoolClient(nodes ...*poolNode) *Client {
+	var urls []string
+	for _, node := range nodes {
+		urls = append(urls, node.url)
+	}
+	client := NewClient("")
+	client.Retry = RetryPolicy{}
+	client.Pool = NewPool(urls...)
+	return client
+}
+
+func TestPoolFailover(t *testing.T) {
+	sick, healthy := newPoolNode(t, false), newPoolNode(t, true)
+	client := newPoolClient(sick, healthy)
+
+	if _, err := client.FetchBlockHeader(context.Background(), "123456"); err != nil {
+		t.Fatalf("FetchBlockHeader() error = %v", err)
+	}
+	stats := client.Pool.Stats()
+	if stats[0].ErrorRate == 0 || stats[1].ErrorRate != 0 {
+		t.Errorf("Stats() error rates = %v, %v", stats[0].ErrorRate, stats[1].ErrorRate)
+	}
+
+	// Having failed, the sick node is passed over while the other works
+	before := sick.requests.Load()
+	for range 3 {
+		if _, err := client.FetchBlockHeader(context.Background(), "123456"); err != nil {
+			t.Fatalf("FetchBlockHeader() error = %v", err)
+		}
+	}
+	if got := sick.requests.Load(); got != before {
+		t.Errorf("sick node served %d more requests", got-before)
+	}
+}
+
+func TestPoolAllDown(t *testing.T) {
+	client := newPoolClient(newPoolNode(t, false), newPoolNode(t, false))
+	if _, err := client.FetchBlockHeader(context.Background(), "123456"); err == nil {
+		t.Fatal("FetchBlockHeader() expected error with every endpoint failing, got nil")
+	}
+}
+
+func TestPoolProbe(t *testing.T) {
+	node, other := newPoolNode(t, false), newPoolNode(t, false)
+	client := newPoolClient(node, other)
+	for range maxConsecutiveFailures {
+		client.FetchBlockHeader(context.Background(), "123456")
+	}
+	if stats := client.Pool.Stats(); !stats[0].Down || !stats[1].Down {
+		t.Fatal("failing endpoints not taken out of rotation")
+	}
+
+	node.healthy.Store(true)
+	client.Pool.Probe(context.Background())
+	if stats := client.Pool.Stats(); stats[0].Down || !stats[1].Down {
+		t.Errorf("Stats() down after Probe() = %v, %v, want false, true", stats[0].Down, stats[1].Down)
+	}
+	if _, err := client.FetchBlockHeader(context.Background(), "123456"); err != nil {
+		t.Errorf("FetchBlockHeader() after recovery error = %v", err)
+	}
+
+	other.healthy.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Pool.Run(ctx, 5*time.Millisecond)
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Pool.Stats()[1].Down {
+		if time.Now().After(deadline) {
+			t.Fatal("Run() did not restore a recovered endpoint")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPoolPick(t *testing.T) {
+	pool := NewPool("a", "b", "c")
+	a, b, c := pool.endpoints[0], pool.endpoints[1], pool.endpoints[2]
+	pool.observe(a, 30*time.Millisecond, false)
+	pool.observe(b, 10*time.Millisecond, false)
+	pool.observe(c, 20*time.Millisecond, false)
+
+	if got := pool.pick(nil); got != b {
+		t.Errorf("pick() = %s, want the fastest endpoint b", got.url)
+	}
+	if got := pool.pick([]*endpoint{b}); got != c {
+		t.Errorf("pick() excluding b = %s, want c", got.url)
+	}
+
+	// Errors outweigh a small latency advantage
+	pool.observe(b, 10*time.Millisecond, true)
+	if got := pool.pick(nil); got != c {
+		t.Errorf("pick() after b failed = %s, want c", got.url)
+	}
+
+	// With every endpoint tried, the best is reused
+	if got := pool.pick([]*endpoint{a, b, c}); got != c {
+		t.Errorf("pick() with all tried = %s, want c", got.url)
+	}
+}
+
+func TestPoolPickUnmeasured(t *testing.T) {
+	pool := NewPool("a", "b")
+	a, b := pool.endpoints[0], pool.endpoints[1]
+	if got := pool.pick(nil); got != a {
+		t.Errorf("pick() = %s, want the first endpoint", got.url)
+	}
+
+	// An endpoint that has only failed ranks below a slow working one
+	pool.observe(a, time.Millisecond, true)
+	pool.observe(b, 200*time.Millisecond, false)
+	if got := pool.pick(nil); got != b {
+		t.Errorf("pick() = %s, want b", got.url)
+	}
+}
@@ -0,0 +1,183 @@
+package beacon
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Quorum fetches headers from several independent beacon nodes and accepts
+// one only when at least Threshold of them return the same header, so that
+// a single lying or faulty node cannot feed the verifier a forged block.
+type Quorum struct {
+	Clients   []*Client
+	Threshold int
+}
+
+// FieldDiff is a header field on which an endpoint disagreed with the
+// header most endpoints returned
+type FieldDiff struct {
+	Field    string
+	Majority string
+	Got      string
+}
+
+// Disagreement is the answer of one endpoint outside the agreeing group:
+// either an error or a header differing in Fields
+type Disagreement struct {
+	Endpoint string
+	Err      error
+	Fields   []FieldDiff
+}
+
+func (d Disagreement) String() string {
+	if d.Err != nil {
+		return fmt.Sprintf("%s: %v", d.Endpoint, d.Err)
+	}
+	parts := make([]string, len(d.Fields))
+	for i, diff := range d.Fields {
+		parts[i] = fmt.Sprintf("%s %s (majority %s)", diff.Field, diff.Got, diff.Majority)
+	}
+	return fmt.Sprintf("%s: %s", d.Endpoint, strings.Join(parts, ", "))
+}
+
+// QuorumError reports that too few endpoints agreed on a header
+type QuorumError struct {
+	Slot          string
+	Threshold     int
+	Endpoints     int
+	Agreeing      int
+	Disagreements []Disagreement
+}
+
+func (e *QuorumError) Error() string {
+	parts := make([]string, len(e.Disagreements))
+	for i, d := range e.Disagreements {
+		parts[i] = d.String()
+	}
+	return fmt.Sprintf("only %d of %d endpoints agree on the header at %s, %d required; %s",
+		e.Agreeing, e.Endpoints, e.Slot, e.Threshold, strings.Join(parts, "; "))
+}
+
+// quorumAnswer is one endpoint's answer and the key it votes with
+type quorumAnswer struct {
+	endpoint string
+	header   HeaderData
+	err      error
+	key      string
+}
+
+// emptyVote is the key of endpoints reporting an empty slot, which counts
+// as an answer like any header
+const emptyVote = "empty"
+
+// FetchBlockHeader fetches the header at slot from every endpoint and
+// returns the one at least Threshold endpoints agree on, together with the
+// answers of the endpoints that did not agree. Headers agree when their
+// locally computed roots and execution timestamps match. If enough
+// endpoints report the slot empty, the error matches ErrSlotEmpty; without
+// a quorum it is a *QuorumError.
+func (q *Quorum) FetchBlockHeader(ctx context.Context, slot string) (HeaderData, []Disagreement, error) {
+	answers := make([]quorumAnswer, len(q.Clients))
+	var wg sync.WaitGroup
+	for i, client := range q.Clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			header, err := client.FetchBlockHeader(ctx, slot)
+			answers[i] = quorumAnswer{endpoint: client.BaseURL, header: header, err: err}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return HeaderData{}, nil, err
+	}
+
+	// Group the answers by what they vote for; the largest group, earliest
+	// endpoint first on ties, is the majority
+	votes := make(map[string]int)
+	majority := -1
+	for i := range answers {
+		answer := &answers[i]
+		switch {
+		case errors.Is(answer.err, ErrSlotEmpty):
+			answer.key = emptyVote
+		case answer.err != nil:
+			continue
+		default:
+			root, err := answer.header.HashTreeRoot()
+			if err != nil {
+				answer.err = &DecodeError{URL: answer.endpoint, Err: err}
+				continue
+			}
+			answer.key = hex.EncodeToString(root[:]) + "/" + strconv.FormatInt(answer.header.Timestamp, 10)
+		}
+		votes[answer.key]++
+		if majority < 0 || votes[answer.key] > votes[answers[majority].key] {
+			majority = i
+		}
+	}
+
+	var disagreements []Disagreement
+	agreeing := 0
+	for _, answer := range answers {
+		switch {
+		case majority >= 0 && answer.key == answers[majority].key:
+			agreeing++
+		case answer.err != nil:
+			disagreements = append(disagreements, Disagreement{Endpoint: answer.endpoint, Err: answer.err})
+		case answers[majority].key == emptyVote:
+			disagreements = append(disagreements, Disagreement{
+				Endpoint: answer.endpoint,
+				Err:      fmt.Errorf("returned block %s for a slot the majority report empty", answer.header.BlockRoot),
+			})
+		default:
+			disagreements = append(disagreements, Disagreement{
+				Endpoint: answer.endpoint,
+				Fields:   diffHeaders(answers[majority].header, answer.header),
+			})
+		}
+	}
+
+	if agreeing < q.Threshold {
+		return HeaderData{}, disagreements, &QuorumError{
+			Slot:          slot,
+			Threshold:     q.Threshold,
+			Endpoints:     len(q.Clients),
+			Agreeing:      agreeing,
+			Disagreements: disagreements,
+		}
+	}
+	if answers[majority].key == emptyVote {
+		return HeaderData{}, disagreements, fmt.Errorf("%d of %d endpoints report slot %s: %w",
+			agreeing, len(q.Clients), slot, ErrSlotEmpty)
+	}
+	return answers[majority].header, disagreements, nil
+}
+
+// diffHeaders lists the fields in which got differs from want
+func diffHeaders(want, got HeaderData) []FieldDiff {
+	fields := []struct {
+		name      string
+		want, got string
+	}{
+		{"slot", want.Slot, got.Slot},
+		{"proposer_index", want.ProposerIndex, got.ProposerIndex},
+		{"parent_root", want.ParentRoot, got.ParentRoot},
+		{"state_root", want.StateRoot, got.StateRoot},
+		{"body_root", want.BodyRoot, got.BodyRoot},
+		{"block_root", want.BlockRoot, got.BlockRoot},
+		{"timestamp", strconv.FormatInt(want.Timestamp, 10), strconv.FormatInt(got.Timestamp, 10)},
+	}
+	var diffs []FieldDiff
+	for _, field := range fields {
+		if !strings.EqualFold(field.want, field.got) {
+			diffs = append(diffs, FieldDiff{Field: field.name, Majority: field.want, Got: field.got})
+		}
+	}
+	return diffs
+}
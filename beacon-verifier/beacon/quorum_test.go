@@ -0,0 +1,103 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// quorumNode serves the test header at slot 123456, edited by tamper, or
+// answers the header request with status when it is not 200
+func quorumNode(t *testing.T, status int, tamper func(*APIResponse)) *Client {
+	header := createValidHeaderResponse()
+	if tamper != nil {
+		tamper(&header)
+	}
+	server := setupTestServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			json.NewEncoder(w).Encode(header)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			// A lying node serves a block consistent with its own header
+			json.NewEncoder(w).Encode(blockResponseFor(header, "1651234567"))
+		},
+	)
+	client := NewClient(server.URL)
+	client.Retry = RetryPolicy{}
+	return client
+}
+
+func TestQuorumFetchBlockHeader(t *testing.T) {
+	forgedState := func(resp *APIResponse) {
+		resp.Data.Header.Message.StateRoot = "0x" + strings.Repeat("ee", 32)
+	}
+	forgedProposer := func(resp *APIResponse) {
+		resp.Data.Header.Message.ProposerIndex = "43"
+	}
+
+	t.Run("Agreement", func(t *testing.T) {
+		honest1, honest2 := quorumNode(t, http.StatusOK, nil), quorumNode(t, http.StatusOK, nil)
+		liar := quorumNode(t, http.StatusOK, forgedState)
+		quorum := &Quorum{Clients: []*Client{liar, honest1, honest2}, Threshold: 2}
+
+		header, disagreements, err := quorum.FetchBlockHeader(context.Background(), "123456")
+		if err != nil {
+			t.Fatalf("FetchBlockHeader() error = %v", err)
+		}
+		if header.StateRoot != createValidHeaderResponse().Data.Header.Message.StateRoot {
+			t.Errorf("FetchBlockHeader() returned the minority header, state_root %s", header.StateRoot)
+		}
+		if len(disagreements) != 1 || disagreements[0].Endpoint != liar.BaseURL {
+			t.Fatalf("disagreements = %v, want only %s", disagreements, liar.BaseURL)
+		}
+		fields := disagreements[0].Fields
+		if len(fields) != 1 || fields[0].Field != "state_root" || fields[0].Got != "0x"+strings.Repeat("ee", 32) {
+			t.Errorf("disagreement fields = %+v, want state_root only", fields)
+		}
+	})
+
+	t.Run("NoQuorum", func(t *testing.T) {
+		honest := quorumNode(t, http.StatusOK, nil)
+		liar := quorumNode(t, http.StatusOK, forgedProposer)
+		down := quorumNode(t, http.StatusInternalServerError, nil)
+		quorum := &Quorum{Clients: []*Client{honest, liar, down}, Threshold: 2}
+
+		_, _, err := quorum.FetchBlockHeader(context.Background(), "123456")
+		var quorumErr *QuorumError
+		if !errors.As(err, &quorumErr) {
+			t.Fatalf("FetchBlockHeader() error = %v, want *QuorumError", err)
+		}
+		if quorumErr.Agreeing != 1 || len(quorumErr.Disagreements) != 2 {
+			t.Errorf("QuorumError agreeing = %d with %d disagreements, want 1 and 2",
+				quorumErr.Agreeing, len(quorumErr.Disagreements))
+		}
+		for _, want := range []string{liar.BaseURL, "proposer_index 43 (majority 42)", down.BaseURL} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not mention %q", err, want)
+			}
+		}
+	})
+
+	t.Run("EmptySlot", func(t *testing.T) {
+		quorum := &Quorum{Clients: []*Client{
+			quorumNode(t, http.StatusNotFound, nil),
+			quorumNode(t, http.StatusOK, nil),
+			quorumNode(t, http.StatusNotFound, nil),
+		}, Threshold: 2}
+
+		_, disagreements, err := quorum.FetchBlockHeader(context.Background(), "123456")
+		if !errors.Is(err, ErrSlotEmpty) {
+			t.Errorf("FetchBlockHeader() error = %v, want ErrSlotEmpty", err)
+		}
+		if len(disagreements) != 1 || disagreements[0].Err == nil {
+			t.Errorf("disagreements = %v, want the endpoint that returned a block", disagreements)
+		}
+	})
+}
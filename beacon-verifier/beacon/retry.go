@@ -0,0 +1,78 @@
+package beacon
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries a request that failed for a
+// reason that may pass: no response at all, or one of RetryableStatus. Each
+// attempt gets the client's full Timeout.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; zero or one disables retries
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubling for each
+	// retry after it up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter is the fraction of each delay that is randomized, so clients
+	// that failed together do not retry together
+	Jitter float64
+	// RetryableStatus lists the status codes worth retrying. A Retry-After
+	// header on such a response replaces the computed delay; if it asks for
+	// more than MaxDelay the response is returned instead.
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy retries rate limiting, gateway errors and syncing nodes
+// three times over a few seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// retryable reports whether a response status is worth retrying
+func (p RetryPolicy) retryable(status int) bool {
+	return slices.Contains(p.RetryableStatus, status)
+}
+
+// backoff returns the delay before retry n, counting from 1
+func (p RetryPolicy) backoff(n int) time.Duration {
+	delay := p.MaxDelay
+	if shift := n - 1; shift < 32 && p.BaseDelay<<shift < p.MaxDelay {
+		delay = p.BaseDelay << shift
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an
+// HTTP date. ok is false when the header is missing or malformed.
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
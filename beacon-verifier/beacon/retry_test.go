@@ -0,0 +1,168 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy retries quickly so tests do not wait on real backoff
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	return policy
+}
+
+// failingHeaderServer answers the header endpoint with failures before it
+// succeeds, counting every request
+func failingHeaderServer(t *testing.T, failures int, fail func(w http.ResponseWriter)) (*Client, *atomic.Int32) {
+	var requests atomic.Int32
+	server := setupTestServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			if int(requests.Add(1)) <= failures {
+				fail(w)
+				return
+			}
+			json.NewEncoder(w).Encode(createValidHeaderResponse())
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(createValidBlockResponse())
+		},
+	)
+	client := NewClient(server.URL)
+	client.Retry = fastRetryPolicy()
+	return client, &requests
+}
+
+func TestClientRetry(t *testing.T) {
+	unavailable := func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }
+
+	t.Run("Recovers", func(t *testing.T) {
+		client, requests := failingHeaderServer(t, 2, unavailable)
+		if _, err := client.FetchBlockHeader(context.Background(), "123456"); err != nil {
+			t.Fatalf("FetchBlockHeader() error = %v", err)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("header requests = %d, want 3", got)
+		}
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		client, requests := failingHeaderServer(t, 10, unavailable)
+		_, err := client.FetchBlockHeader(context.Background(), "123456")
+		if !errors.Is(err, ErrNodeSyncing) {
+			t.Errorf("FetchBlockHeader() error = %v, want ErrNodeSyncing", err)
+		}
+		if got := requests.Load(); got != int32(client.Retry.MaxAttempts) {
+			t.Errorf("header requests = %d, want %d", got, client.Retry.MaxAttempts)
+		}
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		client, requests := failingHeaderServer(t, 10, func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) })
+		if _, err := client.FetchBlockHeader(context.Background(), "123456"); !errors.Is(err, ErrSlotEmpty) {
+			t.Errorf("FetchBlockHeader() error = %v, want ErrSlotEmpty", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("header requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		client, requests := failingHeaderServer(t, 1, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		client.Retry.BaseDelay = time.Minute
+		client.Retry.MaxDelay = time.Minute
+		if _, err := client.FetchBlockHeader(context.Background(), "123456"); err != nil {
+			t.Fatalf("FetchBlockHeader() error = %v", err)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("header requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("RetryAfterTooLong", func(t *testing.T) {
+		client, requests := failingHeaderServer(t, 10, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		var apiErr *APIError
+		if _, err := client.FetchBlockHeader(context.Background(), "123456"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("FetchBlockHeader() error = %v, want a 429 APIError", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("header requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		client, _ := failingHeaderServer(t, 10, unavailable)
+		client.Retry.BaseDelay = time.Minute
+		client.Retry.MaxDelay = time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := client.FetchBlockHeader(ctx, "123456"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("FetchBlockHeader() error = %v, want context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("FetchBlockHeader() took %v after its context expired", elapsed)
+		}
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+	tests := []struct {
+		retry int
+		full  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{64, time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			delay := policy.backoff(tt.retry)
+			if delay > tt.full || delay < tt.full/2 {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.retry, delay, tt.full/2, tt.full)
+			}
+		}
+	}
+
+	policy.Jitter = 0
+	if got := policy.backoff(3); got != 400*time.Millisecond {
+		t.Errorf("backoff(3) without jitter = %v, want 400ms", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
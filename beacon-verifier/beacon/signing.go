@@ -0,0 +1,83 @@
+package beacon
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// DomainType separates signatures over different kinds of messages
+type DomainType [4]byte
+
+// Signature domains from the consensus specs
+var (
+	DomainBeaconProposer = DomainType{0x00, 0x00, 0x00, 0x00}
+	DomainSyncCommittee  = DomainType{0x07, 0x00, 0x00, 0x00}
+)
+
+// Containers signatures commit to
+var (
+	ForkDataType = ssz.Container("ForkData",
+		ssz.F("current_version", Version),
+		ssz.F("genesis_validators_root", Root),
+	)
+
+	SigningDataType = ssz.Container("SigningData",
+		ssz.F("object_root", Root),
+		ssz.F("domain", ssz.Bytes(32)),
+	)
+)
+
+// ComputeDomain combines a domain type with the fork data root of a fork
+// version, binding signatures to one chain and fork
+func ComputeDomain(domainType DomainType, forkVersion [4]byte, genesisValidatorsRoot [32]byte) ([32]byte, error) {
+	forkDataRoot, err := ssz.HashTreeRoot(ForkDataType, map[string]any{
+		"current_version":         "0x" + hex.EncodeToString(forkVersion[:]),
+		"genesis_validators_root": "0x" + hex.EncodeToString(genesisValidatorsRoot[:]),
+	})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("error hashing fork data: %w", err)
+	}
+
+	var domain [32]byte
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain, nil
+}
+
+// ComputeSigningRoot returns the root a signature over an object commits to
+func ComputeSigningRoot(objectRoot, domain [32]byte) ([32]byte, error) {
+	root, err := ssz.HashTreeRoot(SigningDataType, map[string]any{
+		"object_root": "0x" + hex.EncodeToString(objectRoot[:]),
+		"domain":      "0x" + hex.EncodeToString(domain[:]),
+	})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("error hashing signing data: %w", err)
+	}
+	return root, nil
+}
+
+// DomainAtEpoch returns the domain of a message type under the fork active
+// at epoch
+func (s ForkSchedule) DomainAtEpoch(domainType DomainType, epoch uint64) ([32]byte, error) {
+	if s.GenesisValidatorsRoot == ([32]byte{}) {
+		return [32]byte{}, fmt.Errorf("fork schedule has no genesis validators root")
+	}
+	fork, err := s.ForkAtEpoch(epoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return ComputeDomain(domainType, fork.Version, s.GenesisValidatorsRoot)
+}
+
+// WithGenesisValidatorsRoot returns the schedule bound to a chain's genesis
+// validators root, which signing domains commit to
+func (s ForkSchedule) WithGenesisValidatorsRoot(root string) ForkSchedule {
+	b, err := hex.DecodeString(trimHexPrefix(root))
+	if err != nil || len(b) != 32 {
+		panic(fmt.Sprintf("invalid genesis validators root %q", root))
+	}
+	copy(s.GenesisValidatorsRoot[:], b)
+	return s
+}
@@ -0,0 +1,73 @@
+package beacon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestComputeDomain(t *testing.T) {
+	version := [4]byte{0x05, 0x00, 0x00, 0x00}
+	var gvr [32]byte
+	for i := range gvr {
+		gvr[i] = byte(i)
+	}
+
+	domain, err := ComputeDomain(DomainSyncCommittee, version, gvr)
+	if err != nil {
+		t.Fatalf("ComputeDomain() error = %v", err)
+	}
+
+	// ForkData has two chunks: the padded version and the root
+	var chunk [32]byte
+	copy(chunk[:], version[:])
+	forkDataRoot := sha256.Sum256(append(chunk[:], gvr[:]...))
+	want := append([]byte{0x07, 0x00, 0x00, 0x00}, forkDataRoot[:28]...)
+	if !bytes.Equal(domain[:], want) {
+		t.Errorf("ComputeDomain() = %x, want %x", domain, want)
+	}
+
+	objectRoot := sha256.Sum256([]byte("object"))
+	signingRoot, err := ComputeSigningRoot(objectRoot, domain)
+	if err != nil {
+		t.Fatalf("ComputeSigningRoot() error = %v", err)
+	}
+	if want := sha256.Sum256(append(objectRoot[:], domain[:]...)); signingRoot != want {
+		t.Errorf("ComputeSigningRoot() = %x, want %x", signingRoot, want)
+	}
+}
+
+func TestDomainAtEpoch(t *testing.T) {
+	schedule, err := ForkScheduleForChain(1)
+	if err != nil {
+		t.Fatalf("ForkScheduleForChain() error = %v", err)
+	}
+
+	// Domains change exactly at the fork boundary
+	deneb, err := schedule.DomainAtEpoch(DomainBeaconProposer, 364031)
+	if err != nil {
+		t.Fatalf("DomainAtEpoch() error = %v", err)
+	}
+	electra, err := schedule.DomainAtEpoch(DomainBeaconProposer, 364032)
+	if err != nil {
+		t.Fatalf("DomainAtEpoch() error = %v", err)
+	}
+	want, err := ComputeDomain(DomainBeaconProposer, [4]byte{0x05, 0x00, 0x00, 0x00}, schedule.GenesisValidatorsRoot)
+	if err != nil {
+		t.Fatalf("ComputeDomain() error = %v", err)
+	}
+	if electra != want {
+		t.Errorf("DomainAtEpoch(364032) = %x, want %x", electra, want)
+	}
+	if deneb == electra {
+		t.Error("DomainAtEpoch() returned the same domain on both sides of the Electra fork")
+	}
+
+	if _, err := schedule.DomainAtEpoch(DomainBeaconProposer, 100); err == nil {
+		t.Error("DomainAtEpoch() before Capella expected error, got nil")
+	}
+	unbound := NewForkSchedule(SlotsPerEpoch, schedule.Forks...)
+	if _, err := unbound.DomainAtEpoch(DomainBeaconProposer, 364032); err == nil {
+		t.Error("DomainAtEpoch() without genesis validators root expected error, got nil")
+	}
+}
@@ -0,0 +1,125 @@
+package beacon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// ProposerSlashing is evidence that a proposer signed two different headers
+// for the same slot. Both headers carry their signatures.
+type ProposerSlashing struct {
+	Header1 HeaderData
+	Header2 HeaderData
+}
+
+// ParseProposerSlashing reads a slashing in the API shape used both in block
+// bodies and in /eth/v1/beacon/pool/proposer_slashings
+func ParseProposerSlashing(v any) (ProposerSlashing, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return ProposerSlashing{}, fmt.Errorf("proposer slashing is %T, expected an object", v)
+	}
+	header1, err := parseSignedHeader(m["signed_header_1"])
+	if err != nil {
+		return ProposerSlashing{}, fmt.Errorf("signed_header_1: %w", err)
+	}
+	header2, err := parseSignedHeader(m["signed_header_2"])
+	if err != nil {
+		return ProposerSlashing{}, fmt.Errorf("signed_header_2: %w", err)
+	}
+	return ProposerSlashing{Header1: header1, Header2: header2}, nil
+}
+
+// parseSignedHeader reads a SignedBeaconBlockHeader into header data
+func parseSignedHeader(v any) (HeaderData, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return HeaderData{}, errors.New("missing signed header")
+	}
+	message, ok := m["message"].(map[string]any)
+	if !ok {
+		return HeaderData{}, errors.New("signed header has no message")
+	}
+	field := func(name string) string {
+		return fmt.Sprint(message[name])
+	}
+	signature, _ := m["signature"].(string)
+	headerData := HeaderData{
+		Slot:          field("slot"),
+		ProposerIndex: field("proposer_index"),
+		ParentRoot:    field("parent_root"),
+		StateRoot:     field("state_root"),
+		BodyRoot:      field("body_root"),
+		Signature:     signature,
+	}
+	var header BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		return HeaderData{}, err
+	}
+	return headerData, nil
+}
+
+// Check applies the conditions of process_proposer_slashing that do not need
+// the beacon state: same slot, same proposer and different headers. The
+// signatures are checked separately against the proposer's public key.
+func (s ProposerSlashing) Check() error {
+	var header1, header2 BlockHeader
+	if err := header1.FromAPIResponse(s.Header1); err != nil {
+		return fmt.Errorf("signed_header_1: %w", err)
+	}
+	if err := header2.FromAPIResponse(s.Header2); err != nil {
+		return fmt.Errorf("signed_header_2: %w", err)
+	}
+	if header1.Slot != header2.Slot {
+		return fmt.Errorf("headers are for slots %d and %d", header1.Slot, header2.Slot)
+	}
+	if header1.ProposerIndex != header2.ProposerIndex {
+		return fmt.Errorf("headers are by proposers %d and %d", header1.ProposerIndex, header2.ProposerIndex)
+	}
+
+	root1, err := header1.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	root2, err := header2.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if root1 == root2 {
+		return fmt.Errorf("both headers have root 0x%x", root1)
+	}
+	return nil
+}
+
+// Value returns the slashing in the generic JSON shape used by the ssz
+// package
+func (s ProposerSlashing) Value() (map[string]any, error) {
+	signed := func(d HeaderData) (map[string]any, error) {
+		var header BlockHeader
+		if err := header.FromAPIResponse(d); err != nil {
+			return nil, err
+		}
+		return map[string]any{"message": header.Value(), "signature": strings.ToLower(d.Signature)}, nil
+	}
+	header1, err := signed(s.Header1)
+	if err != nil {
+		return nil, fmt.Errorf("signed_header_1: %w", err)
+	}
+	header2, err := signed(s.Header2)
+	if err != nil {
+		return nil, fmt.Errorf("signed_header_2: %w", err)
+	}
+	return map[string]any{"signed_header_1": header1, "signed_header_2": header2}, nil
+}
+
+// HashTreeRoot computes the root the slashing has as a block body element
+func (s ProposerSlashing) HashTreeRoot() ([32]byte, error) {
+	v, err := s.Value()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return ssz.HashTreeRoot(ProposerSlashingType, v)
+}
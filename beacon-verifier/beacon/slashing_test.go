@@ -0,0 +1,77 @@
+package beacon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+func testSignedHeader(slot, proposer, bodyByte string) map[string]any {
+	return map[string]any{
+		"message": map[string]any{
+			"slot":           slot,
+			"proposer_index": proposer,
+			"parent_root":    "0x" + strings.Repeat("11", 32),
+			"state_root":     "0x" + strings.Repeat("22", 32),
+			"body_root":      "0x" + strings.Repeat(bodyByte, 32),
+		},
+		"signature": "0x" + strings.Repeat("ab", 96),
+	}
+}
+
+func TestProposerSlashing(t *testing.T) {
+	raw := map[string]any{
+		"signed_header_1": testSignedHeader("100", "7", "33"),
+		"signed_header_2": testSignedHeader("100", "7", "44"),
+	}
+	slashing, err := ParseProposerSlashing(raw)
+	if err != nil {
+		t.Fatalf("ParseProposerSlashing() error = %v", err)
+	}
+	if err := slashing.Check(); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	if slashing.Header2.Signature != "0x"+strings.Repeat("ab", 96) {
+		t.Errorf("Header2.Signature = %s, want the signed header's signature", slashing.Header2.Signature)
+	}
+
+	root, err := slashing.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	want, err := ssz.HashTreeRoot(ProposerSlashingType, raw)
+	if err != nil {
+		t.Fatalf("ssz.HashTreeRoot() error = %v", err)
+	}
+	if root != want {
+		t.Errorf("HashTreeRoot() = %x, want %x", root, want)
+	}
+
+	tests := []struct {
+		name    string
+		header2 map[string]any
+	}{
+		{"Different slot", testSignedHeader("101", "7", "44")},
+		{"Different proposer", testSignedHeader("100", "8", "44")},
+		{"Same header", testSignedHeader("100", "7", "33")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slashing, err := ParseProposerSlashing(map[string]any{
+				"signed_header_1": raw["signed_header_1"],
+				"signed_header_2": tt.header2,
+			})
+			if err != nil {
+				t.Fatalf("ParseProposerSlashing() error = %v", err)
+			}
+			if err := slashing.Check(); err == nil {
+				t.Error("Check() expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := ParseProposerSlashing(map[string]any{"signed_header_1": raw["signed_header_1"]}); err == nil {
+		t.Error("ParseProposerSlashing() without signed_header_2 expected error, got nil")
+	}
+}
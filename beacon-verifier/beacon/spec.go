@@ -0,0 +1,186 @@
+package beacon
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChainSpec is the configuration of the chain a beacon node follows, as
+// reported by its genesis, spec and fork schedule endpoints
+type ChainSpec struct {
+	GenesisTime                  uint64
+	GenesisValidatorsRoot        [32]byte
+	GenesisForkVersion           [4]byte
+	SecondsPerSlot               uint64
+	SlotsPerEpoch                uint64
+	EpochsPerSyncCommitteePeriod uint64
+	// ConfigName is the network name, such as "mainnet" or "hoodi"
+	ConfigName string
+	// Forks lists every scheduled fork, named after the spec's
+	// <FORK>_FORK_VERSION entries, with the genesis validators root set
+	Forks ForkSchedule
+	// Values holds every string entry of /eth/v1/config/spec by key
+	Values map[string]string
+}
+
+// ChainSpec fetches the chain's genesis, spec and fork schedule on first
+// use and returns the cached result afterwards. A failed fetch is not
+// cached.
+func (c *Client) ChainSpec(ctx context.Context) (*ChainSpec, error) {
+	c.specMu.Lock()
+	defer c.specMu.Unlock()
+	if c.spec != nil {
+		return c.spec, nil
+	}
+	spec, err := c.fetchChainSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.spec = spec
+	return spec, nil
+}
+
+// fetchChainSpec fetches and combines the three configuration endpoints
+func (c *Client) fetchChainSpec(ctx context.Context) (*ChainSpec, error) {
+	var genesis struct {
+		Data struct {
+			GenesisTime           string `json:"genesis_time"`
+			GenesisValidatorsRoot string `json:"genesis_validators_root"`
+			GenesisForkVersion    string `json:"genesis_fork_version"`
+		} `json:"data"`
+	}
+	if err := c.fetchJSON(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+		return nil, fmt.Errorf("error fetching genesis: %w", err)
+	}
+	var config struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := c.fetchJSON(ctx, "/eth/v1/config/spec", &config); err != nil {
+		return nil, fmt.Errorf("error fetching spec: %w", err)
+	}
+	var schedule struct {
+		Data []struct {
+			PreviousVersion string `json:"previous_version"`
+			CurrentVersion  string `json:"current_version"`
+			Epoch           string `json:"epoch"`
+		} `json:"data"`
+	}
+	if err := c.fetchJSON(ctx, "/eth/v1/config/fork_schedule", &schedule); err != nil {
+		return nil, fmt.Errorf("error fetching fork schedule: %w", err)
+	}
+
+	spec := &ChainSpec{Values: make(map[string]string, len(config.Data))}
+	for key, value := range config.Data {
+		if s, ok := value.(string); ok {
+			spec.Values[key] = s
+		}
+	}
+	spec.ConfigName = spec.Values["CONFIG_NAME"]
+
+	var err error
+	if spec.GenesisTime, err = strconv.ParseUint(genesis.Data.GenesisTime, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid genesis time: %w", err)
+	}
+	if err := decodeFixedHex(genesis.Data.GenesisValidatorsRoot, spec.GenesisValidatorsRoot[:]); err != nil {
+		return nil, fmt.Errorf("invalid genesis validators root: %w", err)
+	}
+	if err := decodeFixedHex(genesis.Data.GenesisForkVersion, spec.GenesisForkVersion[:]); err != nil {
+		return nil, fmt.Errorf("invalid genesis fork version: %w", err)
+	}
+	for key, field := range map[string]*uint64{
+		"SECONDS_PER_SLOT":                 &spec.SecondsPerSlot,
+		"SLOTS_PER_EPOCH":                  &spec.SlotsPerEpoch,
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": &spec.EpochsPerSyncCommitteePeriod,
+	} {
+		if *field, err = spec.Uint(key); err != nil {
+			return nil, err
+		}
+		if *field == 0 {
+			return nil, fmt.Errorf("spec value %s is zero", key)
+		}
+	}
+
+	// The fork schedule only carries versions; the spec names them
+	names := make(map[[4]byte]string)
+	for key, value := range spec.Values {
+		name, ok := strings.CutSuffix(key, "_FORK_VERSION")
+		var version [4]byte
+		if ok && decodeFixedHex(value, version[:]) == nil {
+			names[version] = strings.ToLower(name)
+		}
+	}
+	var forks []ForkEpoch
+	for _, entry := range schedule.Data {
+		var fork ForkEpoch
+		if err := decodeFixedHex(entry.CurrentVersion, fork.Version[:]); err != nil {
+			return nil, fmt.Errorf("invalid fork version in schedule: %w", err)
+		}
+		if fork.Epoch, err = strconv.ParseUint(entry.Epoch, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid fork epoch in schedule: %w", err)
+		}
+		fork.Name = names[fork.Version]
+		if fork.Version == spec.GenesisForkVersion {
+			fork.Name = "phase0"
+		}
+		if fork.Name == "" {
+			fork.Name = "0x" + hex.EncodeToString(fork.Version[:])
+		}
+		forks = append(forks, fork)
+	}
+	if len(forks) == 0 {
+		return nil, errors.New("empty fork schedule")
+	}
+	spec.Forks = NewForkSchedule(spec.SlotsPerEpoch, forks...)
+	spec.Forks.GenesisValidatorsRoot = spec.GenesisValidatorsRoot
+	return spec, nil
+}
+
+// Uint returns a numeric spec value
+func (s *ChainSpec) Uint(key string) (uint64, error) {
+	value, ok := s.Values[key]
+	if !ok {
+		return 0, fmt.Errorf("spec has no %s", key)
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid spec value %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// CheckPreset reports an error when the chain's preset differs from the
+// mainnet preset the container layouts are compiled for
+func (s *ChainSpec) CheckPreset() error {
+	for key, want := range map[string]uint64{
+		"SLOTS_PER_EPOCH":                  SlotsPerEpoch,
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": EpochsPerSyncCommitteePeriod,
+		"SLOTS_PER_HISTORICAL_ROOT":        SlotsPerHistoricalRoot,
+		"SYNC_COMMITTEE_SIZE":              SyncCommitteeSize,
+	} {
+		got, err := s.Uint(key)
+		if err != nil {
+			continue
+		}
+		if got != want {
+			return fmt.Errorf("chain uses %s = %d, but only the mainnet preset (%d) is supported", key, got, want)
+		}
+	}
+	return nil
+}
+
+// decodeFixedHex decodes a 0x-prefixed hex string of exactly len(out) bytes
+func decodeFixedHex(s string, out []byte) error {
+	b, err := hex.DecodeString(trimHexPrefix(s))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(out) {
+		return fmt.Errorf("%q has %d bytes, expected %d", s, len(b), len(out))
+	}
+	copy(out, b)
+	return nil
+}
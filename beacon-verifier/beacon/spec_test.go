@@ -0,0 +1,146 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// specServer serves the configuration endpoints of a mainnet-like chain;
+// edit may change the spec values before they are served
+func specServer(t *testing.T, edit func(map[string]any)) (*httptest.Server, *atomic.Int32) {
+	spec := map[string]any{
+		"CONFIG_NAME":                      "mainnet",
+		"PRESET_BASE":                      "mainnet",
+		"SECONDS_PER_SLOT":                 "12",
+		"SLOTS_PER_EPOCH":                  "32",
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": "256",
+		"SLOTS_PER_HISTORICAL_ROOT":        "8192",
+		"SYNC_COMMITTEE_SIZE":              "512",
+		"GENESIS_FORK_VERSION":             "0x00000000",
+		"ALTAIR_FORK_VERSION":              "0x01000000",
+		"CAPELLA_FORK_VERSION":             "0x03000000",
+		"DENEB_FORK_VERSION":               "0x04000000",
+		"DOMAIN_BEACON_PROPOSER":           "0x00000000",
+		"BLOB_SCHEDULE":                    []any{map[string]any{"EPOCH": "1", "MAX_BLOBS_PER_BLOCK": "9"}},
+	}
+	if edit != nil {
+		edit(spec)
+	}
+	responses := map[string]any{
+		"/eth/v1/beacon/genesis": map[string]any{
+			"genesis_time":            "1606824023",
+			"genesis_validators_root": "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95",
+			"genesis_fork_version":    "0x00000000",
+		},
+		"/eth/v1/config/spec": spec,
+		"/eth/v1/config/fork_schedule": []any{
+			map[string]any{"previous_version": "0x00000000", "current_version": "0x00000000", "epoch": "0"},
+			map[string]any{"previous_version": "0x00000000", "current_version": "0x01000000", "epoch": "74240"},
+			map[string]any{"previous_version": "0x01000000", "current_version": "0x03000000", "epoch": "194048"},
+			map[string]any{"previous_version": "0x03000000", "current_version": "0x04000000", "epoch": "269568"},
+			map[string]any{"previous_version": "0x04000000", "current_version": "0x99000000", "epoch": "999999"},
+		},
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestChainSpec(t *testing.T) {
+	server, requests := specServer(t, nil)
+	client := NewClient(server.URL)
+
+	spec, err := client.ChainSpec(context.Background())
+	if err != nil {
+		t.Fatalf("ChainSpec() error = %v", err)
+	}
+	if spec.ConfigName != "mainnet" || spec.GenesisTime != 1606824023 || spec.SecondsPerSlot != 12 ||
+		spec.SlotsPerEpoch != 32 || spec.EpochsPerSyncCommitteePeriod != 256 {
+		t.Errorf("ChainSpec() = %+v", spec)
+	}
+	mainnet, _ := ForkScheduleForChain(1)
+	if spec.GenesisValidatorsRoot != mainnet.GenesisValidatorsRoot || spec.Forks.GenesisValidatorsRoot != mainnet.GenesisValidatorsRoot {
+		t.Errorf("genesis validators root = %x, want %x", spec.GenesisValidatorsRoot, mainnet.GenesisValidatorsRoot)
+	}
+	if err := spec.CheckPreset(); err != nil {
+		t.Errorf("CheckPreset() error = %v", err)
+	}
+
+	tests := []struct {
+		epoch uint64
+		want  string
+	}{
+		{0, "phase0"},
+		{74240, "altair"},
+		{194047, "altair"},
+		{194048, Capella},
+		{300000, Deneb},
+		{999999, "0x99000000"},
+	}
+	for _, tt := range tests {
+		fork, err := spec.Forks.ForkAtEpoch(tt.epoch)
+		if err != nil || fork.Name != tt.want {
+			t.Errorf("ForkAtEpoch(%d) = %q, %v, want %q", tt.epoch, fork.Name, err, tt.want)
+		}
+	}
+	if _, err := spec.Forks.SchemaAtSlot(999999 * 32); err == nil {
+		t.Error("SchemaAtSlot() for an unknown fork expected error, got nil")
+	}
+
+	if _, ok := spec.Values["BLOB_SCHEDULE"]; ok {
+		t.Error("Values kept a non-string entry")
+	}
+
+	// Later calls are served from the cache
+	before := requests.Load()
+	if again, err := client.ChainSpec(context.Background()); err != nil || again != spec {
+		t.Errorf("second ChainSpec() = %p, %v, want the cached %p", again, err, spec)
+	}
+	if got := requests.Load(); got != before {
+		t.Errorf("second ChainSpec() made %d requests", got-before)
+	}
+}
+
+func TestChainSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(map[string]any)
+	}{
+		{"MissingSecondsPerSlot", func(spec map[string]any) { delete(spec, "SECONDS_PER_SLOT") }},
+		{"ZeroSlotsPerEpoch", func(spec map[string]any) { spec["SLOTS_PER_EPOCH"] = "0" }},
+		{"InvalidNumber", func(spec map[string]any) { spec["EPOCHS_PER_SYNC_COMMITTEE_PERIOD"] = "many" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := specServer(t, tt.edit)
+			if _, err := NewClient(server.URL).ChainSpec(context.Background()); err == nil {
+				t.Error("ChainSpec() expected error, got nil")
+			}
+		})
+	}
+
+	t.Run("MinimalPreset", func(t *testing.T) {
+		server, _ := specServer(t, func(spec map[string]any) { spec["SLOTS_PER_EPOCH"] = "8" })
+		spec, err := NewClient(server.URL).ChainSpec(context.Background())
+		if err != nil {
+			t.Fatalf("ChainSpec() error = %v", err)
+		}
+		if err := spec.CheckPreset(); err == nil {
+			t.Error("CheckPreset() expected error for the minimal preset, got nil")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+// Package bls verifies BLS12-381 signatures as used by the consensus layer:
+// public keys in G1, signatures in G2, both in compressed form, with the
+// proof-of-possession ciphersuite of the IETF BLS signature draft
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// DST is the hash-to-curve domain separation tag of the consensus layer
+const DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// Sizes of compressed public keys and signatures
+const (
+	PublicKeySize = bls12381.SizeOfG1AffineCompressed
+	SignatureSize = bls12381.SizeOfG2AffineCompressed
+)
+
+// ErrInvalidSignature is returned when a well-formed signature does not
+// verify
+var ErrInvalidSignature = errors.New("invalid BLS signature")
+
+// PublicKey is a validated public key
+type PublicKey struct {
+	point bls12381.G1Affine
+}
+
+// ParsePublicKey decompresses a public key, rejecting points outside the
+// subgroup and the point at infinity
+func ParsePublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, fmt.Errorf("public key has %d bytes, expected %d", len(b), PublicKeySize)
+	}
+	var pk PublicKey
+	if _, err := pk.point.SetBytes(b); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if pk.point.IsInfinity() {
+		return nil, errors.New("invalid public key: point at infinity")
+	}
+	return &pk, nil
+}
+
+// Bytes returns the compressed public key
+func (pk *PublicKey) Bytes() []byte {
+	b := pk.point.Bytes()
+	return b[:]
+}
+
+// AggregatePublicKeys adds public keys for fast aggregate verification
+func AggregatePublicKeys(pubkeys []*PublicKey) (*PublicKey, error) {
+	if len(pubkeys) == 0 {
+		return nil, errors.New("no public keys to aggregate")
+	}
+	var sum bls12381.G1Jac
+	sum.FromAffine(&pubkeys[0].point)
+	for _, pk := range pubkeys[1:] {
+		sum.AddMixed(&pk.point)
+	}
+	var aggregate PublicKey
+	aggregate.point.FromJacobian(&sum)
+	return &aggregate, nil
+}
+
+// SecretKey signs messages. Verification never needs one; it exists to
+// build fixtures that can be checked offline.
+type SecretKey struct {
+	scalar *big.Int
+}
+
+// NewSecretKey reads a big-endian secret scalar, which must be non-zero and
+// below the group order
+func NewSecretKey(b []byte) (*SecretKey, error) {
+	scalar := new(big.Int).SetBytes(b)
+	if scalar.Sign() == 0 || scalar.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("secret key out of range")
+	}
+	return &SecretKey{scalar: scalar}, nil
+}
+
+// PublicKey returns the public key of the secret key
+func (sk *SecretKey) PublicKey() *PublicKey {
+	var pk PublicKey
+	pk.point.ScalarMultiplicationBase(sk.scalar)
+	return &pk
+}
+
+// Sign returns the compressed signature over msg
+func (sk *SecretKey) Sign(msg []byte) ([]byte, error) {
+	hashed, err := bls12381.HashToG2(msg, []byte(DST))
+	if err != nil {
+		return nil, fmt.Errorf("hashing message to G2: %w", err)
+	}
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&hashed, sk.scalar)
+	b := sig.Bytes()
+	return b[:], nil
+}
+
+// AggregateSignatures adds signatures over the same message into one that
+// verifies against the aggregate of the signers' public keys
+func AggregateSignatures(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+	var sum bls12381.G2Jac
+	for i, b := range signatures {
+		sig, err := parseSignature(b)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+		sum.AddMixed(&sig)
+	}
+	var aggregate bls12381.G2Affine
+	aggregate.FromJacobian(&sum)
+	b := aggregate.Bytes()
+	return b[:], nil
+}
+
+// parseSignature decompresses a signature, rejecting points outside the
+// subgroup
+func parseSignature(b []byte) (bls12381.G2Affine, error) {
+	var sig bls12381.G2Affine
+	if len(b) != SignatureSize {
+		return sig, fmt.Errorf("signature has %d bytes, expected %d", len(b), SignatureSize)
+	}
+	if _, err := sig.SetBytes(b); err != nil {
+		return sig, fmt.Errorf("invalid signature: %w", err)
+	}
+	return sig, nil
+}
+
+// Verify checks a signature over msg by pk, returning ErrInvalidSignature
+// when it does not verify
+func Verify(pk *PublicKey, msg []byte, signature []byte) error {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return err
+	}
+	hashed, err := bls12381.HashToG2(msg, []byte(DST))
+	if err != nil {
+		return fmt.Errorf("hashing message to G2: %w", err)
+	}
+
+	// e(pk, H(msg)) == e(g1, sig)  <=>  e(pk, H(msg)) * e(-g1, sig) == 1
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{pk.point, negG1}, []bls12381.G2Affine{hashed, sig})
+	if err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// FastAggregateVerify checks an aggregate signature by pubkeys over the same
+// message
+func FastAggregateVerify(pubkeys []*PublicKey, msg []byte, signature []byte) error {
+	aggregate, err := AggregatePublicKeys(pubkeys)
+	if err != nil {
+		return err
+	}
+	return Verify(aggregate, msg, signature)
+}
@@ -0,0 +1,169 @@
+package bls
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// testSecretKey returns the secret key with a small scalar value
+func testSecretKey(t *testing.T, n byte) *SecretKey {
+	t.Helper()
+	sk, err := NewSecretKey([]byte{n})
+	if err != nil {
+		t.Fatalf("NewSecretKey() error = %v", err)
+	}
+	return sk
+}
+
+func sign(t *testing.T, sk *SecretKey, msg []byte) []byte {
+	t.Helper()
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return sig
+}
+
+func TestParsePublicKey(t *testing.T) {
+	// The secret key 1 has the G1 generator as its public key
+	generator := "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	pk, err := ParsePublicKey(testSecretKey(t, 1).PublicKey().Bytes())
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	if got := hex.EncodeToString(pk.Bytes()); got != generator {
+		t.Errorf("public key of secret 1 = %s, want %s", got, generator)
+	}
+
+	infinity := make([]byte, PublicKeySize)
+	infinity[0] = 0xc0
+	notOnCurve, _ := hex.DecodeString(generator[:len(generator)-1] + "c")
+	for name, b := range map[string][]byte{
+		"Infinity":     infinity,
+		"Not on curve": notOnCurve,
+		"Short":        make([]byte, 47),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePublicKey(b); err == nil {
+				t.Error("ParsePublicKey() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewSecretKey(t *testing.T) {
+	if _, err := NewSecretKey(make([]byte, 32)); err == nil {
+		t.Error("NewSecretKey(0) expected error, got nil")
+	}
+	order, _ := hex.DecodeString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	if _, err := NewSecretKey(order); err == nil {
+		t.Error("NewSecretKey(r) expected error, got nil")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	sk := testSecretKey(t, 123)
+	msg := []byte("beacon block signing root")
+	sig := sign(t, sk, msg)
+
+	if err := Verify(sk.PublicKey(), msg, sig); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := Verify(sk.PublicKey(), []byte("other message"), sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() wrong message error = %v, want ErrInvalidSignature", err)
+	}
+	if err := Verify(testSecretKey(t, 42).PublicKey(), msg, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() wrong key error = %v, want ErrInvalidSignature", err)
+	}
+	if err := Verify(sk.PublicKey(), msg, sig[:95]); err == nil || errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() short signature error = %v, want a decoding error", err)
+	}
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	msg := []byte("sync committee signing root")
+	var pubkeys []*PublicKey
+	var signatures [][]byte
+	for _, n := range []byte{11, 22, 33} {
+		sk := testSecretKey(t, n)
+		pubkeys = append(pubkeys, sk.PublicKey())
+		signatures = append(signatures, sign(t, sk, msg))
+	}
+	sig, err := AggregateSignatures(signatures)
+	if err != nil {
+		t.Fatalf("AggregateSignatures() error = %v", err)
+	}
+
+	if err := FastAggregateVerify(pubkeys, msg, sig); err != nil {
+		t.Fatalf("FastAggregateVerify() error = %v", err)
+	}
+	// The aggregate is the signature of the summed secret keys
+	if err := Verify(testSecretKey(t, 66).PublicKey(), msg, sig); err != nil {
+		t.Errorf("Verify() with summed key error = %v", err)
+	}
+	if err := FastAggregateVerify(pubkeys[:2], msg, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("FastAggregateVerify() missing signer error = %v, want ErrInvalidSignature", err)
+	}
+	if err := FastAggregateVerify(nil, msg, sig); err == nil {
+		t.Error("FastAggregateVerify() expected error without public keys, got nil")
+	}
+}
+
+// mustHex decodes a hex test vector
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+// TestSignSpecVector pins hashing to G2 and the DST to the Ethereum
+// ciphersuite with the consensus spec's sign_case_142f678a8d05fcd1
+func TestSignSpecVector(t *testing.T) {
+	sk, err := NewSecretKey(mustHex(t, "47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138"))
+	if err != nil {
+		t.Fatalf("NewSecretKey() error = %v", err)
+	}
+	msg := make([]byte, 32)
+	want := "b23c46be3a001c63ca711f87a005c200cc550b9429d5f4eb38d74322144f1b63926da3388979e5321012fb1a0526bcd1" +
+		"00b5ef5fe72628ce4cd5e904aeaa3279527843fae5ca9ca675f4f51ed8f83bbf7155da9ecc9663100a885d5dc6df96d9"
+	if got := hex.EncodeToString(sign(t, sk, msg)); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if err := Verify(sk.PublicKey(), msg, mustHex(t, want)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+// TestFastAggregateVerifySpecVector checks the consensus spec's
+// fast_aggregate_verify_valid_3d7576f3c0e3570a and its tampered variants
+func TestFastAggregateVerifySpecVector(t *testing.T) {
+	var pubkeys []*PublicKey
+	for _, s := range []string{
+		"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+		"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+		"b53d21a4cfd562c469cc81514d4ce5a6b577d8403d32a394dc265dd190b47fa9f829fdd7963afdf972e5e77854051f6f",
+	} {
+		pk, err := ParsePublicKey(mustHex(t, s))
+		if err != nil {
+			t.Fatalf("ParsePublicKey() error = %v", err)
+		}
+		pubkeys = append(pubkeys, pk)
+	}
+	msg := mustHex(t, "abababababababababababababababababababababababababababababababab")
+	sig := mustHex(t, "9712c3edd73a209c742b8250759db12549b3eaf43b5ca61376d9f30e2747dbcf842d8b2ac0901d2a093713e20284a767"+
+		"0fcf6954e9ab93de991bb9b313e664785a075fc285806fa5224c82bde146561b446ccfc706a64b8579513cfc4ff1d930")
+
+	if err := FastAggregateVerify(pubkeys, msg, sig); err != nil {
+		t.Fatalf("FastAggregateVerify() error = %v", err)
+	}
+	if err := FastAggregateVerify(pubkeys[:2], msg, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("FastAggregateVerify() missing signer error = %v, want ErrInvalidSignature", err)
+	}
+	if err := FastAggregateVerify(pubkeys, make([]byte, 32), sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("FastAggregateVerify() other message error = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package chaintime converts between slots, epochs, sync committee periods,
+// wall-clock time and execution timestamps of a beacon chain, and finds the
+// fork in effect at any of them
+package chaintime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// Clock holds the genesis and spec values that fix a chain's timeline
+type Clock struct {
+	GenesisTime                  uint64
+	SecondsPerSlot               uint64
+	SlotsPerEpoch                uint64
+	EpochsPerSyncCommitteePeriod uint64
+	// Forks may be empty, in which case fork lookups fail
+	Forks beacon.ForkSchedule
+}
+
+// ErrBeforeGenesis is returned for times before the chain started
+var ErrBeforeGenesis = errors.New("before genesis")
+
+// New creates a clock, rejecting zero durations
+func New(genesisTime, secondsPerSlot, slotsPerEpoch, epochsPerSyncCommitteePeriod uint64, forks beacon.ForkSchedule) (*Clock, error) {
+	if secondsPerSlot == 0 || slotsPerEpoch == 0 || epochsPerSyncCommitteePeriod == 0 {
+		return nil, fmt.Errorf("invalid chain timing: %d seconds per slot, %d slots per epoch, %d epochs per sync committee period",
+			secondsPerSlot, slotsPerEpoch, epochsPerSyncCommitteePeriod)
+	}
+	return &Clock{
+		GenesisTime:                  genesisTime,
+		SecondsPerSlot:               secondsPerSlot,
+		SlotsPerEpoch:                slotsPerEpoch,
+		EpochsPerSyncCommitteePeriod: epochsPerSyncCommitteePeriod,
+		Forks:                        forks,
+	}, nil
+}
+
+// FromSpec creates a clock from a chain spec reported by a beacon node
+func FromSpec(spec *beacon.ChainSpec) (*Clock, error) {
+	return New(spec.GenesisTime, spec.SecondsPerSlot, spec.SlotsPerEpoch, spec.EpochsPerSyncCommitteePeriod, spec.Forks)
+}
+
+// ForChain creates the clock of a known public chain with mainnet timing
+func ForChain(chainID int) (*Clock, error) {
+	genesis, err := beacon.GenesisTimeForChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	forks, err := beacon.ForkScheduleForChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return New(genesis, beacon.SecondsPerSlot, beacon.SlotsPerEpoch, beacon.EpochsPerSyncCommitteePeriod, forks)
+}
+
+// Genesis returns the start of slot 0
+func (c *Clock) Genesis() time.Time {
+	return time.Unix(int64(c.GenesisTime), 0).UTC()
+}
+
+// SlotTime returns the start of a slot
+func (c *Clock) SlotTime(slot uint64) time.Time {
+	return time.Unix(int64(c.SlotTimestamp(slot)), 0).UTC()
+}
+
+// SlotTimestamp returns the start of a slot in Unix seconds
+func (c *Clock) SlotTimestamp(slot uint64) uint64 {
+	return c.GenesisTime + slot*c.SecondsPerSlot
+}
+
+// SlotAt returns the slot in progress at t
+func (c *Clock) SlotAt(t time.Time) (uint64, error) {
+	if t.Unix() < int64(c.GenesisTime) {
+		return 0, fmt.Errorf("%s is %w at %s", t.UTC().Format(time.RFC3339), ErrBeforeGenesis, c.Genesis().Format(time.RFC3339))
+	}
+	return (uint64(t.Unix()) - c.GenesisTime) / c.SecondsPerSlot, nil
+}
+
+// SlotOffset returns how far into its slot t falls
+func (c *Clock) SlotOffset(t time.Time) (time.Duration, error) {
+	slot, err := c.SlotAt(t)
+	if err != nil {
+		return 0, err
+	}
+	return t.Sub(c.SlotTime(slot)), nil
+}
+
+// EpochOf returns the epoch containing slot
+func (c *Clock) EpochOf(slot uint64) uint64 {
+	return slot / c.SlotsPerEpoch
+}
+
+// EpochStart returns the first slot of an epoch
+func (c *Clock) EpochStart(epoch uint64) uint64 {
+	return epoch * c.SlotsPerEpoch
+}
+
+// EpochTime returns the start of an epoch
+func (c *Clock) EpochTime(epoch uint64) time.Time {
+	return c.SlotTime(c.EpochStart(epoch))
+}
+
+// EpochAt returns the epoch in progress at t
+func (c *Clock) EpochAt(t time.Time) (uint64, error) {
+	slot, err := c.SlotAt(t)
+	if err != nil {
+		return 0, err
+	}
+	return c.EpochOf(slot), nil
+}
+
+// SyncPeriodOf returns the sync committee period containing slot
+func (c *Clock) SyncPeriodOf(slot uint64) uint64 {
+	return c.SyncPeriodOfEpoch(c.EpochOf(slot))
+}
+
+// SyncPeriodOfEpoch returns the sync committee period containing epoch
+func (c *Clock) SyncPeriodOfEpoch(epoch uint64) uint64 {
+	return epoch / c.EpochsPerSyncCommitteePeriod
+}
+
+// SyncPeriodStart returns the first slot of a sync committee period
+func (c *Clock) SyncPeriodStart(period uint64) uint64 {
+	return c.EpochStart(period * c.EpochsPerSyncCommitteePeriod)
+}
+
+// SyncPeriodAt returns the sync committee period in progress at t
+func (c *Clock) SyncPeriodAt(t time.Time) (uint64, error) {
+	slot, err := c.SlotAt(t)
+	if err != nil {
+		return 0, err
+	}
+	return c.SyncPeriodOf(slot), nil
+}
+
+// ExecutionTimestamp returns the timestamp of the execution payload of a
+// block at slot, which is the slot's start. Slots in forks without
+// execution payloads have none.
+func (c *Clock) ExecutionTimestamp(slot uint64) (uint64, error) {
+	if fork, err := c.ForkAt(slot); err == nil && !hasExecutionPayload(fork.Name) {
+		return 0, fmt.Errorf("slot %d is in %s, before execution payloads", slot, fork.Name)
+	}
+	return c.SlotTimestamp(slot), nil
+}
+
+// SlotOfExecutionTimestamp returns the slot of the block whose execution
+// payload carries timestamp. Timestamps between slot starts belong to no
+// block.
+func (c *Clock) SlotOfExecutionTimestamp(timestamp uint64) (uint64, error) {
+	if timestamp < c.GenesisTime {
+		return 0, fmt.Errorf("timestamp %d is %w at %d", timestamp, ErrBeforeGenesis, c.GenesisTime)
+	}
+	elapsed := timestamp - c.GenesisTime
+	if elapsed%c.SecondsPerSlot != 0 {
+		return 0, fmt.Errorf("timestamp %d is %d seconds into slot %d, not a slot start",
+			timestamp, elapsed%c.SecondsPerSlot, elapsed/c.SecondsPerSlot)
+	}
+	slot := elapsed / c.SecondsPerSlot
+	if _, err := c.ExecutionTimestamp(slot); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
+
+// hasExecutionPayload reports whether blocks of a fork carry an execution
+// payload; every fork from Bellatrix on does
+func hasExecutionPayload(fork string) bool {
+	return fork != "phase0" && fork != "altair"
+}
+
+// ForkAt returns the fork in effect at slot
+func (c *Clock) ForkAt(slot uint64) (beacon.ForkEpoch, error) {
+	return c.Forks.ForkAtEpoch(c.EpochOf(slot))
+}
+
+// ForkAtTime returns the fork in effect at t
+func (c *Clock) ForkAtTime(t time.Time) (beacon.ForkEpoch, error) {
+	slot, err := c.SlotAt(t)
+	if err != nil {
+		return beacon.ForkEpoch{}, err
+	}
+	return c.ForkAt(slot)
+}
+
+// SigningForkAt returns the fork whose version signs a sync aggregate
+// included at signatureSlot. The committee signs the previous slot's block,
+// so on the first slot of a fork the version of the old fork still applies.
+func (c *Clock) SigningForkAt(signatureSlot uint64) (beacon.ForkEpoch, error) {
+	return c.ForkAt(max(signatureSlot, 1) - 1)
+}
+
+// ForkStart returns the first slot of a fork
+func (c *Clock) ForkStart(name string) (uint64, error) {
+	fork, ok := c.Forks.Fork(name)
+	if !ok {
+		return 0, fmt.Errorf("fork %q is not scheduled", name)
+	}
+	return c.EpochStart(fork.Epoch), nil
+}
+
+// NextFork returns the first fork activating after slot. ok is false when
+// none is scheduled.
+func (c *Clock) NextFork(slot uint64) (fork beacon.ForkEpoch, ok bool) {
+	epoch := c.EpochOf(slot)
+	for _, f := range c.Forks.Forks {
+		if f.Epoch > epoch {
+			return f, true
+		}
+	}
+	return beacon.ForkEpoch{}, false
+}
+
+// IsForkBoundary reports whether slot is the first slot of a fork
+func (c *Clock) IsForkBoundary(slot uint64) bool {
+	if slot%c.SlotsPerEpoch != 0 {
+		return false
+	}
+	for _, f := range c.Forks.Forks {
+		if c.EpochStart(f.Epoch) == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// SameFork reports whether two slots fall in the same fork, so that
+// containers from one can be read with the other's layouts
+func (c *Clock) SameFork(a, b uint64) (bool, error) {
+	forkA, err := c.ForkAt(a)
+	if err != nil {
+		return false, err
+	}
+	forkB, err := c.ForkAt(b)
+	if err != nil {
+		return false, err
+	}
+	return forkA.Name == forkB.Name, nil
+}
@@ -0,0 +1,429 @@
+package chaintime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+func mainnet(t *testing.T) *Clock {
+	t.Helper()
+	clock, err := ForChain(1)
+	if err != nil {
+		t.Fatalf("ForChain(1) error = %v", err)
+	}
+	return clock
+}
+
+// earlyClock has the forks before Capella scheduled, as a chain spec served
+// by a node would
+func earlyClock(t *testing.T) *Clock {
+	t.Helper()
+	forks := beacon.NewForkSchedule(32,
+		beacon.ForkEpoch{Name: "phase0", Epoch: 0},
+		beacon.ForkEpoch{Name: "altair", Epoch: 10},
+		beacon.ForkEpoch{Name: "bellatrix", Epoch: 20},
+		beacon.ForkEpoch{Name: beacon.Capella, Epoch: 30},
+	)
+	clock, err := New(1000, 12, 32, 256, forks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clock
+}
+
+func TestForChain(t *testing.T) {
+	tests := []struct {
+		chainID int
+		genesis int64
+	}{
+		{1, 1606824023},
+		{17000, 1695902400},
+		{11155111, 1655733600},
+		{560048, 1742213400},
+	}
+	for _, tt := range tests {
+		clock, err := ForChain(tt.chainID)
+		if err != nil {
+			t.Fatalf("ForChain(%d) error = %v", tt.chainID, err)
+		}
+		if got := clock.Genesis(); got.Unix() != tt.genesis || got.Location() != time.UTC {
+			t.Errorf("ForChain(%d).Genesis() = %v, want %d UTC", tt.chainID, got, tt.genesis)
+		}
+		if clock.SecondsPerSlot != 12 || clock.SlotsPerEpoch != 32 || clock.EpochsPerSyncCommitteePeriod != 256 {
+			t.Errorf("ForChain(%d) timing = %+v", tt.chainID, clock)
+		}
+		if !clock.SlotTime(0).Equal(clock.Genesis()) {
+			t.Errorf("ForChain(%d).SlotTime(0) = %v, want genesis", tt.chainID, clock.SlotTime(0))
+		}
+	}
+
+	if _, err := ForChain(5); err == nil {
+		t.Error("ForChain(5) expected error for an unknown chain, got nil")
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, timing := range [][3]uint64{{0, 32, 256}, {12, 0, 256}, {12, 32, 0}} {
+		if _, err := New(0, timing[0], timing[1], timing[2], beacon.ForkSchedule{}); err == nil {
+			t.Errorf("New() with timing %v expected error, got nil", timing)
+		}
+	}
+}
+
+func TestFromSpec(t *testing.T) {
+	spec := &beacon.ChainSpec{
+		GenesisTime:                  1742213400,
+		SecondsPerSlot:               6,
+		SlotsPerEpoch:                8,
+		EpochsPerSyncCommitteePeriod: 4,
+	}
+	clock, err := FromSpec(spec)
+	if err != nil {
+		t.Fatalf("FromSpec() error = %v", err)
+	}
+	if got := clock.SlotTimestamp(10); got != 1742213460 {
+		t.Errorf("SlotTimestamp(10) = %d, want 1742213460", got)
+	}
+	if got := clock.SyncPeriodOf(64); got != 2 {
+		t.Errorf("SyncPeriodOf(64) = %d, want 2", got)
+	}
+
+	spec.SecondsPerSlot = 0
+	if _, err := FromSpec(spec); err == nil {
+		t.Error("FromSpec() with zero seconds per slot expected error, got nil")
+	}
+}
+
+func TestSlotTime(t *testing.T) {
+	clock := mainnet(t)
+	tests := []struct {
+		name string
+		slot uint64
+		want int64
+	}{
+		{"Genesis", 0, 1606824023},
+		{"FirstSlot", 1, 1606824035},
+		{"Capella", 6209536, 1681338455},
+		{"Deneb", 8626176, 1710338135},
+		{"Electra", 11649024, 1746612311},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clock.SlotTime(tt.slot); got.Unix() != tt.want {
+				t.Errorf("SlotTime(%d) = %d, want %d", tt.slot, got.Unix(), tt.want)
+			}
+			if got := clock.SlotTimestamp(tt.slot); got != uint64(tt.want) {
+				t.Errorf("SlotTimestamp(%d) = %d, want %d", tt.slot, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotAt(t *testing.T) {
+	clock := mainnet(t)
+	genesis := clock.Genesis()
+	tests := []struct {
+		name   string
+		t      time.Time
+		slot   uint64
+		offset time.Duration
+	}{
+		{"Genesis", genesis, 0, 0},
+		{"LastSecondOfSlot0", genesis.Add(11 * time.Second), 0, 11 * time.Second},
+		{"SubSecond", genesis.Add(11*time.Second + 999*time.Millisecond), 0, 11*time.Second + 999*time.Millisecond},
+		{"Slot1", genesis.Add(12 * time.Second), 1, 0},
+		{"MidSlot", time.Unix(1710338135+7, 0), 8626176, 7 * time.Second},
+		{"OtherZone", time.Unix(1710338135, 0).In(time.FixedZone("UTC+5", 5*3600)), 8626176, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slot, err := clock.SlotAt(tt.t)
+			if err != nil || slot != tt.slot {
+				t.Errorf("SlotAt() = %d, %v, want %d", slot, err, tt.slot)
+			}
+			offset, err := clock.SlotOffset(tt.t)
+			if err != nil || offset != tt.offset {
+				t.Errorf("SlotOffset() = %v, %v, want %v", offset, err, tt.offset)
+			}
+		})
+	}
+
+	before := genesis.Add(-time.Second)
+	if _, err := clock.SlotAt(before); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("SlotAt() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+	if _, err := clock.SlotOffset(before); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("SlotOffset() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+	if _, err := clock.EpochAt(before); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("EpochAt() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+	if _, err := clock.SyncPeriodAt(before); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("SyncPeriodAt() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+	if _, err := clock.ForkAtTime(before); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("ForkAtTime() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+}
+
+func TestSlotRoundTrip(t *testing.T) {
+	clock := mainnet(t)
+	for _, slot := range []uint64{0, 1, 31, 32, 8191, 8192, 6209535, 6209536, 13164544, 1 << 32} {
+		got, err := clock.SlotAt(clock.SlotTime(slot))
+		if err != nil || got != slot {
+			t.Errorf("SlotAt(SlotTime(%d)) = %d, %v", slot, got, err)
+		}
+		epoch := clock.EpochOf(slot)
+		if start := clock.EpochStart(epoch); start > slot || slot-start >= clock.SlotsPerEpoch {
+			t.Errorf("EpochStart(EpochOf(%d)) = %d, not the start of the slot's epoch", slot, start)
+		}
+		period := clock.SyncPeriodOf(slot)
+		if start := clock.SyncPeriodStart(period); start > slot || slot-start >= clock.SlotsPerEpoch*clock.EpochsPerSyncCommitteePeriod {
+			t.Errorf("SyncPeriodStart(SyncPeriodOf(%d)) = %d, not the start of the slot's period", slot, start)
+		}
+	}
+}
+
+func TestEpochs(t *testing.T) {
+	clock := mainnet(t)
+	tests := []struct {
+		slot, epoch uint64
+	}{
+		{0, 0},
+		{31, 0},
+		{32, 1},
+		{63, 1},
+		{64, 2},
+		{6209536, 194048},
+		{6209535, 194047},
+	}
+	for _, tt := range tests {
+		if got := clock.EpochOf(tt.slot); got != tt.epoch {
+			t.Errorf("EpochOf(%d) = %d, want %d", tt.slot, got, tt.epoch)
+		}
+	}
+
+	if got := clock.EpochStart(194048); got != 6209536 {
+		t.Errorf("EpochStart(194048) = %d, want 6209536", got)
+	}
+	if got := clock.EpochTime(269568); got.Unix() != 1710338135 {
+		t.Errorf("EpochTime(269568) = %d, want 1710338135", got.Unix())
+	}
+	epoch, err := clock.EpochAt(time.Unix(1710338135+32*12-1, 0))
+	if err != nil || epoch != 269568 {
+		t.Errorf("EpochAt(last second of epoch 269568) = %d, %v", epoch, err)
+	}
+	epoch, err = clock.EpochAt(time.Unix(1710338135+32*12, 0))
+	if err != nil || epoch != 269569 {
+		t.Errorf("EpochAt(start of epoch 269569) = %d, %v", epoch, err)
+	}
+}
+
+func TestSyncPeriods(t *testing.T) {
+	clock := mainnet(t)
+	tests := []struct {
+		slot, period uint64
+	}{
+		{0, 0},
+		{8191, 0},
+		{8192, 1},
+		{16383, 1},
+		{6209536, 758},
+		{8626176, 1053},
+		{11649024, 1422},
+	}
+	for _, tt := range tests {
+		if got := clock.SyncPeriodOf(tt.slot); got != tt.period {
+			t.Errorf("SyncPeriodOf(%d) = %d, want %d", tt.slot, got, tt.period)
+		}
+	}
+
+	if got := clock.SyncPeriodOfEpoch(255); got != 0 {
+		t.Errorf("SyncPeriodOfEpoch(255) = %d, want 0", got)
+	}
+	if got := clock.SyncPeriodOfEpoch(256); got != 1 {
+		t.Errorf("SyncPeriodOfEpoch(256) = %d, want 1", got)
+	}
+	if got := clock.SyncPeriodStart(758); got != 6209536 {
+		t.Errorf("SyncPeriodStart(758) = %d, want 6209536", got)
+	}
+	period, err := clock.SyncPeriodAt(time.Unix(1681338455, 0))
+	if err != nil || period != 758 {
+		t.Errorf("SyncPeriodAt(Capella) = %d, %v, want 758", period, err)
+	}
+}
+
+func TestExecutionTimestamps(t *testing.T) {
+	clock := mainnet(t)
+
+	timestamp, err := clock.ExecutionTimestamp(8626176)
+	if err != nil || timestamp != 1710338135 {
+		t.Errorf("ExecutionTimestamp(8626176) = %d, %v, want 1710338135", timestamp, err)
+	}
+	slot, err := clock.SlotOfExecutionTimestamp(1710338135)
+	if err != nil || slot != 8626176 {
+		t.Errorf("SlotOfExecutionTimestamp(1710338135) = %d, %v, want 8626176", slot, err)
+	}
+
+	for _, bad := range []uint64{1710338136, 1710338146} {
+		if _, err := clock.SlotOfExecutionTimestamp(bad); err == nil {
+			t.Errorf("SlotOfExecutionTimestamp(%d) expected error for a timestamp inside a slot, got nil", bad)
+		}
+	}
+	if _, err := clock.SlotOfExecutionTimestamp(1606824022); !errors.Is(err, ErrBeforeGenesis) {
+		t.Errorf("SlotOfExecutionTimestamp() before genesis error = %v, want ErrBeforeGenesis", err)
+	}
+
+	// Only blocks from Bellatrix on carry an execution payload
+	early := earlyClock(t)
+	tests := []struct {
+		slot uint64
+		ok   bool
+	}{
+		{0, false},
+		{10*32 - 1, false},
+		{10 * 32, false},
+		{20*32 - 1, false},
+		{20 * 32, true},
+		{30 * 32, true},
+	}
+	for _, tt := range tests {
+		_, err := early.ExecutionTimestamp(tt.slot)
+		if (err == nil) != tt.ok {
+			t.Errorf("ExecutionTimestamp(%d) error = %v, want ok %v", tt.slot, err, tt.ok)
+		}
+		_, err = early.SlotOfExecutionTimestamp(early.SlotTimestamp(tt.slot))
+		if (err == nil) != tt.ok {
+			t.Errorf("SlotOfExecutionTimestamp(slot %d) error = %v, want ok %v", tt.slot, err, tt.ok)
+		}
+	}
+}
+
+func TestForks(t *testing.T) {
+	clock := mainnet(t)
+	tests := []struct {
+		slot uint64
+		fork string
+	}{
+		{6209536, beacon.Capella},
+		{8626175, beacon.Capella},
+		{8626176, beacon.Deneb},
+		{11649023, beacon.Deneb},
+		{11649024, beacon.Electra},
+		{13164544, beacon.Fulu},
+		{1 << 40, beacon.Fulu},
+	}
+	for _, tt := range tests {
+		fork, err := clock.ForkAt(tt.slot)
+		if err != nil || fork.Name != tt.fork {
+			t.Errorf("ForkAt(%d) = %q, %v, want %q", tt.slot, fork.Name, err, tt.fork)
+		}
+		fork, err = clock.ForkAtTime(clock.SlotTime(tt.slot))
+		if err != nil || fork.Name != tt.fork {
+			t.Errorf("ForkAtTime(slot %d) = %q, %v, want %q", tt.slot, fork.Name, err, tt.fork)
+		}
+	}
+
+	// The built-in schedule starts at Capella
+	if _, err := clock.ForkAt(6209535); err == nil {
+		t.Error("ForkAt() before the first scheduled fork expected error, got nil")
+	}
+	if _, err := (&Clock{SlotsPerEpoch: 32}).ForkAt(0); err == nil {
+		t.Error("ForkAt() without a schedule expected error, got nil")
+	}
+
+	start, err := clock.ForkStart(beacon.Electra)
+	if err != nil || start != 11649024 {
+		t.Errorf("ForkStart(electra) = %d, %v, want 11649024", start, err)
+	}
+	if _, err := clock.ForkStart("gloas"); err == nil {
+		t.Error("ForkStart() of an unscheduled fork expected error, got nil")
+	}
+}
+
+func TestForkBoundaries(t *testing.T) {
+	clock := mainnet(t)
+	tests := []struct {
+		slot     uint64
+		boundary bool
+	}{
+		{8626176, true},
+		{8626175, false},
+		{8626177, false},
+		{8626176 + 32, false},
+		{11649024, true},
+		{0, false},
+	}
+	for _, tt := range tests {
+		if got := clock.IsForkBoundary(tt.slot); got != tt.boundary {
+			t.Errorf("IsForkBoundary(%d) = %v, want %v", tt.slot, got, tt.boundary)
+		}
+	}
+
+	next, ok := clock.NextFork(8626175)
+	if !ok || next.Name != beacon.Deneb {
+		t.Errorf("NextFork(8626175) = %q, %v, want deneb", next.Name, ok)
+	}
+	next, ok = clock.NextFork(8626176)
+	if !ok || next.Name != beacon.Electra {
+		t.Errorf("NextFork(8626176) = %q, %v, want electra", next.Name, ok)
+	}
+	if next, ok := clock.NextFork(13164544); ok {
+		t.Errorf("NextFork(after the last fork) = %q, want none", next.Name)
+	}
+
+	// A sync aggregate in the first slot of a fork signs the last block of
+	// the previous fork
+	signingTests := []struct {
+		slot uint64
+		fork string
+	}{
+		{8626175, beacon.Capella},
+		{8626176, beacon.Capella},
+		{8626177, beacon.Deneb},
+	}
+	for _, tt := range signingTests {
+		fork, err := clock.SigningForkAt(tt.slot)
+		if err != nil || fork.Name != tt.fork {
+			t.Errorf("SigningForkAt(%d) = %q, %v, want %q", tt.slot, fork.Name, err, tt.fork)
+		}
+	}
+	// Slot 0 has no previous slot and signs with its own fork
+	early := earlyClock(t)
+	if fork, err := early.SigningForkAt(0); err != nil || fork.Name != "phase0" {
+		t.Errorf("SigningForkAt(0) = %q, %v, want phase0", fork.Name, err)
+	}
+
+	same, err := clock.SameFork(8626176, 11649023)
+	if err != nil || !same {
+		t.Errorf("SameFork(deneb, deneb) = %v, %v, want true", same, err)
+	}
+	same, err = clock.SameFork(8626175, 8626176)
+	if err != nil || same {
+		t.Errorf("SameFork(capella, deneb) = %v, %v, want false", same, err)
+	}
+	if _, err := clock.SameFork(0, 8626176); err == nil {
+		t.Error("SameFork() with an unscheduled slot expected error, got nil")
+	}
+}
+
+func TestHoodiForksAtGenesis(t *testing.T) {
+	clock, err := ForChain(560048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Capella and Deneb both activate at genesis; the later one applies
+	fork, err := clock.ForkAt(0)
+	if err != nil || fork.Name != beacon.Deneb {
+		t.Errorf("ForkAt(0) = %q, %v, want deneb", fork.Name, err)
+	}
+	if !clock.IsForkBoundary(0) {
+		t.Error("IsForkBoundary(0) = false on a chain forking at genesis")
+	}
+	if got := clock.SlotTime(2048 * 32).Unix(); got != 1742213400+2048*32*12 {
+		t.Errorf("SlotTime(Electra) = %d", got)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/audit"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
+)
+
+const auditBlockUsage = `Usage: beacon-verifier audit-block [flags] [block id]
+
+Downloads a full block (head by default), recomputes its body root for the
+block's fork and compares it and the message fields with the header served
+by the same node. Unless -blinded=false, the blinded block is fetched too and
+every body field is compared with it, descending into differing subtrees to
+report mismatches down to the field.
+
+With -state, the block's post-state is downloaded as SSZ and state_root is
+recomputed as well. With -count, that many blocks are audited walking back
+through parent_root; consecutive states share unchanged subtrees, so only
+what changed between them is rehashed.
+
+Flags:
+`
+
+// runAuditBlock implements the audit-block subcommand
+func runAuditBlock(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("audit-block", flag.ContinueOnError)
+	flags.SetOutput(out)
+	endpoint := flags.String("beacon", config.DefaultConfig().BeaconAPI.Endpoints[0], "Beacon API endpoint")
+	withBlinded := flags.Bool("blinded", true, "Also compare the body field by field with the blinded block")
+	withState := flags.Bool("state", false, "Also recompute state_root from the SSZ post-state")
+	count := flags.Int("count", 1, "Number of blocks to audit, walking back through parent_root")
+	asJSON := flags.Bool("json", false, "Print the report as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(out, auditBlockUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("expected at most one block id")
+	}
+	if *count < 1 {
+		return fmt.Errorf("invalid -count %d", *count)
+	}
+	blockID := "head"
+	if flags.NArg() == 1 {
+		blockID = flags.Arg(0)
+	}
+
+	client := newClient(*endpoint)
+	var states audit.StateAuditor
+	failed := 0
+	for i := 0; i < *count; i++ {
+		// Only the header: a body that does not match it is what the audit
+		// reports, not a reason to stop
+		headerData, err := client.FetchHeader(ctx, blockID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch header %s: %w", blockID, err)
+		}
+		// Pin the rest of the audit to the header's root so head cannot move
+		if headerData.BlockRoot != "" {
+			blockID = headerData.BlockRoot
+		}
+
+		report, err := auditBlock(ctx, client, blockID, headerData, *withBlinded)
+		if err != nil {
+			return err
+		}
+		if !report.OK() {
+			failed++
+		}
+		if err := printReport(out, report, *asJSON, printBlockReport); err != nil {
+			return err
+		}
+
+		if *withState {
+			data, fork, err := client.FetchStateSSZ(ctx, headerData.Slot)
+			if err != nil {
+				return fmt.Errorf("failed to fetch state at slot %s: %w", headerData.Slot, err)
+			}
+			stateReport, err := states.Audit(headerData, fork, data)
+			if err != nil {
+				return fmt.Errorf("failed to audit state at slot %s: %w", headerData.Slot, err)
+			}
+			if !stateReport.OK() {
+				failed++
+			}
+			if err := printReport(out, stateReport, *asJSON, printStateReport); err != nil {
+				return err
+			}
+		}
+		blockID = headerData.ParentRoot
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d audits found mismatches", failed)
+	}
+	return nil
+}
+
+// auditBlock fetches the full and, if wanted, blinded block and audits them
+func auditBlock(ctx context.Context, client *beacon.Client, blockID string, headerData beacon.HeaderData, withBlinded bool) (audit.BlockReport, error) {
+	block, err := client.FetchBlock(ctx, blockID)
+	if err != nil {
+		return audit.BlockReport{}, fmt.Errorf("failed to fetch block %s: %w", blockID, err)
+	}
+	var blinded *beacon.Block
+	if withBlinded {
+		blinded, err = client.FetchBlindedBlock(ctx, blockID)
+		if err != nil {
+			log.Printf("Warning: blinded block unavailable, skipping field comparison: %v", err)
+			blinded = nil
+		}
+	}
+	return audit.AuditBlock(headerData, block, blinded)
+}
+
+// printReport prints a report as JSON or with its human-readable printer
+func printReport[R any](out io.Writer, report R, asJSON bool, printText func(io.Writer, R)) error {
+	if !asJSON {
+		printText(out, report)
+		return nil
+	}
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(encoded))
+	return nil
+}
+
+// printBlockReport prints a block audit in human-readable form
+func printBlockReport(out io.Writer, report audit.BlockReport) {
+	fmt.Fprintf(out, "slot:                %s\n", report.Slot)
+	fmt.Fprintf(out, "fork:                %s\n", report.Fork)
+	fmt.Fprintf(out, "header body_root:    %s\n", report.HeaderBodyRoot)
+	fmt.Fprintf(out, "computed body root:  %s\n", report.ComputedBodyRoot)
+	if report.BlindedBodyRoot != "" {
+		fmt.Fprintf(out, "blinded body root:   %s\n", report.BlindedBodyRoot)
+	}
+	printMismatches(out, report.Mismatches)
+}
+
+// printStateReport prints a state audit in human-readable form
+func printStateReport(out io.Writer, report audit.StateReport) {
+	fmt.Fprintf(out, "state size:          %d bytes\n", report.Size)
+	fmt.Fprintf(out, "header state_root:   %s\n", report.HeaderStateRoot)
+	fmt.Fprintf(out, "computed state root: %s\n", report.ComputedStateRoot)
+	fmt.Fprintf(out, "segments:            %d hashed, %d reused\n", report.Segments.Hashed, report.Segments.Reused)
+	printMismatches(out, report.Mismatches)
+}
+
+// printMismatches prints the outcome of an audit
+func printMismatches(out io.Writer, mismatches []audit.Mismatch) {
+	if len(mismatches) == 0 {
+		fmt.Fprintln(out, "result:              OK")
+		return
+	}
+	fmt.Fprintf(out, "result:              %d mismatches\n", len(mismatches))
+	for _, m := range mismatches {
+		fmt.Fprintf(out, "  %s\n    expected: %s\n    actual:   %s\n", m.Path, m.Expected, m.Actual)
+	}
+}
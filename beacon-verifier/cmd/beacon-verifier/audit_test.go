@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// tamperedBodyServer serves a Deneb header and blinded block that agree,
+// and a full block whose gas_used differs from both
+func tamperedBodyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	body := ssz.Zero(beacon.BeaconBlockBodyDenebType).(map[string]any)
+	payload := body["execution_payload"].(map[string]any)
+	payload["gas_used"] = "21000"
+
+	payloadHeader := make(map[string]any)
+	for _, f := range beacon.ExecutionPayloadHeaderDenebType.Fields {
+		if v, ok := payload[f.Name]; ok {
+			payloadHeader[f.Name] = v
+			continue
+		}
+		name := strings.TrimSuffix(f.Name, "_root")
+		i, _ := beacon.ExecutionPayloadDenebType.FieldIndex(name)
+		root, err := ssz.HashTreeRoot(beacon.ExecutionPayloadDenebType.Fields[i].Type, payload[name])
+		if err != nil {
+			t.Fatalf("HashTreeRoot(%s) error = %v", name, err)
+		}
+		payloadHeader[f.Name] = "0x" + hex.EncodeToString(root[:])
+	}
+	blindedBody := make(map[string]any)
+	for name, v := range body {
+		blindedBody[name] = v
+	}
+	delete(blindedBody, "execution_payload")
+	blindedBody["execution_payload_header"] = payloadHeader
+
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyDenebType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	message := map[string]any{
+		"slot":           "123456",
+		"proposer_index": "42",
+		"parent_root":    "0x" + strings.Repeat("11", 32),
+		"state_root":     "0x" + strings.Repeat("22", 32),
+		"body_root":      "0x" + hex.EncodeToString(bodyRoot[:]),
+	}
+	header := map[string]any{"data": map[string]any{
+		"root":   "0x" + strings.Repeat("33", 32),
+		"header": map[string]any{"message": message, "signature": "0x" + strings.Repeat("00", 96)},
+	}}
+	block := func(body map[string]any) map[string]any {
+		m := map[string]any{"body": body}
+		for _, name := range []string{"slot", "proposer_index", "parent_root", "state_root"} {
+			m[name] = message[name]
+		}
+		return map[string]any{"version": "deneb", "data": map[string]any{"message": m, "signature": "0x" + strings.Repeat("00", 96)}}
+	}
+
+	tamperedPayload := make(map[string]any)
+	for name, v := range payload {
+		tamperedPayload[name] = v
+	}
+	tamperedPayload["gas_used"] = "21001"
+	tampered := make(map[string]any)
+	for name, v := range body {
+		tampered[name] = v
+	}
+	tampered["execution_payload"] = tamperedPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/eth/v1/beacon/headers/123456":
+			json.NewEncoder(w).Encode(header)
+		case strings.HasPrefix(r.URL.Path, "/eth/v2/beacon/blocks/"):
+			json.NewEncoder(w).Encode(block(tampered))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/beacon/blinded_blocks/"):
+			json.NewEncoder(w).Encode(block(blindedBody))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAuditBlockReportsTamperedBody(t *testing.T) {
+	server := tamperedBodyServer(t)
+
+	var out bytes.Buffer
+	err := runAuditBlock(context.Background(), []string{"-beacon", server.URL, "123456"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "audits found mismatches") {
+		t.Fatalf("runAuditBlock() error = %v, want the mismatch count", err)
+	}
+	for _, want := range []string{"body_root", "body.execution_payload.gas_used"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report does not list %s:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+const gindexUsage = `Usage: beacon-verifier gindex [flags] <path>...
+       beacon-verifier gindex [flags] -decode <gindex>...
+
+Prints the generalized index, depth and leaf position of container paths, or
+decodes generalized indices back into paths.
+
+Paths are relative to the beacon block root by default: header fields are
+named directly and "body." / "state." descend below body_root / state_root,
+e.g. body.execution_payload.timestamp or state.randao_mixes[5].
+
+Flags:
+`
+
+// runGindex implements the gindex subcommand
+func runGindex(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("gindex", flag.ContinueOnError)
+	flags.SetOutput(out)
+	fork := flags.String("fork", beacon.Forks[len(beacon.Forks)-1], fmt.Sprintf("Fork layout to use (one of %s)", strings.Join(beacon.Forks, ", ")))
+	root := flags.String("root", "block", "Container the index is relative to: block, body or state")
+	decode := flags.Bool("decode", false, "Decode generalized indices into paths instead")
+	flags.Usage = func() {
+		fmt.Fprint(out, gindexUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no path or generalized index given")
+	}
+
+	schema, err := beacon.SchemaFor(*fork)
+	if err != nil {
+		return err
+	}
+	if *root != "block" && schema.Container(*root) == nil {
+		return fmt.Errorf("unknown root %q. Must be one of block, %s, %s", *root, beacon.BodyPathRoot, beacon.StatePathRoot)
+	}
+
+	for i, arg := range flags.Args() {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if *decode {
+			err = decodeGindex(out, schema, *root, arg)
+		} else {
+			err = printGindex(out, schema, *root, arg)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	return nil
+}
+
+// locate resolves path elements relative to the selected root
+func locate(schema *beacon.Schema, root string, path []string) (ssz.Location, error) {
+	if root == "block" {
+		return schema.Locate(path...)
+	}
+	return schema.Container(root).Locate(path...)
+}
+
+// printGindex prints where a path lives in the tree
+func printGindex(out io.Writer, schema *beacon.Schema, root string, arg string) error {
+	path, err := ssz.ParsePath(arg)
+	if err != nil {
+		return err
+	}
+	loc, err := locate(schema, root, path)
+	if err != nil {
+		return err
+	}
+	printLocation(out, schema, root, path, loc)
+	return nil
+}
+
+// decodeGindex prints the path a generalized index points at
+func decodeGindex(out io.Writer, schema *beacon.Schema, root string, arg string) error {
+	gindex, err := strconv.ParseUint(arg, 0, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generalized index: %w", err)
+	}
+
+	var path []string
+	if root == "block" {
+		path, err = schema.PathOf(gindex)
+	} else {
+		var rest uint64
+		path, rest, err = schema.Container(root).PathOf(gindex)
+		if err == nil && rest != 1 {
+			err = fmt.Errorf("generalized index points inside the value at %s", ssz.FormatPath(path))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	loc, err := locate(schema, root, path)
+	if err != nil {
+		return err
+	}
+	printLocation(out, schema, root, path, loc)
+	return nil
+}
+
+// printLocation prints the details of a resolved path
+func printLocation(out io.Writer, schema *beacon.Schema, root string, path []string, loc ssz.Location) {
+	depth := merkle.GeneralizedIndexDepth(loc.GeneralizedIndex)
+
+	fmt.Fprintf(out, "path:              %s\n", ssz.FormatPath(path))
+	fmt.Fprintf(out, "fork:              %s\n", schema.Fork)
+	fmt.Fprintf(out, "relative to:       %s root\n", root)
+	fmt.Fprintf(out, "generalized index: %d (0x%x)\n", loc.GeneralizedIndex, loc.GeneralizedIndex)
+	fmt.Fprintf(out, "depth:             %d (proof length)\n", depth)
+	fmt.Fprintf(out, "leaf position:     %d of %d\n", merkle.GeneralizedIndexPosition(loc.GeneralizedIndex), uint64(1)<<uint(depth))
+	fmt.Fprintf(out, "type:              %s\n", loc.Type.Name)
+	if loc.Packed {
+		fmt.Fprintf(out, "packed:            bytes %d..%d of the leaf chunk\n", loc.ByteOffset, loc.ByteOffset+loc.Type.Size-1)
+	}
+}
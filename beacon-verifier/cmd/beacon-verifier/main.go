@@ -2,26 +2,67 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Mikelle/beacon-root-verification/beacon-verifier/app"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
 	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
 )
 
+// subcommands run instead of the verifier when named as the first argument
+var subcommands = map[string]func(ctx context.Context, args []string, out io.Writer) error{
+	"gindex":            runGindex,
+	"audit-block":       runAuditBlock,
+	"proposer-slashing": runProposerSlashing,
+}
+
 func main() {
+	// Interrupting cancels in-flight requests instead of killing them midway
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(ctx, os.Args[2:], os.Stdout); err != nil {
+				if !errors.Is(err, flag.ErrHelp) {
+					log.Printf("Error: %v", err)
+				}
+				stop()
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Error loading configuration: %v", err)
 	}
 
-	application, err := app.NewApplication(cfg)
+	application, err := app.NewApplication(ctx, cfg)
 	if err != nil {
 		log.Fatalf("Error initializing application: %v", err)
 	}
 
-	if err := application.Run(); err != nil {
+	if err := application.Run(ctx); err != nil {
 		log.Printf("Error: %v", err)
+		stop()
 		os.Exit(1)
 	}
 }
+
+// newClient creates a beacon client for a subcommand with the default
+// request timeout
+func newClient(endpoint string) *beacon.Client {
+	client := beacon.NewClient(endpoint)
+	client.Timeout = time.Duration(config.DefaultConfig().BeaconAPI.RequestTimeoutMs) * time.Millisecond
+	return client
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/config"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/proof"
+)
+
+const proposerSlashingUsage = `Usage: beacon-verifier proposer-slashing [flags] [block id]
+
+Verifies a proposer slashing: both signed headers must be for the same slot
+and proposer, have different roots, and carry valid signatures by the
+proposer. The slashing is read from -file (in the beacon API JSON shape) or
+taken from proposer_slashings[-index] of the block (head by default).
+
+The proposer's public key is proven from the block's state and, when the
+block includes the slashing, its inclusion in the body is proven too. The
+resulting evidence bundle is printed as JSON; every proof in it is against
+the block's root.
+
+Flags:
+`
+
+// runProposerSlashing implements the proposer-slashing subcommand
+func runProposerSlashing(ctx context.Context, args []string, out io.Writer) error {
+	defaults := config.DefaultConfig()
+	flags := flag.NewFlagSet("proposer-slashing", flag.ContinueOnError)
+	flags.SetOutput(out)
+	endpoint := flags.String("beacon", defaults.BeaconAPI.Endpoints[0], "Beacon API endpoint")
+	chainID := flags.Int("chain", defaults.EthereumNode.ChainID, "Chain ID whose fork schedule provides the signing domains")
+	file := flags.String("file", "", "JSON file holding the proposer slashing")
+	index := flags.Int("index", 0, "Index into the block's proposer_slashings when no -file is given")
+	flags.Usage = func() {
+		fmt.Fprint(out, proposerSlashingUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("expected at most one block id")
+	}
+	blockID := "head"
+	if flags.NArg() == 1 {
+		blockID = flags.Arg(0)
+	}
+
+	schedule, err := beacon.ForkScheduleForChain(*chainID)
+	if err != nil {
+		return err
+	}
+	client := newClient(*endpoint)
+	headerData, err := client.FetchBlockHeader(ctx, blockID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch header %s: %w", blockID, err)
+	}
+	if headerData.BlockRoot != "" {
+		blockID = headerData.BlockRoot
+	}
+	block, err := client.FetchBlock(ctx, blockID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block %s: %w", blockID, err)
+	}
+	schema, err := beacon.SchemaFor(block.Version)
+	if err != nil {
+		return err
+	}
+	body, err := block.Body()
+	if err != nil {
+		return err
+	}
+	included, _ := body["proposer_slashings"].([]any)
+
+	var slashing beacon.ProposerSlashing
+	found := -1
+	if *file != "" {
+		if slashing, err = readProposerSlashing(*file); err != nil {
+			return err
+		}
+		if found, err = findProposerSlashing(included, slashing); err != nil {
+			return err
+		}
+	} else {
+		if *index < 0 || *index >= len(included) {
+			return fmt.Errorf("block %s has %d proposer slashings, no index %d", blockID, len(included), *index)
+		}
+		if slashing, err = beacon.ParseProposerSlashing(included[*index]); err != nil {
+			return err
+		}
+		found = *index
+	}
+
+	log.Printf("Fetching state %s for the proposer's public key...", headerData.StateRoot)
+	state, err := client.FetchState(ctx, headerData.StateRoot)
+	if err != nil {
+		return fmt.Errorf("failed to fetch state %s: %w", headerData.StateRoot, err)
+	}
+	evidence, err := proof.GenerateProposerSlashingEvidence(slashing, schedule, headerData, schema, state.Data)
+	if err != nil {
+		return err
+	}
+	if found >= 0 {
+		if err := evidence.ProveInclusion(headerData, schema, body, found); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("Block %s does not include the slashing; the evidence proves its validity only", blockID)
+	}
+	if err := evidence.Verify(schedule, schema); err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(evidence, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(encoded))
+	return nil
+}
+
+// readProposerSlashing reads a slashing from a JSON file, accepting either
+// the slashing itself or an API response wrapping it in "data"
+func readProposerSlashing(path string) (beacon.ProposerSlashing, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return beacon.ProposerSlashing{}, err
+	}
+	defer f.Close()
+
+	var raw map[string]any
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return beacon.ProposerSlashing{}, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	if data, ok := raw["data"].(map[string]any); ok {
+		raw = data
+	}
+	return beacon.ParseProposerSlashing(raw)
+}
+
+// findProposerSlashing returns the index of the slashing among a block's
+// proposer_slashings, or -1
+func findProposerSlashing(included []any, slashing beacon.ProposerSlashing) (int, error) {
+	root, err := slashing.HashTreeRoot()
+	if err != nil {
+		return 0, err
+	}
+	for i, v := range included {
+		other, err := beacon.ParseProposerSlashing(v)
+		if err != nil {
+			return 0, fmt.Errorf("proposer_slashings[%d]: %w", i, err)
+		}
+		otherRoot, err := other.HashTreeRoot()
+		if err != nil {
+			return 0, fmt.Errorf("proposer_slashings[%d]: %w", i, err)
+		}
+		if otherRoot == root {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
@@ -3,6 +3,28 @@ package config
 
 import (
 	"flag"
+	"fmt"
+	"strings"
+)
+
+// Verification modes
+const (
+	// ModeHeader proves header fields and paths of a block inside the EIP-4788 buffer
+	ModeHeader = "header"
+	// ModeHistorical proves an old block root through historical_summaries
+	ModeHistorical = "historical"
+	// ModeAncestor proves a block within 8192 slots through the anchor state's block_roots
+	ModeAncestor = "ancestor"
+	// ModeSkipped proves that a slot was empty through two block_roots entries
+	ModeSkipped = "skipped"
+	// ModeChain links an older header to the anchor through parent_root
+	ModeChain = "chain"
+	// ModeLightClient takes the header to verify from a light client synced
+	// from a trusted checkpoint instead of the headers endpoint
+	ModeLightClient = "lightclient"
+	// ModeFollow subscribes to the node's event stream and verifies the
+	// parent of every new head until interrupted
+	ModeFollow = "follow"
 )
 
 // Config represents the application configuration
@@ -10,27 +32,67 @@ type Config struct {
 	BeaconAPI    BeaconAPIConfig    `json:"beacon_api"`
 	Verification VerificationConfig `json:"verification"`
 	EthereumNode EthereumNodeConfig `json:"ethereum_node"`
-	Slot         string             `json:"slot"`
+	// Slot identifies the block to verify in any form locator.Parse accepts
+	Slot string `json:"slot"`
+	Mode string `json:"mode"`
+	// AnchorSlot is the recent block that proofs of older blocks are chained
+	// to, in the same forms as Slot; it defaults to the block before the
+	// current head
+	AnchorSlot string `json:"anchor_slot"`
+	// Checkpoint is the trusted block root the light client bootstraps from
+	Checkpoint string `json:"checkpoint"`
+	// Optimistic makes the light client verify its latest attested header
+	// instead of the latest finalized one
+	Optimistic bool `json:"optimistic"`
 }
 
 // BeaconAPIConfig contains beacon chain API configuration
 type BeaconAPIConfig struct {
-	Endpoints        []string `json:"endpoints"`
-	RetryAttempts    int      `json:"retry_attempts"`
-	RequestTimeoutMs int      `json:"request_timeout_ms"`
+	Endpoints []string `json:"endpoints"`
+	// RetryAttempts bounds the attempts for a single request, counting the
+	// first one
+	RetryAttempts    int `json:"retry_attempts"`
+	RequestTimeoutMs int `json:"request_timeout_ms"`
+	// SlotSearchWindow is how many slots are searched for a filled one
+	// before giving up
+	SlotSearchWindow int `json:"slot_search_window"`
+	// ProbeIntervalMs is how often endpoints taken out of rotation are
+	// probed when several are configured
+	ProbeIntervalMs int `json:"probe_interval_ms"`
+	// Quorum is how many endpoints must return the same header before it is
+	// verified; zero trusts a single endpoint
+	Quorum int `json:"quorum"`
 }
 
-// VerificationConfig contains verification-related settings
+// VerificationConfig contains verification-related settings. FieldsToVerify
+// takes header field names or paths below the header such as
+// "body.execution_payload.block_number" or "state.validators[0].slashed".
 type VerificationConfig struct {
-	VerifierAddress      string   `json:"verifier_address"`
-	FieldsToVerify       []string `json:"fields_to_verify"`
-	MaxVerificationSlots int      `json:"max_verification_slots"`
+	VerifierAddress         string   `json:"verifier_address"`
+	FieldsToVerify          []string `json:"fields_to_verify"`
+	MaxVerificationSlots    int      `json:"max_verification_slots"`
+	VerifyExecutionRequests bool     `json:"verify_execution_requests"`
+	ChainDepth              int      `json:"chain_depth"`
+	ProveTimestamp          bool     `json:"prove_timestamp"`
+	VerifySignature         bool     `json:"verify_signature"`
 }
 
 // EthereumNodeConfig contains Ethereum node configuration
 type EthereumNodeConfig struct {
 	Endpoint string `json:"endpoint"`
-	ChainID  int    `json:"chain_id"`
+	// ChainID selects the built-in fork schedule. It is replaced by the chain
+	// the beacon node turns out to follow unless it was given with -chain, in
+	// which case a node on another chain is an error.
+	ChainID int `json:"chain_id"`
+
+	// chainIDSet records that ChainID was given with -chain
+	chainIDSet bool
+}
+
+// ChainIDSet reports whether ChainID was given explicitly rather than left at
+// its default
+func (c EthereumNodeConfig) ChainIDSet() bool {
+	return c.chainIDSet
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -40,8 +102,10 @@ func DefaultConfig() *Config {
 			Endpoints: []string{
 				"https://responsive-spring-glitter.ethereum-holesky.quiknode.pro/cb7d99ff3abcf116b14f8255e084e0a0121ad9e1",
 			},
-			RetryAttempts:    5,
+			RetryAttempts:    4,
 			RequestTimeoutMs: 5000,
+			SlotSearchWindow: 5,
+			ProbeIntervalMs:  30000,
 		},
 		Verification: VerificationConfig{
 			VerifierAddress: "0x4D581D208fe2645A97Bee8344c5073c6729a715b",
@@ -53,11 +117,13 @@ func DefaultConfig() *Config {
 				"body_root",
 			},
 			MaxVerificationSlots: 5,
+			ProveTimestamp:       true,
 		},
 		EthereumNode: EthereumNodeConfig{
 			Endpoint: "",    // Default to using the same endpoint as Beacon API
 			ChainID:  17000, // Holesky testnet
 		},
+		Mode: ModeHeader,
 	}
 }
 
@@ -66,16 +132,29 @@ func LoadConfig() (*Config, error) {
 	config := DefaultConfig()
 
 	// Define command line flags
-	beaconEndpoint := flag.String("beacon", "", "Beacon chain API endpoint")
+	beaconEndpoint := flag.String("beacon", "", "Comma-separated beacon chain API endpoints; requests go to the healthiest")
 	verifierAddr := flag.String("verifier", "", "Beacon header verifier contract address")
 	ethEndpoint := flag.String("eth", "", "Ethereum node endpoint")
-	maxRetries := flag.Int("retries", 0, "Maximum number of retry attempts")
-	slotToVerify := flag.String("slot", "", "Specific slot to verify (defaults to auto-detecting a recent slot)")
+	chainID := flag.Int("chain", config.EthereumNode.ChainID, "Chain ID the beacon node must follow (detected from the node when not given)")
+	maxRetries := flag.Int("retries", 0, "Maximum attempts per beacon API request, with exponential backoff")
+	quorum := flag.Int("quorum", 0, "Number of beacon endpoints that must agree on each verified header (0 trusts one endpoint)")
+	slotWindow := flag.Int("slot-window", 0, "Number of slots to search for a filled one when skipping empty slots")
+	requestTimeout := flag.Int("timeout", 0, "Beacon API request timeout in milliseconds")
+	slotToVerify := flag.String("slot", "", "Block to verify: a slot, head, finalized, justified, genesis, a 0x root, an offset such as finalized-32, an RFC 3339 time or el:<block number> (defaults to a recent block)")
+	mode := flag.String("mode", "", "Verification mode: header, historical, ancestor, skipped, chain, lightclient or follow")
+	anchorSlot := flag.String("anchor", "", "Recent anchor block for historical, ancestor, skipped-slot and chain proofs, in any form -slot accepts (defaults to the block before head)")
+	proveTimestamp := flag.Bool("prove-timestamp", true, "Prove the next block's execution timestamp used as the EIP-4788 key")
+	chainDepth := flag.Int("depth", 0, "Number of blocks to walk back from the anchor in chain mode")
+	checkpoint := flag.String("checkpoint", "", "Trusted block root to bootstrap the light client from in lightclient mode")
+	optimistic := flag.Bool("optimistic", false, "Verify the light client's latest attested header instead of the finalized one")
+	fields := flag.String("fields", "", "Comma-separated header fields or paths to verify (e.g. slot,body.execution_payload.block_number)")
+	verifySignature := flag.Bool("verify-signature", false, "Verify the proposer's BLS signature over the header against a public key proven from its state")
+	executionRequests := flag.Bool("execution-requests", false, "Also prove every Electra execution request in the verified block")
 	flag.Parse()
 
 	// Override with command line parameters if provided
 	if *beaconEndpoint != "" {
-		config.BeaconAPI.Endpoints = []string{*beaconEndpoint}
+		config.BeaconAPI.Endpoints = strings.Split(*beaconEndpoint, ",")
 	}
 
 	if *verifierAddr != "" {
@@ -86,14 +165,65 @@ func LoadConfig() (*Config, error) {
 		config.EthereumNode.Endpoint = *ethEndpoint
 	}
 
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "chain" {
+			config.EthereumNode.ChainID = *chainID
+			config.EthereumNode.chainIDSet = true
+		}
+	})
+	if config.EthereumNode.ChainID <= 0 {
+		return nil, fmt.Errorf("invalid -chain %d", config.EthereumNode.ChainID)
+	}
+
 	if *maxRetries > 0 {
 		config.BeaconAPI.RetryAttempts = *maxRetries
 	}
 
+	if *quorum > 0 {
+		config.BeaconAPI.Quorum = *quorum
+	}
+
+	if *slotWindow > 0 {
+		config.BeaconAPI.SlotSearchWindow = *slotWindow
+	}
+
+	if *requestTimeout > 0 {
+		config.BeaconAPI.RequestTimeoutMs = *requestTimeout
+	}
+
 	if *slotToVerify != "" {
 		config.Slot = *slotToVerify
 	}
 
+	if *mode != "" {
+		config.Mode = *mode
+	}
+
+	if *anchorSlot != "" {
+		config.AnchorSlot = *anchorSlot
+	}
+
+	if *checkpoint != "" {
+		config.Checkpoint = *checkpoint
+	}
+
+	config.Optimistic = *optimistic
+
+	config.Verification.ProveTimestamp = *proveTimestamp
+	config.Verification.VerifySignature = *verifySignature
+
+	if *chainDepth > 0 {
+		config.Verification.ChainDepth = *chainDepth
+	}
+
+	if *fields != "" {
+		config.Verification.FieldsToVerify = strings.Split(*fields, ",")
+	}
+
+	if *executionRequests {
+		config.Verification.VerifyExecutionRequests = true
+	}
+
 	// If Ethereum endpoint not specified, use the first beacon API endpoint
 	if config.EthereumNode.Endpoint == "" && len(config.BeaconAPI.Endpoints) > 0 {
 		config.EthereumNode.Endpoint = config.BeaconAPI.Endpoints[0]
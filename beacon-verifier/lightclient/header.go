@@ -0,0 +1,177 @@
+// Package lightclient follows the chain with the light client sync protocol
+// of the consensus specs: bootstraps from a trusted block root, then checks
+// every header, sync committee and finalized checkpoint it learns from the
+// beacon API against Merkle branches instead of taking them on trust
+package lightclient
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Header is a light client header: a beacon block header together with the
+// execution payload header and its branch to body_root
+type Header struct {
+	Beacon          beacon.HeaderData
+	Execution       map[string]any
+	ExecutionBranch [][32]byte
+}
+
+// Slot returns the slot of the beacon block header
+func (h Header) Slot() uint64 {
+	slot, _ := strconv.ParseUint(h.Beacon.Slot, 10, 64)
+	return slot
+}
+
+// Root returns the beacon block root of the header
+func (h Header) Root() ([32]byte, error) {
+	return h.Beacon.HashTreeRoot()
+}
+
+// Verify checks the execution payload header against body_root
+func (h Header) Verify(schema *beacon.Schema) error {
+	gindex, err := schema.BeaconBlockBody.GeneralizedIndex("execution_payload")
+	if err != nil {
+		return err
+	}
+	leaf, err := ssz.HashTreeRoot(schema.ExecutionPayloadHeader, h.Execution)
+	if err != nil {
+		return fmt.Errorf("error hashing execution header: %w", err)
+	}
+	bodyRoot, err := decodeRoot(h.Beacon.BodyRoot)
+	if err != nil {
+		return fmt.Errorf("invalid body_root: %w", err)
+	}
+	if err := verifyBranch(leaf, h.ExecutionBranch, gindex, bodyRoot); err != nil {
+		return fmt.Errorf("execution branch of slot %s: %w", h.Beacon.Slot, err)
+	}
+	return nil
+}
+
+// IsEmpty reports whether every field of the header, its execution header
+// and execution branch included, is zero. The finalized header of an update
+// takes this form before the first finalization.
+func (h Header) IsEmpty(schema *beacon.Schema) (bool, error) {
+	if h.Beacon.Slot != "0" || h.Beacon.ProposerIndex != "0" {
+		return false, nil
+	}
+	for _, s := range []string{h.Beacon.ParentRoot, h.Beacon.StateRoot, h.Beacon.BodyRoot} {
+		if root, err := decodeRoot(s); err != nil || root != ([32]byte{}) {
+			return false, nil
+		}
+	}
+	for _, node := range h.ExecutionBranch {
+		if node != ([32]byte{}) {
+			return false, nil
+		}
+	}
+	execution, err := ssz.HashTreeRoot(schema.ExecutionPayloadHeader, h.Execution)
+	if err != nil {
+		return false, fmt.Errorf("error hashing execution header: %w", err)
+	}
+	empty, err := ssz.HashTreeRoot(schema.ExecutionPayloadHeader, ssz.Zero(schema.ExecutionPayloadHeader))
+	if err != nil {
+		return false, err
+	}
+	return execution == empty, nil
+}
+
+// Verified returns the beacon header with its block root and the execution
+// timestamp proven by the execution branch filled in, ready for the proof
+// pipeline. Call it only after Verify.
+func (h Header) Verified() (beacon.HeaderData, error) {
+	headerData := h.Beacon
+	root, err := h.Root()
+	if err != nil {
+		return beacon.HeaderData{}, err
+	}
+	headerData.BlockRoot = "0x" + hex.EncodeToString(root[:])
+	timestamp, err := strconv.ParseInt(fmt.Sprint(h.Execution["timestamp"]), 10, 64)
+	if err != nil {
+		return beacon.HeaderData{}, fmt.Errorf("error parsing execution timestamp: %w", err)
+	}
+	headerData.Timestamp = timestamp
+	return headerData, nil
+}
+
+// parseHeader reads a light client header from its JSON shape
+func parseHeader(v any) (Header, error) {
+	fields, ok := v.(map[string]any)
+	if !ok {
+		return Header{}, fmt.Errorf("light client header expects a JSON object, got %T", v)
+	}
+	beaconFields, ok := fields["beacon"].(map[string]any)
+	if !ok {
+		return Header{}, fmt.Errorf("light client header has no beacon header")
+	}
+	execution, ok := fields["execution"].(map[string]any)
+	if !ok {
+		return Header{}, fmt.Errorf("light client header has no execution header")
+	}
+	branch, err := parseBranch(fields["execution_branch"])
+	if err != nil {
+		return Header{}, fmt.Errorf("execution_branch: %w", err)
+	}
+
+	field := func(name string) string { return fmt.Sprint(beaconFields[name]) }
+	return Header{
+		Beacon: beacon.HeaderData{
+			Slot:          field("slot"),
+			ProposerIndex: field("proposer_index"),
+			ParentRoot:    field("parent_root"),
+			StateRoot:     field("state_root"),
+			BodyRoot:      field("body_root"),
+		},
+		Execution:       execution,
+		ExecutionBranch: branch,
+	}, nil
+}
+
+// parseBranch reads a Merkle branch from a JSON array of hex roots
+func parseBranch(v any) ([][32]byte, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON array, got %T", v)
+	}
+	branch := make([][32]byte, len(items))
+	for i, item := range items {
+		s, _ := item.(string)
+		root, err := decodeRoot(s)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		branch[i] = root
+	}
+	return branch, nil
+}
+
+// decodeRoot decodes a 0x-prefixed 32-byte root
+func decodeRoot(s string) ([32]byte, error) {
+	var root [32]byte
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return root, err
+	}
+	if len(b) != 32 {
+		return root, fmt.Errorf("root has %d bytes, expected 32", len(b))
+	}
+	copy(root[:], b)
+	return root, nil
+}
+
+// verifyBranch checks a branch whose length must match the depth of gindex
+func verifyBranch(leaf [32]byte, branch [][32]byte, gindex uint64, root [32]byte) error {
+	if depth := merkle.GeneralizedIndexDepth(gindex); len(branch) != depth {
+		return fmt.Errorf("branch has %d nodes, generalized index %d needs %d", len(branch), gindex, depth)
+	}
+	if !merkle.VerifyBranch(leaf, branch, gindex, root) {
+		return fmt.Errorf("branch does not lead to root 0x%x", root)
+	}
+	return nil
+}
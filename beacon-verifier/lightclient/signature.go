@@ -0,0 +1,81 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/bls"
+)
+
+// VerifySignature checks the sync aggregate signature of the update: the
+// participating members of committee must have signed the attested header
+// root under the sync committee domain of the slot before the signature slot
+func (u Update) VerifySignature(committee map[string]any, schedule beacon.ForkSchedule) error {
+	participation, err := u.participation()
+	if err != nil {
+		return err
+	}
+	pubkeys, ok := committee["pubkeys"].([]any)
+	if !ok || len(pubkeys) != beacon.SyncCommitteeSize {
+		return fmt.Errorf("sync committee does not have %d public keys", beacon.SyncCommitteeSize)
+	}
+
+	var signers []*bls.PublicKey
+	for i, signed := range participation {
+		if !signed {
+			continue
+		}
+		pubkey, err := decodePubkey(pubkeys[i])
+		if err != nil {
+			return fmt.Errorf("sync committee member %d: %w", i, err)
+		}
+		signers = append(signers, pubkey)
+	}
+
+	signingRoot, err := u.signingRoot(schedule)
+	if err != nil {
+		return err
+	}
+	signatureHex, _ := u.SyncAggregate["sync_committee_signature"].(string)
+	signature, err := hex.DecodeString(strings.TrimPrefix(signatureHex, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid sync_committee_signature: %w", err)
+	}
+	if err := bls.FastAggregateVerify(signers, signingRoot[:], signature); err != nil {
+		return fmt.Errorf("sync aggregate of %d members over slot %d: %w", len(signers), u.AttestedHeader.Slot(), err)
+	}
+	return nil
+}
+
+// signingRoot returns the root the sync committee signed. The fork version
+// is that of the slot before the signature slot, the slot the committee
+// members attested in.
+func (u Update) signingRoot(schedule beacon.ForkSchedule) ([32]byte, error) {
+	if schedule.SlotsPerEpoch == 0 {
+		return [32]byte{}, fmt.Errorf("fork schedule has no slots per epoch")
+	}
+	forkSlot := max(u.SignatureSlot, 1) - 1
+	domain, err := schedule.DomainAtEpoch(beacon.DomainSyncCommittee, forkSlot/schedule.SlotsPerEpoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	root, err := u.AttestedHeader.Root()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return beacon.ComputeSigningRoot(root, domain)
+}
+
+func decodePubkey(v any) (*bls.PublicKey, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("public key is %T, expected a hex string", v)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return bls.ParsePublicKey(b)
+}
@@ -0,0 +1,173 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/bls"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
+)
+
+// testSchedule is a single-fork chain, so every slot has a signing domain
+var testSchedule = beacon.NewForkSchedule(beacon.SlotsPerEpoch,
+	beacon.ForkEpoch{Name: beacon.Deneb, Epoch: 0, Version: [4]byte{0x04, 0x00, 0x00, 0x00}},
+).WithGenesisValidatorsRoot("0x" + strings.Repeat("5a", 32))
+
+// testClock times the test chain with mainnet values
+var testClock = &chaintime.Clock{
+	SecondsPerSlot:               beacon.SecondsPerSlot,
+	SlotsPerEpoch:                beacon.SlotsPerEpoch,
+	EpochsPerSyncCommitteePeriod: beacon.EpochsPerSyncCommitteePeriod,
+	Forks:                        testSchedule,
+}
+
+// signingCommittee is a sync committee with known secret keys. Member i has
+// the secret seed*1000+i+1, so the aggregate secret of any subset is a sum.
+type signingCommittee struct {
+	committee map[string]any
+	secrets   []int64
+}
+
+func newSigningCommittee(t *testing.T, seed int64) signingCommittee {
+	t.Helper()
+	c := signingCommittee{secrets: make([]int64, beacon.SyncCommitteeSize)}
+	pubkeys := make([]any, beacon.SyncCommitteeSize)
+	for i := range pubkeys {
+		c.secrets[i] = seed*1000 + int64(i) + 1
+		pubkeys[i] = "0x" + hex.EncodeToString(secretKey(t, c.secrets[i]).PublicKey().Bytes())
+	}
+	c.committee = map[string]any{"pubkeys": pubkeys, "aggregate_pubkey": pubkeys[0]}
+	return c
+}
+
+func secretKey(t *testing.T, secret int64) *bls.SecretKey {
+	t.Helper()
+	sk, err := bls.NewSecretKey(big.NewInt(secret).Bytes())
+	if err != nil {
+		t.Fatalf("NewSecretKey() error = %v", err)
+	}
+	return sk
+}
+
+// sign sets the sync aggregate signature of the participating members
+func (c signingCommittee) sign(t *testing.T, u *Update) {
+	t.Helper()
+	participation, err := u.participation()
+	if err != nil {
+		t.Fatalf("participation() error = %v", err)
+	}
+	var sum int64
+	for i, signed := range participation {
+		if signed {
+			sum += c.secrets[i]
+		}
+	}
+	signingRoot, err := u.signingRoot(testSchedule)
+	if err != nil {
+		t.Fatalf("signingRoot() error = %v", err)
+	}
+	signature, err := secretKey(t, sum).Sign(signingRoot[:])
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	u.SyncAggregate["sync_committee_signature"] = "0x" + hex.EncodeToString(signature)
+}
+
+func TestUpdateVerifySignature(t *testing.T) {
+	signers := newSigningCommittee(t, 1)
+	update := setupUpdate(t, 10*periodSlots+200, 10*periodSlots+128, signers.committee, testCommittee(2))
+	// Member 0 and 9 abstain, checking the bit order within a byte
+	update.SyncAggregate["sync_committee_bits"] = "0xfefd" + strings.Repeat("ff", beacon.SyncCommitteeSize/8-2)
+	signers.sign(t, &update)
+
+	if err := update.VerifySignature(signers.committee, testSchedule); err != nil {
+		t.Fatalf("VerifySignature() error = %v", err)
+	}
+
+	// A fork at the epoch after the signature slot changes only its domain
+	forked := beacon.NewForkSchedule(beacon.SlotsPerEpoch, append(testSchedule.Forks,
+		beacon.ForkEpoch{Name: beacon.Electra, Epoch: update.SignatureSlot/beacon.SlotsPerEpoch + 1,
+			Version: [4]byte{0x05, 0x00, 0x00, 0x00}})...)
+	forked.GenesisValidatorsRoot = testSchedule.GenesisValidatorsRoot
+
+	tests := []struct {
+		name     string
+		schedule beacon.ForkSchedule
+		tamper   func(u *Update) map[string]any
+	}{
+		{"Other committee", testSchedule, func(u *Update) map[string]any {
+			return newSigningCommittee(t, 2).committee
+		}},
+		{"Bits", testSchedule, func(u *Update) map[string]any {
+			u.SyncAggregate["sync_committee_bits"] = "0xff" + strings.Repeat("ff", beacon.SyncCommitteeSize/8-1)
+			return signers.committee
+		}},
+		{"Attested header", testSchedule, func(u *Update) map[string]any {
+			u.AttestedHeader.Beacon.ProposerIndex = "8"
+			return signers.committee
+		}},
+		{"Signature slot in another fork", forked, func(u *Update) map[string]any {
+			u.SignatureSlot = (u.SignatureSlot/beacon.SlotsPerEpoch+1)*beacon.SlotsPerEpoch + 1
+			return signers.committee
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := update
+			tampered.SyncAggregate = map[string]any{}
+			for k, v := range update.SyncAggregate {
+				tampered.SyncAggregate[k] = v
+			}
+			committee := tt.tamper(&tampered)
+			if err := tampered.VerifySignature(committee, tt.schedule); !errors.Is(err, bls.ErrInvalidSignature) {
+				t.Errorf("VerifySignature() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	t.Run("Invalid public key", func(t *testing.T) {
+		if err := update.VerifySignature(testCommittee(1), testSchedule); err == nil || errors.Is(err, bls.ErrInvalidSignature) {
+			t.Errorf("VerifySignature() error = %v, want a public key error", err)
+		}
+	})
+}
+
+func TestStoreVerifiesSignatures(t *testing.T) {
+	current, next := newSigningCommittee(t, 1), newSigningCommittee(t, 2)
+	bootstrap := setupBootstrap(t, 10*periodSlots+64, current.committee)
+	store, err := NewStore(headerRoot(t, bootstrap.Header), bootstrap, testClock)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	// An update signed by the wrong committee is rejected outright
+	forged := setupUpdate(t, 10*periodSlots+200, 10*periodSlots+128, current.committee, next.committee)
+	next.sign(t, &forged)
+	if err := store.Apply(forged); !errors.Is(err, bls.ErrInvalidSignature) {
+		t.Fatalf("Apply() forged update error = %v, want ErrInvalidSignature", err)
+	}
+	if store.OptimisticHeader.Slot() != 10*periodSlots+64 {
+		t.Errorf("forged update moved the optimistic header to slot %d", store.OptimisticHeader.Slot())
+	}
+
+	update := setupUpdate(t, 10*periodSlots+200, 10*periodSlots+128, current.committee, next.committee)
+	current.sign(t, &update)
+	if err := store.Apply(update); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// After the period boundary the next committee signs
+	finality := setupUpdate(t, 11*periodSlots+100, 11*periodSlots+32, next.committee, testCommittee(3))
+	finality.NextSyncCommittee, finality.NextSyncCommitteeBranch = nil, nil
+	next.sign(t, &finality)
+	if err := store.Apply(finality); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if store.Period() != 11 {
+		t.Errorf("Period() = %d, want 11", store.Period())
+	}
+}
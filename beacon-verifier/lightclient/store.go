@@ -0,0 +1,146 @@
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Store is the state of a light client: the latest finalized and optimistic
+// headers and the sync committees of the current and next period. It applies
+// updates following process_light_client_update of the consensus specs,
+// without the best-valid-update bookkeeping used to recover from stalls.
+type Store struct {
+	FinalizedHeader      Header
+	OptimisticHeader     Header
+	CurrentSyncCommittee map[string]any
+	// NextSyncCommittee is nil until an update for the current period
+	// reveals it
+	NextSyncCommittee map[string]any
+
+	// clock provides the sync committee periods of slots and, through its
+	// fork schedule, the signing domains of sync aggregate signatures
+	clock *chaintime.Clock
+}
+
+// NewStore initializes a store from a bootstrap for a trusted block root.
+// Sync aggregate signatures are verified against the clock's fork schedule,
+// which is required: a light client that skips them trusts the API
+// completely.
+func NewStore(trustedRoot [32]byte, bootstrap Bootstrap, clock *chaintime.Clock) (*Store, error) {
+	if clock == nil || len(clock.Forks.Forks) == 0 {
+		return nil, errors.New("a fork schedule is needed to verify sync aggregate signatures")
+	}
+	if err := bootstrap.Verify(trustedRoot); err != nil {
+		return nil, err
+	}
+	return &Store{
+		FinalizedHeader:      bootstrap.Header,
+		OptimisticHeader:     bootstrap.Header,
+		CurrentSyncCommittee: bootstrap.CurrentSyncCommittee,
+		clock:                clock,
+	}, nil
+}
+
+// Period returns the sync committee period of the finalized header
+func (s *Store) Period() uint64 {
+	return s.clock.SyncPeriodOf(s.FinalizedHeader.Slot())
+}
+
+// Apply verifies an update against the store, including its signature, and
+// advances the optimistic header, the finalized header and the sync
+// committees it proves
+func (s *Store) Apply(u Update) error {
+	if err := u.Verify(); err != nil {
+		return err
+	}
+
+	storePeriod := s.Period()
+	signaturePeriod := s.clock.SyncPeriodOf(u.SignatureSlot)
+	if s.NextSyncCommittee != nil {
+		if signaturePeriod != storePeriod && signaturePeriod != storePeriod+1 {
+			return fmt.Errorf("update signed in period %d, store is at period %d", signaturePeriod, storePeriod)
+		}
+	} else if signaturePeriod != storePeriod {
+		return fmt.Errorf("update signed in period %d, store is at period %d and does not know the next sync committee",
+			signaturePeriod, storePeriod)
+	}
+
+	attestedSlot := u.AttestedHeader.Slot()
+	attestedPeriod := s.clock.SyncPeriodOf(attestedSlot)
+	learnsCommittee := u.NextSyncCommittee != nil && attestedPeriod == storePeriod && s.NextSyncCommittee == nil
+	if attestedSlot <= s.FinalizedHeader.Slot() && !learnsCommittee {
+		return fmt.Errorf("update attests slot %d, not newer than the finalized slot %d", attestedSlot, s.FinalizedHeader.Slot())
+	}
+	if u.NextSyncCommittee != nil && attestedPeriod == storePeriod && s.NextSyncCommittee != nil {
+		if err := sameCommittee(u.NextSyncCommittee, s.NextSyncCommittee); err != nil {
+			return fmt.Errorf("update contradicts the known next sync committee: %w", err)
+		}
+	}
+
+	committee := s.CurrentSyncCommittee
+	if signaturePeriod != storePeriod {
+		committee = s.NextSyncCommittee
+	}
+	if err := u.VerifySignature(committee, s.clock.Forks); err != nil {
+		return err
+	}
+
+	participants, err := u.Participants()
+	if err != nil {
+		return err
+	}
+	if attestedSlot > s.OptimisticHeader.Slot() {
+		s.OptimisticHeader = u.AttestedHeader
+	}
+
+	// Finality and committee changes need a two-thirds supermajority
+	if participants*3 < beacon.SyncCommitteeSize*2 {
+		return nil
+	}
+	// A committee is only learned from an update that also finalizes a
+	// header of the same period
+	if learnsCommittee && u.FinalizedHeader != nil && s.clock.SyncPeriodOf(u.FinalizedHeader.Slot()) == storePeriod {
+		s.NextSyncCommittee = u.NextSyncCommittee
+	}
+	if u.FinalizedHeader == nil || u.FinalizedHeader.Slot() <= s.FinalizedHeader.Slot() {
+		return nil
+	}
+
+	finalizedPeriod := s.clock.SyncPeriodOf(u.FinalizedHeader.Slot())
+	switch {
+	case finalizedPeriod == storePeriod:
+	case finalizedPeriod == storePeriod+1 && s.NextSyncCommittee != nil:
+		s.CurrentSyncCommittee = s.NextSyncCommittee
+		s.NextSyncCommittee = nil
+		if u.NextSyncCommittee != nil && attestedPeriod == finalizedPeriod {
+			s.NextSyncCommittee = u.NextSyncCommittee
+		}
+	default:
+		return fmt.Errorf("update finalizes period %d, store is at period %d", finalizedPeriod, storePeriod)
+	}
+	s.FinalizedHeader = *u.FinalizedHeader
+	if s.FinalizedHeader.Slot() > s.OptimisticHeader.Slot() {
+		s.OptimisticHeader = s.FinalizedHeader
+	}
+	return nil
+}
+
+// sameCommittee compares two sync committees by root
+func sameCommittee(a, b map[string]any) error {
+	rootA, err := ssz.HashTreeRoot(beacon.SyncCommitteeType, a)
+	if err != nil {
+		return err
+	}
+	rootB, err := ssz.HashTreeRoot(beacon.SyncCommitteeType, b)
+	if err != nil {
+		return err
+	}
+	if rootA != rootB {
+		return fmt.Errorf("sync committee root 0x%x, expected 0x%x", rootA, rootB)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package lightclient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
+)
+
+// testSigners caches signing committees by seed, since each one derives a
+// key per member
+var testSigners = map[int64]signingCommittee{}
+
+func signers(t *testing.T, seed int64) signingCommittee {
+	t.Helper()
+	c, ok := testSigners[seed]
+	if !ok {
+		c = newSigningCommittee(t, seed)
+		testSigners[seed] = c
+	}
+	return c
+}
+
+// signedUpdate returns an update signed by the committee of seed current
+// that reveals the committee of seed next
+func signedUpdate(t *testing.T, attestedSlot, finalizedSlot uint64, current, next int64) Update {
+	t.Helper()
+	u := setupUpdate(t, attestedSlot, finalizedSlot, signers(t, current).committee, signers(t, next).committee)
+	signers(t, current).sign(t, &u)
+	return u
+}
+
+func setupStore(t *testing.T) *Store {
+	t.Helper()
+	bootstrap := setupBootstrap(t, 10*periodSlots+64, signers(t, 1).committee)
+	store, err := NewStore(headerRoot(t, bootstrap.Header), bootstrap, testClock)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	return store
+}
+
+func TestNewStoreNeedsSchedule(t *testing.T) {
+	bootstrap := setupBootstrap(t, 10*periodSlots+64, signers(t, 1).committee)
+	if _, err := NewStore(headerRoot(t, bootstrap.Header), bootstrap, nil); err == nil {
+		t.Error("NewStore() without a fork schedule expected error, got nil")
+	}
+	if _, err := NewStore(headerRoot(t, bootstrap.Header), bootstrap, &chaintime.Clock{}); err == nil {
+		t.Error("NewStore() with an empty fork schedule expected error, got nil")
+	}
+}
+
+func TestStorePeriodFollowsClock(t *testing.T) {
+	// A chain with 8-epoch sync committee periods, as in the minimal preset
+	clock := *testClock
+	clock.EpochsPerSyncCommitteePeriod = 8
+	bootstrap := setupBootstrap(t, 10*periodSlots+64, signers(t, 1).committee)
+	store, err := NewStore(headerRoot(t, bootstrap.Header), bootstrap, &clock)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if want := uint64(10*periodSlots+64) / (8 * beacon.SlotsPerEpoch); store.Period() != want {
+		t.Errorf("Period() = %d, want %d", store.Period(), want)
+	}
+}
+
+func TestStoreFollowsPeriods(t *testing.T) {
+	store := setupStore(t)
+	if store.Period() != 10 {
+		t.Fatalf("Period() = %d, want 10", store.Period())
+	}
+
+	// An update of the current period reveals the next committee
+	update := signedUpdate(t, 10*periodSlots+200, 10*periodSlots+128, 1, 2)
+	if err := store.Apply(update); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if store.FinalizedHeader.Slot() != 10*periodSlots+128 || store.NextSyncCommittee == nil {
+		t.Fatalf("store did not finalize slot %d and learn the next committee", 10*periodSlots+128)
+	}
+
+	// A finality update signed in the next period rotates the committees
+	finality := signedUpdate(t, 11*periodSlots+100, 11*periodSlots+32, 2, 3)
+	finality.NextSyncCommittee, finality.NextSyncCommitteeBranch = nil, nil
+	if err := store.Apply(finality); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if store.Period() != 11 {
+		t.Errorf("Period() = %d, want 11", store.Period())
+	}
+	if err := sameCommittee(store.CurrentSyncCommittee, signers(t, 2).committee); err != nil {
+		t.Errorf("current sync committee not rotated: %v", err)
+	}
+	if store.NextSyncCommittee != nil {
+		t.Error("next sync committee should be unknown after rotating")
+	}
+	if store.OptimisticHeader.Slot() != 11*periodSlots+100 {
+		t.Errorf("OptimisticHeader.Slot() = %d, want %d", store.OptimisticHeader.Slot(), 11*periodSlots+100)
+	}
+}
+
+func TestStoreOptimisticUpdate(t *testing.T) {
+	store := setupStore(t)
+
+	// Without a supermajority only the optimistic header moves
+	optimistic := signedUpdate(t, 10*periodSlots+300, 10*periodSlots+256, 1, 2)
+	optimistic.SyncAggregate["sync_committee_bits"] = "0x" + strings.Repeat("0f", beacon.SyncCommitteeSize/8)
+	signers(t, 1).sign(t, &optimistic)
+	if err := store.Apply(optimistic); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if store.OptimisticHeader.Slot() != 10*periodSlots+300 {
+		t.Errorf("OptimisticHeader.Slot() = %d, want %d", store.OptimisticHeader.Slot(), 10*periodSlots+300)
+	}
+	if store.FinalizedHeader.Slot() != 10*periodSlots+64 || store.NextSyncCommittee != nil {
+		t.Error("update without a supermajority changed the finalized header or committees")
+	}
+}
+
+func TestStoreRejectsUpdates(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(t *testing.T) Update
+	}{
+		{"Stale", func(t *testing.T) Update {
+			u := signedUpdate(t, 10*periodSlots+32, 10*periodSlots, 1, 2)
+			u.NextSyncCommittee, u.NextSyncCommitteeBranch = nil, nil
+			return u
+		}},
+		{"Next period without next committee", func(t *testing.T) Update {
+			return signedUpdate(t, 11*periodSlots+100, 11*periodSlots+32, 2, 3)
+		}},
+		{"Period gap", func(t *testing.T) Update {
+			return signedUpdate(t, 12*periodSlots+100, 12*periodSlots+32, 2, 3)
+		}},
+		{"Invalid branch", func(t *testing.T) Update {
+			u := signedUpdate(t, 10*periodSlots+200, 10*periodSlots+128, 1, 2)
+			u.NextSyncCommittee = testCommittee(4)
+			return u
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := setupStore(t)
+			if err := store.Apply(tt.update(t)); err == nil {
+				t.Error("Apply() expected error, got nil")
+			}
+		})
+	}
+
+	t.Run("Contradicting next committee", func(t *testing.T) {
+		store := setupStore(t)
+		if err := store.Apply(signedUpdate(t, 10*periodSlots+200, 10*periodSlots+128, 1, 2)); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if err := store.Apply(signedUpdate(t, 10*periodSlots+400, 10*periodSlots+320, 1, 5)); err == nil {
+			t.Error("Apply() expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Bootstrap is the sync committee of the period of a trusted header, with
+// its branch to the header's state_root
+type Bootstrap struct {
+	Schema                     *beacon.Schema
+	Header                     Header
+	CurrentSyncCommittee       map[string]any
+	CurrentSyncCommitteeBranch [][32]byte
+}
+
+// Update is a LightClientUpdate or one of its finality and optimistic
+// subsets. Fields that the subset does not carry are left empty.
+type Update struct {
+	Schema                  *beacon.Schema
+	AttestedHeader          Header
+	NextSyncCommittee       map[string]any
+	NextSyncCommitteeBranch [][32]byte
+	FinalizedHeader         *Header
+	FinalityBranch          [][32]byte
+	SyncAggregate           map[string]any
+	SignatureSlot           uint64
+}
+
+// ParseBootstrap reads a bootstrap served by the beacon API
+func ParseBootstrap(data *beacon.LightClientData) (Bootstrap, error) {
+	schema, err := beacon.SchemaFor(data.Version)
+	if err != nil {
+		return Bootstrap{}, err
+	}
+	header, err := parseHeader(data.Data["header"])
+	if err != nil {
+		return Bootstrap{}, err
+	}
+	committee, ok := data.Data["current_sync_committee"].(map[string]any)
+	if !ok {
+		return Bootstrap{}, fmt.Errorf("bootstrap has no current_sync_committee")
+	}
+	branch, err := parseBranch(data.Data["current_sync_committee_branch"])
+	if err != nil {
+		return Bootstrap{}, fmt.Errorf("current_sync_committee_branch: %w", err)
+	}
+	return Bootstrap{
+		Schema:                     schema,
+		Header:                     header,
+		CurrentSyncCommittee:       committee,
+		CurrentSyncCommitteeBranch: branch,
+	}, nil
+}
+
+// Verify checks that the bootstrap header has the trusted root and that the
+// sync committee and execution header are committed to by it
+func (b Bootstrap) Verify(trustedRoot [32]byte) error {
+	root, err := b.Header.Root()
+	if err != nil {
+		return err
+	}
+	if root != trustedRoot {
+		return fmt.Errorf("bootstrap header has root 0x%x, expected the trusted 0x%x", root, trustedRoot)
+	}
+	if err := b.Header.Verify(b.Schema); err != nil {
+		return err
+	}
+	if err := verifyStateBranch(b.Schema, b.Header, "current_sync_committee", beacon.SyncCommitteeType,
+		b.CurrentSyncCommittee, b.CurrentSyncCommitteeBranch); err != nil {
+		return fmt.Errorf("current sync committee: %w", err)
+	}
+	return nil
+}
+
+// ParseUpdate reads a full, finality or optimistic update served by the
+// beacon API
+func ParseUpdate(data *beacon.LightClientData) (Update, error) {
+	schema, err := beacon.SchemaFor(data.Version)
+	if err != nil {
+		return Update{}, err
+	}
+	attested, err := parseHeader(data.Data["attested_header"])
+	if err != nil {
+		return Update{}, fmt.Errorf("attested_header: %w", err)
+	}
+	aggregate, ok := data.Data["sync_aggregate"].(map[string]any)
+	if !ok {
+		return Update{}, fmt.Errorf("update has no sync_aggregate")
+	}
+	signatureSlot, err := strconv.ParseUint(fmt.Sprint(data.Data["signature_slot"]), 10, 64)
+	if err != nil {
+		return Update{}, fmt.Errorf("error parsing signature_slot: %w", err)
+	}
+	update := Update{
+		Schema:         schema,
+		AttestedHeader: attested,
+		SyncAggregate:  aggregate,
+		SignatureSlot:  signatureSlot,
+	}
+
+	if committee, ok := data.Data["next_sync_committee"].(map[string]any); ok {
+		update.NextSyncCommittee = committee
+		if update.NextSyncCommitteeBranch, err = parseBranch(data.Data["next_sync_committee_branch"]); err != nil {
+			return Update{}, fmt.Errorf("next_sync_committee_branch: %w", err)
+		}
+	}
+	if finalized, ok := data.Data["finalized_header"]; ok {
+		header, err := parseHeader(finalized)
+		if err != nil {
+			return Update{}, fmt.Errorf("finalized_header: %w", err)
+		}
+		update.FinalizedHeader = &header
+		if update.FinalityBranch, err = parseBranch(data.Data["finality_branch"]); err != nil {
+			return Update{}, fmt.Errorf("finality_branch: %w", err)
+		}
+	}
+	return update, nil
+}
+
+// Participants returns the number of sync committee members that signed
+func (u Update) Participants() (int, error) {
+	participation, err := u.participation()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, signed := range participation {
+		if signed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// participation decodes the sync committee bits, least significant bit of
+// each byte first
+func (u Update) participation() ([]bool, error) {
+	bitsHex, ok := u.SyncAggregate["sync_committee_bits"].(string)
+	if !ok {
+		return nil, fmt.Errorf("sync aggregate has no sync_committee_bits")
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(bitsHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync_committee_bits: %w", err)
+	}
+	if len(raw) != beacon.SyncCommitteeSize/8 {
+		return nil, fmt.Errorf("sync_committee_bits has %d bytes, expected %d", len(raw), beacon.SyncCommitteeSize/8)
+	}
+	participation := make([]bool, beacon.SyncCommitteeSize)
+	for i := range participation {
+		participation[i] = raw[i/8]>>(i%8)&1 == 1
+	}
+	return participation, nil
+}
+
+// Verify checks the slots and participation of the update and every branch
+// it carries: the execution branches of both headers, the next sync
+// committee and the finalized header, each against the attested state_root.
+// The sync aggregate signature is checked by VerifySignature.
+func (u Update) Verify() error {
+	participants, err := u.Participants()
+	if err != nil {
+		return err
+	}
+	if participants < beacon.MinSyncCommitteeParticipants {
+		return fmt.Errorf("update has %d sync committee participants, need at least %d",
+			participants, beacon.MinSyncCommitteeParticipants)
+	}
+
+	attestedSlot := u.AttestedHeader.Slot()
+	if u.SignatureSlot <= attestedSlot {
+		return fmt.Errorf("signature slot %d is not after attested slot %d", u.SignatureSlot, attestedSlot)
+	}
+	if err := u.AttestedHeader.Verify(u.Schema); err != nil {
+		return fmt.Errorf("attested header: %w", err)
+	}
+
+	if u.FinalizedHeader != nil {
+		if err := u.verifyFinality(attestedSlot); err != nil {
+			return err
+		}
+	}
+
+	if u.NextSyncCommittee != nil {
+		if err := verifyStateBranch(u.Schema, u.AttestedHeader, "next_sync_committee", beacon.SyncCommitteeType,
+			u.NextSyncCommittee, u.NextSyncCommitteeBranch); err != nil {
+			return fmt.Errorf("next sync committee: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifyFinality checks the finalized header against the finalized
+// checkpoint in the attested state
+func (u Update) verifyFinality(attestedSlot uint64) error {
+	finalized := *u.FinalizedHeader
+	if finalized.Slot() > attestedSlot {
+		return fmt.Errorf("finalized slot %d is after attested slot %d", finalized.Slot(), attestedSlot)
+	}
+
+	// Before the first finalization the checkpoint root is zero and the
+	// finalized header is empty
+	var leaf [32]byte
+	if finalized.Slot() == 0 {
+		empty, err := finalized.IsEmpty(u.Schema)
+		if err != nil {
+			return fmt.Errorf("finalized header: %w", err)
+		}
+		if !empty {
+			return fmt.Errorf("finalized header at slot 0 is not empty")
+		}
+	} else {
+		var err error
+		if leaf, err = finalized.Root(); err != nil {
+			return err
+		}
+		if err := finalized.Verify(u.Schema); err != nil {
+			return fmt.Errorf("finalized header: %w", err)
+		}
+	}
+
+	gindex, err := u.Schema.BeaconState.GeneralizedIndex("finalized_checkpoint", "root")
+	if err != nil {
+		return err
+	}
+	stateRoot, err := decodeRoot(u.AttestedHeader.Beacon.StateRoot)
+	if err != nil {
+		return fmt.Errorf("invalid attested state_root: %w", err)
+	}
+	if err := verifyBranch(leaf, u.FinalityBranch, gindex, stateRoot); err != nil {
+		return fmt.Errorf("finality branch: %w", err)
+	}
+	return nil
+}
+
+// verifyStateBranch checks a value of type t at a top-level state field
+// against the state_root of header
+func verifyStateBranch(schema *beacon.Schema, header Header, field string, t *ssz.Type, value any, branch [][32]byte) error {
+	gindex, err := schema.BeaconState.GeneralizedIndex(field)
+	if err != nil {
+		return err
+	}
+	leaf, err := ssz.HashTreeRoot(t, value)
+	if err != nil {
+		return fmt.Errorf("error hashing %s: %w", field, err)
+	}
+	stateRoot, err := decodeRoot(header.Beacon.StateRoot)
+	if err != nil {
+		return fmt.Errorf("invalid state_root: %w", err)
+	}
+	return verifyBranch(leaf, branch, gindex, stateRoot)
+}
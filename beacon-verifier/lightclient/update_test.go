@@ -0,0 +1,344 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+const periodSlots = beacon.SlotsPerEpoch * beacon.EpochsPerSyncCommitteePeriod
+
+var testSchema, _ = beacon.SchemaFor(beacon.Deneb)
+
+// zeroFieldRoots returns the roots of the fields of a zero value, so test
+// states and bodies can be hashed by swapping in a few field roots
+var zeroFieldRoots = sync.OnceValue(func() map[*ssz.Type][][32]byte {
+	roots := make(map[*ssz.Type][][32]byte)
+	for _, typ := range []*ssz.Type{testSchema.BeaconState, testSchema.BeaconBlockBody} {
+		zero := ssz.Zero(typ).(map[string]any)
+		for _, f := range typ.Fields {
+			root, _ := ssz.HashTreeRoot(f.Type, zero[f.Name])
+			roots[typ] = append(roots[typ], root)
+		}
+	}
+	return roots
+})
+
+// containerWith returns the root of a zero container of type typ with some
+// field roots replaced, and the branch of one field
+func containerWith(t *testing.T, typ *ssz.Type, fields map[string][32]byte, prove string) ([32]byte, [][32]byte) {
+	t.Helper()
+	roots := append([][32]byte{}, zeroFieldRoots()[typ]...)
+	for name, root := range fields {
+		i, _ := typ.FieldIndex(name)
+		roots[i] = root
+	}
+	index, _ := typ.FieldIndex(prove)
+	root, branch, err := merkle.MerkleizeWithProof(roots, uint64(len(roots)), uint64(index))
+	if err != nil {
+		t.Fatalf("MerkleizeWithProof() error = %v", err)
+	}
+	return root, branch
+}
+
+func testCommittee(seed byte) map[string]any {
+	pubkey := "0x" + strings.Repeat(hex.EncodeToString([]byte{seed}), 48)
+	pubkeys := make([]any, beacon.SyncCommitteeSize)
+	for i := range pubkeys {
+		pubkeys[i] = pubkey
+	}
+	return map[string]any{"pubkeys": pubkeys, "aggregate_pubkey": pubkey}
+}
+
+func hashValue(t *testing.T, typ *ssz.Type, v any) [32]byte {
+	t.Helper()
+	root, err := ssz.HashTreeRoot(typ, v)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	return root
+}
+
+// testState is the attested state of a test update
+type testState struct {
+	root                                      [32]byte
+	currentBranch, nextBranch, finalityBranch [][32]byte
+}
+
+func setupState(t *testing.T, current, next map[string]any, finalizedRoot [32]byte) testState {
+	t.Helper()
+	checkpoint := map[string]any{"epoch": "0", "root": "0x" + hex.EncodeToString(finalizedRoot[:])}
+	fields := map[string][32]byte{
+		"current_sync_committee": hashValue(t, beacon.SyncCommitteeType, current),
+		"next_sync_committee":    hashValue(t, beacon.SyncCommitteeType, next),
+		"finalized_checkpoint":   hashValue(t, beacon.CheckpointType, checkpoint),
+	}
+
+	var state testState
+	state.root, state.currentBranch = containerWith(t, testSchema.BeaconState, fields, "current_sync_committee")
+	_, state.nextBranch = containerWith(t, testSchema.BeaconState, fields, "next_sync_committee")
+	_, outer := containerWith(t, testSchema.BeaconState, fields, "finalized_checkpoint")
+	// The checkpoint root sits next to the epoch chunk
+	state.finalityBranch = append([][32]byte{{}}, outer...)
+	return state
+}
+
+// setupHeader returns a light client header at slot committing to stateRoot
+func setupHeader(t *testing.T, slot uint64, stateRoot [32]byte) Header {
+	t.Helper()
+	execution := ssz.Zero(testSchema.ExecutionPayloadHeader).(map[string]any)
+	execution["block_number"] = strconv.FormatUint(slot, 10)
+	execution["timestamp"] = strconv.FormatUint(1700000000+slot*beacon.SecondsPerSlot, 10)
+
+	bodyRoot, branch := containerWith(t, testSchema.BeaconBlockBody,
+		map[string][32]byte{"execution_payload": hashValue(t, testSchema.ExecutionPayloadHeader, execution)},
+		"execution_payload")
+	return Header{
+		Beacon: beacon.HeaderData{
+			Slot:          strconv.FormatUint(slot, 10),
+			ProposerIndex: "7",
+			ParentRoot:    "0x" + strings.Repeat("ab", 32),
+			StateRoot:     "0x" + hex.EncodeToString(stateRoot[:]),
+			BodyRoot:      "0x" + hex.EncodeToString(bodyRoot[:]),
+		},
+		Execution:       execution,
+		ExecutionBranch: branch,
+	}
+}
+
+func headerRoot(t *testing.T, h Header) [32]byte {
+	t.Helper()
+	root, err := h.Root()
+	if err != nil {
+		t.Fatalf("Root() error = %v", err)
+	}
+	return root
+}
+
+// setupBootstrap returns a bootstrap at slot with the given committee
+func setupBootstrap(t *testing.T, slot uint64, current map[string]any) Bootstrap {
+	t.Helper()
+	state := setupState(t, current, testCommittee(0xee), [32]byte{})
+	return Bootstrap{
+		Schema:                     testSchema,
+		Header:                     setupHeader(t, slot, state.root),
+		CurrentSyncCommittee:       current,
+		CurrentSyncCommitteeBranch: state.currentBranch,
+	}
+}
+
+// setupUpdate returns a full update attesting attestedSlot and finalizing
+// finalizedSlot, signed by every member
+func setupUpdate(t *testing.T, attestedSlot, finalizedSlot uint64, current, next map[string]any) Update {
+	t.Helper()
+	finalized := setupHeader(t, finalizedSlot, [32]byte{1})
+	state := setupState(t, current, next, headerRoot(t, finalized))
+	return Update{
+		Schema:                  testSchema,
+		AttestedHeader:          setupHeader(t, attestedSlot, state.root),
+		NextSyncCommittee:       next,
+		NextSyncCommitteeBranch: state.nextBranch,
+		FinalizedHeader:         &finalized,
+		FinalityBranch:          state.finalityBranch,
+		SyncAggregate: map[string]any{
+			"sync_committee_bits":      "0x" + strings.Repeat("ff", beacon.SyncCommitteeSize/8),
+			"sync_committee_signature": "0x" + strings.Repeat("00", 96),
+		},
+		SignatureSlot: attestedSlot + 1,
+	}
+}
+
+func TestBootstrapVerify(t *testing.T) {
+	bootstrap := setupBootstrap(t, 10*periodSlots+64, testCommittee(1))
+	root := headerRoot(t, bootstrap.Header)
+	if err := bootstrap.Verify(root); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(b *Bootstrap)
+		root   [32]byte
+	}{
+		{"Untrusted root", func(b *Bootstrap) {}, [32]byte{9}},
+		{"Committee", func(b *Bootstrap) { b.CurrentSyncCommittee = testCommittee(2) }, root},
+		{"Short branch", func(b *Bootstrap) { b.CurrentSyncCommitteeBranch = b.CurrentSyncCommitteeBranch[1:] }, root},
+		{"Execution timestamp", func(b *Bootstrap) {
+			execution := make(map[string]any)
+			for k, v := range b.Header.Execution {
+				execution[k] = v
+			}
+			execution["timestamp"] = "1"
+			b.Header.Execution = execution
+		}, root},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := setupBootstrap(t, 10*periodSlots+64, testCommittee(1))
+			tt.tamper(&tampered)
+			if err := tampered.Verify(tt.root); err == nil {
+				t.Error("Verify() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestUpdateVerify(t *testing.T) {
+	attested, finalized := uint64(10*periodSlots+200), uint64(10*periodSlots+128)
+	update := setupUpdate(t, attested, finalized, testCommittee(1), testCommittee(2))
+	if err := update.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(u *Update)
+	}{
+		{"Next committee", func(u *Update) { u.NextSyncCommittee = testCommittee(3) }},
+		{"Finalized header", func(u *Update) {
+			other := setupHeader(t, finalized, [32]byte{2})
+			u.FinalizedHeader = &other
+		}},
+		{"Finality branch", func(u *Update) { u.FinalityBranch[0] = [32]byte{1} }},
+		{"Signature slot", func(u *Update) { u.SignatureSlot = attested }},
+		{"No participants", func(u *Update) {
+			u.SyncAggregate = map[string]any{"sync_committee_bits": "0x" + strings.Repeat("00", beacon.SyncCommitteeSize/8)}
+		}},
+		{"Attested execution branch", func(u *Update) { u.AttestedHeader.ExecutionBranch[3] = [32]byte{1} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := setupUpdate(t, attested, finalized, testCommittee(1), testCommittee(2))
+			tt.tamper(&tampered)
+			if err := tampered.Verify(); err == nil {
+				t.Error("Verify() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestUpdateVerifyEmptyFinality(t *testing.T) {
+	attested := uint64(200)
+	empty := Header{
+		Beacon: beacon.HeaderData{
+			Slot:          "0",
+			ProposerIndex: "0",
+			ParentRoot:    "0x" + strings.Repeat("00", 32),
+			StateRoot:     "0x" + strings.Repeat("00", 32),
+			BodyRoot:      "0x" + strings.Repeat("00", 32),
+		},
+		Execution:       ssz.Zero(testSchema.ExecutionPayloadHeader).(map[string]any),
+		ExecutionBranch: make([][32]byte, 4),
+	}
+	setup := func(finalized Header) Update {
+		state := setupState(t, testCommittee(1), testCommittee(2), [32]byte{})
+		update := setupUpdate(t, attested, 0, testCommittee(1), testCommittee(2))
+		update.AttestedHeader = setupHeader(t, attested, state.root)
+		update.FinalizedHeader = &finalized
+		update.NextSyncCommitteeBranch = state.nextBranch
+		update.FinalityBranch = state.finalityBranch
+		return update
+	}
+
+	// Before the first finalization the zero checkpoint root proves an empty
+	// header
+	update := setup(empty)
+	if err := update.Verify(); err != nil {
+		t.Fatalf("Verify() with an empty finalized header error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(h *Header)
+	}{
+		{"Proposer index", func(h *Header) { h.Beacon.ProposerIndex = "7" }},
+		{"State root", func(h *Header) { h.Beacon.StateRoot = "0x" + strings.Repeat("01", 32) }},
+		{"Execution header", func(h *Header) {
+			execution := ssz.Zero(testSchema.ExecutionPayloadHeader).(map[string]any)
+			execution["timestamp"] = "1"
+			h.Execution = execution
+		}},
+		{"Execution branch", func(h *Header) { h.ExecutionBranch = [][32]byte{{}, {1}, {}, {}} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finalized := empty
+			tt.tamper(&finalized)
+			update := setup(finalized)
+			if err := update.Verify(); err == nil {
+				t.Error("Verify() with a non-empty finalized header at slot 0 expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseUpdate(t *testing.T) {
+	update := setupUpdate(t, 10*periodSlots+200, 10*periodSlots+128, testCommittee(1), testCommittee(2))
+
+	headerJSON := func(h Header) map[string]any {
+		return map[string]any{
+			"beacon": map[string]any{
+				"slot":           h.Beacon.Slot,
+				"proposer_index": h.Beacon.ProposerIndex,
+				"parent_root":    h.Beacon.ParentRoot,
+				"state_root":     h.Beacon.StateRoot,
+				"body_root":      h.Beacon.BodyRoot,
+			},
+			"execution":        h.Execution,
+			"execution_branch": branchJSON(h.ExecutionBranch),
+		}
+	}
+	data := &beacon.LightClientData{
+		Version: beacon.Deneb,
+		Data: map[string]any{
+			"attested_header":            headerJSON(update.AttestedHeader),
+			"next_sync_committee":        update.NextSyncCommittee,
+			"next_sync_committee_branch": branchJSON(update.NextSyncCommitteeBranch),
+			"finalized_header":           headerJSON(*update.FinalizedHeader),
+			"finality_branch":            branchJSON(update.FinalityBranch),
+			"sync_aggregate":             update.SyncAggregate,
+			"signature_slot":             strconv.FormatUint(update.SignatureSlot, 10),
+		},
+	}
+
+	parsed, err := ParseUpdate(data)
+	if err != nil {
+		t.Fatalf("ParseUpdate() error = %v", err)
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	// A finality update has no sync committee fields
+	delete(data.Data, "next_sync_committee")
+	delete(data.Data, "next_sync_committee_branch")
+	parsed, err = ParseUpdate(data)
+	if err != nil {
+		t.Fatalf("ParseUpdate() error = %v", err)
+	}
+	if parsed.NextSyncCommittee != nil || parsed.FinalizedHeader == nil {
+		t.Error("ParseUpdate() did not read a finality update")
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	data.Version = "bellatrix"
+	if _, err := ParseUpdate(data); err == nil {
+		t.Error("ParseUpdate() expected error for an unsupported fork, got nil")
+	}
+}
+
+func branchJSON(branch [][32]byte) []any {
+	out := make([]any, len(branch))
+	for i, node := range branch {
+		out[i] = "0x" + hex.EncodeToString(node[:])
+	}
+	return out
+}
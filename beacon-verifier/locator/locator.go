@@ -0,0 +1,281 @@
+// Package locator parses the block identifiers accepted for -slot and
+// -anchor and resolves them to a concrete slot and header
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
+)
+
+// Kind is the form a block identifier takes
+type Kind int
+
+const (
+	// Slot is a decimal slot number
+	Slot Kind = iota
+	// Named is head, finalized, justified or genesis
+	Named
+	// Root is a 0x-prefixed block root
+	Root
+	// Time is an RFC 3339 time, located by the slot in progress at it
+	Time
+	// ExecutionBlock is el:<number>, located by the execution block's
+	// timestamp
+	ExecutionBlock
+)
+
+// Block names the beacon API or the finality checkpoints provide
+const (
+	Head      = "head"
+	Finalized = "finalized"
+	Justified = "justified"
+	Genesis   = "genesis"
+)
+
+// executionPrefix introduces an execution block number
+const executionPrefix = "el:"
+
+// Locator is a parsed block identifier
+type Locator struct {
+	Kind Kind
+	// Name is set for Named and Root locators
+	Name string
+	// Number is the slot of a Slot locator or the block number of an
+	// ExecutionBlock locator
+	Number uint64
+	Time   time.Time
+	// Offset is a number of slots added to the slot a Slot, Named or Root
+	// locator resolves to, as in finalized-32
+	Offset int64
+}
+
+// Parse parses a block identifier: a slot, head, finalized, justified,
+// genesis, a 0x block root, any of these followed by +N or -N slots, an
+// RFC 3339 time, or el:<block number>
+func Parse(s string) (Locator, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Locator{}, errors.New("empty block identifier")
+	}
+	// Times carry signs of their own, so they are tried before offsets
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return Locator{Kind: Time, Time: t}, nil
+	}
+	if number, ok := strings.CutPrefix(s, executionPrefix); ok {
+		n, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return Locator{}, fmt.Errorf("invalid execution block number in %q: %w", s, err)
+		}
+		return Locator{Kind: ExecutionBlock, Number: n}, nil
+	}
+
+	base, offset, err := splitOffset(s)
+	if err != nil {
+		return Locator{}, err
+	}
+	loc := Locator{Offset: offset}
+	switch {
+	case base == Head || base == Finalized || base == Justified || base == Genesis:
+		loc.Kind, loc.Name = Named, base
+	case strings.HasPrefix(base, "0x"):
+		if err := checkRoot(base); err != nil {
+			return Locator{}, fmt.Errorf("invalid block root %q: %w", base, err)
+		}
+		loc.Kind, loc.Name = Root, strings.ToLower(base)
+	default:
+		n, err := strconv.ParseUint(base, 10, 64)
+		if err != nil {
+			return Locator{}, fmt.Errorf("unknown block identifier %q: want a slot, head, finalized, justified, genesis, "+
+				"a 0x block root, an RFC 3339 time or el:<block number>", s)
+		}
+		loc.Kind, loc.Number = Slot, n
+	}
+	return loc, nil
+}
+
+// splitOffset splits a trailing +N or -N from an identifier
+func splitOffset(s string) (string, int64, error) {
+	i := strings.LastIndexAny(s, "+-")
+	if i <= 0 {
+		return s, 0, nil
+	}
+	n, err := strconv.ParseUint(s[i+1:], 10, 63)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid slot offset in %q: %w", s, err)
+	}
+	if s[i] == '-' {
+		return s[:i], -int64(n), nil
+	}
+	return s[:i], int64(n), nil
+}
+
+// checkRoot checks that a root is 32 bytes of hex
+func checkRoot(root string) error {
+	digits := root[2:]
+	if len(digits) != 64 {
+		return fmt.Errorf("%d hex digits, expected 64", len(digits))
+	}
+	for _, c := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return fmt.Errorf("invalid hex digit %q", c)
+		}
+	}
+	return nil
+}
+
+// String returns the identifier in the form Parse accepts
+func (l Locator) String() string {
+	var base string
+	switch l.Kind {
+	case Time:
+		return l.Time.Format(time.RFC3339)
+	case ExecutionBlock:
+		return executionPrefix + strconv.FormatUint(l.Number, 10)
+	case Slot:
+		base = strconv.FormatUint(l.Number, 10)
+	default:
+		base = l.Name
+	}
+	if l.Offset > 0 {
+		return base + "+" + strconv.FormatInt(l.Offset, 10)
+	}
+	if l.Offset < 0 {
+		return base + strconv.FormatInt(l.Offset, 10)
+	}
+	return base
+}
+
+// Resolver turns locators into slots and headers
+type Resolver struct {
+	// Header fetches a header by beacon API block ID
+	Header func(ctx context.Context, blockID string) (beacon.HeaderData, error)
+	// Checkpoints fetches the finality checkpoints of the head state, which
+	// locate the justified block
+	Checkpoints func(ctx context.Context) (*beacon.FinalityCheckpoints, error)
+	// Clock locates times and execution timestamps; without it they fail
+	Clock *chaintime.Clock
+	// ExecutionTimestamp returns the timestamp of an execution block; without
+	// it execution block numbers fail
+	ExecutionTimestamp func(ctx context.Context, number uint64) (uint64, error)
+}
+
+// zeroRoot is the checkpoint root of the genesis epoch before anything is
+// justified
+const zeroRoot = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+// SlotOf returns the slot a locator points at. The slot need not hold a
+// block, so a skipped slot can be named relative to another.
+func (r *Resolver) SlotOf(ctx context.Context, loc Locator) (uint64, error) {
+	switch loc.Kind {
+	case Slot:
+		return offsetSlot(loc, loc.Number)
+	case Named, Root:
+		if loc.Name == Genesis {
+			return offsetSlot(loc, 0)
+		}
+		base, err := r.baseHeader(ctx, loc)
+		if err != nil {
+			return 0, err
+		}
+		slot, err := strconv.ParseUint(base.Slot, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing slot of %s: %w", loc.Name, err)
+		}
+		return offsetSlot(loc, slot)
+	case Time:
+		if r.Clock == nil {
+			return 0, fmt.Errorf("cannot locate %s without the chain's genesis time", loc)
+		}
+		return r.Clock.SlotAt(loc.Time)
+	case ExecutionBlock:
+		timestamp, err := r.executionTimestamp(ctx, loc.Number)
+		if err != nil {
+			return 0, err
+		}
+		return r.Clock.SlotOfExecutionTimestamp(timestamp)
+	}
+	return 0, fmt.Errorf("unknown locator kind %d", loc.Kind)
+}
+
+// Resolve returns the header of the block a locator points at. A locator
+// that lands on a skipped slot fails with an error matching
+// beacon.ErrSlotEmpty.
+func (r *Resolver) Resolve(ctx context.Context, loc Locator) (beacon.HeaderData, error) {
+	if (loc.Kind == Named || loc.Kind == Root) && loc.Offset == 0 && loc.Name != Genesis {
+		return r.baseHeader(ctx, loc)
+	}
+
+	var slot, timestamp uint64
+	var err error
+	if loc.Kind == ExecutionBlock {
+		if timestamp, err = r.executionTimestamp(ctx, loc.Number); err != nil {
+			return beacon.HeaderData{}, err
+		}
+		if slot, err = r.Clock.SlotOfExecutionTimestamp(timestamp); err != nil {
+			return beacon.HeaderData{}, err
+		}
+	} else if slot, err = r.SlotOf(ctx, loc); err != nil {
+		return beacon.HeaderData{}, err
+	}
+	header, err := r.Header(ctx, strconv.FormatUint(slot, 10))
+	if err != nil {
+		return beacon.HeaderData{}, fmt.Errorf("error fetching %s at slot %d: %w", loc, slot, err)
+	}
+	// The slot came from the execution timestamp; the block must carry it
+	if loc.Kind == ExecutionBlock && uint64(header.Timestamp) != timestamp {
+		return beacon.HeaderData{}, fmt.Errorf("block at slot %d has execution timestamp %d, but %s has %d",
+			slot, header.Timestamp, loc, timestamp)
+	}
+	return header, nil
+}
+
+// baseHeader fetches the block a Named or Root locator names, before any
+// offset
+func (r *Resolver) baseHeader(ctx context.Context, loc Locator) (beacon.HeaderData, error) {
+	blockID := loc.Name
+	if loc.Name == Justified {
+		if r.Checkpoints == nil {
+			return beacon.HeaderData{}, errors.New("cannot locate the justified block without finality checkpoints")
+		}
+		checkpoints, err := r.Checkpoints(ctx)
+		if err != nil {
+			return beacon.HeaderData{}, err
+		}
+		blockID = checkpoints.CurrentJustified.Root
+		if blockID == zeroRoot {
+			blockID = Genesis
+		}
+	}
+	header, err := r.Header(ctx, blockID)
+	if err != nil {
+		return beacon.HeaderData{}, fmt.Errorf("error fetching %s block: %w", loc.Name, err)
+	}
+	return header, nil
+}
+
+// executionTimestamp looks up an execution block's timestamp
+func (r *Resolver) executionTimestamp(ctx context.Context, number uint64) (uint64, error) {
+	if r.ExecutionTimestamp == nil || r.Clock == nil {
+		return 0, fmt.Errorf("cannot locate execution block %d without an execution node and the chain's genesis time", number)
+	}
+	timestamp, err := r.ExecutionTimestamp(ctx, number)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching execution block %d: %w", number, err)
+	}
+	return timestamp, nil
+}
+
+// offsetSlot applies a locator's offset to the slot its base resolved to
+func offsetSlot(loc Locator, slot uint64) (uint64, error) {
+	if loc.Offset < 0 && uint64(-loc.Offset) > slot {
+		return 0, fmt.Errorf("%s is before genesis: slot %d minus %d", loc, slot, -loc.Offset)
+	}
+	return uint64(int64(slot) + loc.Offset), nil
+}
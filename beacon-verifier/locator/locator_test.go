@@ -0,0 +1,265 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/chaintime"
+)
+
+// denebSlot is the first slot of Deneb on mainnet
+const denebSlot = 8626176
+
+const (
+	rootA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	rootB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Locator
+		str  string
+	}{
+		{"123", Locator{Kind: Slot, Number: 123}, "123"},
+		{" head ", Locator{Kind: Named, Name: Head}, "head"},
+		{"finalized-32", Locator{Kind: Named, Name: Finalized, Offset: -32}, "finalized-32"},
+		{"justified+1", Locator{Kind: Named, Name: Justified, Offset: 1}, "justified+1"},
+		{"genesis", Locator{Kind: Named, Name: Genesis}, "genesis"},
+		{"100-3", Locator{Kind: Slot, Number: 100, Offset: -3}, "100-3"},
+		{"0x" + strings.ToUpper(rootA[2:]), Locator{Kind: Root, Name: rootA}, rootA},
+		{rootB + "-2", Locator{Kind: Root, Name: rootB, Offset: -2}, rootB + "-2"},
+		{"2024-03-13T13:55:35Z", Locator{Kind: Time, Time: time.Unix(1710338135, 0).UTC()}, "2024-03-13T13:55:35Z"},
+		{"el:19426587", Locator{Kind: ExecutionBlock, Number: 19426587}, "el:19426587"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if got.Kind != tt.want.Kind || got.Name != tt.want.Name || got.Number != tt.want.Number ||
+				got.Offset != tt.want.Offset || !got.Time.Equal(tt.want.Time) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+			}
+		})
+	}
+
+	// A time with a zone offset is not mistaken for a slot offset
+	got, err := Parse("2024-03-13T18:55:35+05:00")
+	if err != nil || got.Kind != Time || got.Time.Unix() != 1710338135 {
+		t.Errorf("Parse() of a zoned time = %+v, %v", got, err)
+	}
+
+	for _, in := range []string{"", "latest", "head-", "head-x", "head--1", "-5", "0x1234", "0x" + strings.Repeat("zz", 32),
+		"el:", "el:-1", "el:12-1", "2024-03-13"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", in)
+		}
+	}
+}
+
+// chain serves headers of a mainnet-timed chain; each slot in filled holds a
+// block whose root is the slot in hex
+type chain struct {
+	head, finalized uint64
+	filled          map[uint64]bool
+	justifiedRoot   string
+	requests        []string
+}
+
+func (c *chain) header(_ context.Context, blockID string) (beacon.HeaderData, error) {
+	c.requests = append(c.requests, blockID)
+	slot, err := strconv.ParseUint(blockID, 10, 64)
+	switch {
+	case blockID == Head:
+		slot = c.head
+	case blockID == Finalized:
+		slot = c.finalized
+	case blockID == Genesis:
+		slot = 0
+	case strings.HasPrefix(blockID, "0x"):
+		slot, err = strconv.ParseUint(strings.TrimLeft(blockID[2:], "0"), 16, 64)
+		if err != nil {
+			return beacon.HeaderData{}, beacon.ErrSlotEmpty
+		}
+	case err != nil:
+		return beacon.HeaderData{}, err
+	}
+	if !c.filled[slot] {
+		return beacon.HeaderData{}, beacon.ErrSlotEmpty
+	}
+	return beacon.HeaderData{Slot: strconv.FormatUint(slot, 10), Timestamp: int64(1606824023 + slot*12)}, nil
+}
+
+func rootOf(slot uint64) string {
+	hex := strconv.FormatUint(slot, 16)
+	return "0x" + strings.Repeat("0", 64-len(hex)) + hex
+}
+
+func newResolver(t *testing.T, c *chain) *Resolver {
+	t.Helper()
+	clock, err := chaintime.ForChain(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Resolver{
+		Header: c.header,
+		Checkpoints: func(context.Context) (*beacon.FinalityCheckpoints, error) {
+			return &beacon.FinalityCheckpoints{CurrentJustified: beacon.Checkpoint{Root: c.justifiedRoot}}, nil
+		},
+		Clock: clock,
+		ExecutionTimestamp: func(_ context.Context, number uint64) (uint64, error) {
+			// Execution block n is in slot denebSlot+n; block 7 is in no slot
+			if number == 7 {
+				return 1606824023 + denebSlot*12 + 5, nil
+			}
+			return 1606824023 + (denebSlot+number)*12, nil
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	c := &chain{
+		head:          denebSlot + 100,
+		finalized:     denebSlot + 32,
+		justifiedRoot: rootOf(denebSlot + 64),
+		filled:        map[uint64]bool{},
+	}
+	for _, slot := range []uint64{0, denebSlot, denebSlot + 32, denebSlot + 64, denebSlot + 100, denebSlot + 99} {
+		c.filled[slot] = true
+	}
+	r := newResolver(t, c)
+
+	tests := []struct {
+		in   string
+		slot uint64
+	}{
+		{"8626176", denebSlot},
+		{"head", denebSlot + 100},
+		{"head-1", denebSlot + 99},
+		{"finalized", denebSlot + 32},
+		{"finalized-32", denebSlot},
+		{"justified", denebSlot + 64},
+		{"justified-32", denebSlot + 32},
+		{"genesis", 0},
+		{rootOf(denebSlot + 64), denebSlot + 64},
+		{rootOf(denebSlot+64) + "+36", denebSlot + 100},
+		{"2024-03-13T13:55:35Z", denebSlot},
+		{"2024-03-13T13:55:46.5Z", denebSlot},
+		{"el:0", denebSlot},
+		{"el:32", denebSlot + 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			loc, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			slot, err := r.SlotOf(context.Background(), loc)
+			if err != nil || slot != tt.slot {
+				t.Errorf("SlotOf(%s) = %d, %v, want %d", loc, slot, err, tt.slot)
+			}
+			header, err := r.Resolve(context.Background(), loc)
+			if err != nil || header.Slot != strconv.FormatUint(tt.slot, 10) {
+				t.Errorf("Resolve(%s) = slot %s, %v, want %d", loc, header.Slot, err, tt.slot)
+			}
+		})
+	}
+
+	// A name without an offset is fetched by its block ID, not its slot
+	c.requests = nil
+	if _, err := r.Resolve(context.Background(), Locator{Kind: Named, Name: Finalized}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.requests) != 1 || c.requests[0] != Finalized {
+		t.Errorf("Resolve(finalized) requested %q, want just finalized", c.requests)
+	}
+
+	// An execution block's timestamp is fetched once
+	lookups := 0
+	timestamp := r.ExecutionTimestamp
+	r.ExecutionTimestamp = func(ctx context.Context, number uint64) (uint64, error) {
+		lookups++
+		return timestamp(ctx, number)
+	}
+	if _, err := r.Resolve(context.Background(), Locator{Kind: ExecutionBlock, Number: 32}); err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 1 {
+		t.Errorf("Resolve(el:32) fetched the execution block %d times, want 1", lookups)
+	}
+
+	// Before anything is justified the checkpoint root is zero
+	c.justifiedRoot = zeroRoot
+	if header, err := r.Resolve(context.Background(), Locator{Kind: Named, Name: Justified}); err != nil || header.Slot != "0" {
+		t.Errorf("Resolve(justified) at genesis = slot %s, %v, want 0", header.Slot, err)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	c := &chain{head: 2000, finalized: 1900, filled: map[uint64]bool{2000: true, 1900: true}}
+	r := newResolver(t, c)
+
+	for _, in := range []string{"1999", "head-2", "finalized+1"} {
+		loc, _ := Parse(in)
+		if _, err := r.SlotOf(context.Background(), loc); err != nil {
+			t.Errorf("SlotOf(%s) error = %v, a skipped slot still has a slot number", in, err)
+		}
+		if _, err := r.Resolve(context.Background(), loc); !errors.Is(err, beacon.ErrSlotEmpty) {
+			t.Errorf("Resolve(%s) error = %v, want ErrSlotEmpty", in, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		loc  Locator
+		edit func(*Resolver)
+	}{
+		{"BeforeGenesisOffset", Locator{Kind: Slot, Number: 5, Offset: -6}, nil},
+		{"BeforeGenesisTime", Locator{Kind: Time, Time: time.Unix(1606824022, 0)}, nil},
+		{"MisalignedExecutionTimestamp", Locator{Kind: ExecutionBlock, Number: 7}, nil},
+		{"NoClock", Locator{Kind: Time, Time: time.Now()}, func(r *Resolver) { r.Clock = nil }},
+		{"NoExecutionNode", Locator{Kind: ExecutionBlock, Number: 1}, func(r *Resolver) { r.ExecutionTimestamp = nil }},
+		{"NoCheckpoints", Locator{Kind: Named, Name: Justified}, func(r *Resolver) { r.Checkpoints = nil }},
+		{"CheckpointsFail", Locator{Kind: Named, Name: Justified}, func(r *Resolver) {
+			r.Checkpoints = func(context.Context) (*beacon.FinalityCheckpoints, error) { return nil, errors.New("down") }
+		}},
+		{"ExecutionNodeFails", Locator{Kind: ExecutionBlock, Number: 1}, func(r *Resolver) {
+			r.ExecutionTimestamp = func(context.Context, uint64) (uint64, error) { return 0, errors.New("down") }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newResolver(t, c)
+			if tt.edit != nil {
+				tt.edit(r)
+			}
+			if _, err := r.SlotOf(context.Background(), tt.loc); err == nil {
+				t.Errorf("SlotOf(%s) expected error, got nil", tt.loc)
+			}
+			if _, err := r.Resolve(context.Background(), tt.loc); err == nil {
+				t.Errorf("Resolve(%s) expected error, got nil", tt.loc)
+			}
+		})
+	}
+
+	// The block found at an execution block's slot must carry its timestamp
+	c.filled[denebSlot+1] = true
+	r.Header = func(ctx context.Context, blockID string) (beacon.HeaderData, error) {
+		header, err := c.header(ctx, blockID)
+		header.Timestamp++
+		return header, err
+	}
+	if _, err := r.Resolve(context.Background(), Locator{Kind: ExecutionBlock, Number: 1}); err == nil {
+		t.Error("Resolve() with a mismatched execution timestamp expected error, got nil")
+	}
+}
@@ -0,0 +1,68 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// GeneralizedIndexDepth returns the depth of a generalized index, i.e. the
+// number of branch nodes a proof for it contains
+func GeneralizedIndexDepth(gindex uint64) int {
+	if gindex == 0 {
+		return 0
+	}
+	return bits.Len64(gindex) - 1
+}
+
+// GeneralizedIndexPosition returns the leaf position of a generalized index
+// within its depth
+func GeneralizedIndexPosition(gindex uint64) uint64 {
+	depth := GeneralizedIndexDepth(gindex)
+	return gindex - (1 << uint(depth))
+}
+
+// ConcatGeneralizedIndices composes generalized indices from outer to inner,
+// as in get_generalized_index_for_path. The result addresses the innermost
+// node relative to the outermost root.
+func ConcatGeneralizedIndices(indices ...uint64) (uint64, error) {
+	result := uint64(1)
+	for _, gindex := range indices {
+		if gindex == 0 {
+			return 0, fmt.Errorf("generalized index 0 is invalid")
+		}
+		depth := GeneralizedIndexDepth(gindex)
+		if GeneralizedIndexDepth(result)+depth >= 64 {
+			return 0, fmt.Errorf("generalized index overflows 64 bits")
+		}
+		result = result<<uint(depth) | GeneralizedIndexPosition(gindex)
+	}
+	return result, nil
+}
+
+// ComputeRootFromBranch hashes a leaf up a bottom-up branch using the
+// position bits of the generalized index
+func ComputeRootFromBranch(leaf [32]byte, branch [][32]byte, gindex uint64) ([32]byte, error) {
+	if len(branch) != GeneralizedIndexDepth(gindex) {
+		return [32]byte{}, fmt.Errorf("branch length %d does not match depth %d of generalized index %d",
+			len(branch), GeneralizedIndexDepth(gindex), gindex)
+	}
+
+	current := leaf
+	for i, sibling := range branch {
+		if (gindex>>uint(i))&1 == 1 {
+			current = HashPair(sibling, current)
+		} else {
+			current = HashPair(current, sibling)
+		}
+	}
+	return current, nil
+}
+
+// VerifyBranch checks that leaf is at gindex under root
+func VerifyBranch(leaf [32]byte, branch [][32]byte, gindex uint64, root [32]byte) bool {
+	computed, err := ComputeRootFromBranch(leaf, branch, gindex)
+	if err != nil {
+		return false
+	}
+	return computed == root
+}
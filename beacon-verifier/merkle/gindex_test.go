@@ -0,0 +1,77 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGeneralizedIndexDepthAndPosition(t *testing.T) {
+	tests := []struct {
+		gindex   uint64
+		depth    int
+		position uint64
+	}{
+		{1, 0, 0},
+		{2, 1, 0},
+		{3, 1, 1},
+		{12, 3, 4},
+		{105, 6, 41},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("gindex=%d", tt.gindex), func(t *testing.T) {
+			if got := GeneralizedIndexDepth(tt.gindex); got != tt.depth {
+				t.Errorf("GeneralizedIndexDepth(%d) = %d, want %d", tt.gindex, got, tt.depth)
+			}
+			if got := GeneralizedIndexPosition(tt.gindex); got != tt.position {
+				t.Errorf("GeneralizedIndexPosition(%d) = %d, want %d", tt.gindex, got, tt.position)
+			}
+		})
+	}
+}
+
+func TestConcatGeneralizedIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []uint64
+		want    uint64
+		wantErr bool
+	}{
+		{"Empty", nil, 1, false},
+		{"Single", []uint64{12}, 12, false},
+		{"Header body_root then body field 9", []uint64{12, 25}, 12<<4 | 9, false},
+		{"Root is neutral", []uint64{1, 12, 1}, 12, false},
+		{"Zero is invalid", []uint64{12, 0}, 0, true},
+		{"Overflow", []uint64{1 << 40, 1 << 30}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConcatGeneralizedIndices(tt.indices...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConcatGeneralizedIndices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ConcatGeneralizedIndices() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyBranch(t *testing.T) {
+	chunks := [][32]byte{{1}, {2}, {3}, {4}}
+	root, branch, err := MerkleizeWithProof(chunks, 4, 2)
+	if err != nil {
+		t.Fatalf("MerkleizeWithProof() error = %v", err)
+	}
+
+	if !VerifyBranch(chunks[2], branch, 6, root) {
+		t.Errorf("VerifyBranch() = false for a valid branch")
+	}
+	if VerifyBranch(chunks[2], branch, 7, root) {
+		t.Errorf("VerifyBranch() = true for the wrong generalized index")
+	}
+	if VerifyBranch(chunks[2], branch[:1], 6, root) {
+		t.Errorf("VerifyBranch() = true for a truncated branch")
+	}
+}
@@ -0,0 +1,156 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// MaxDepth is the deepest virtual tree supported by the limit-aware helpers
+const MaxDepth = 64
+
+// zeroHashes[i] is the root of a depth-i tree whose leaves are all zero chunks
+var zeroHashes [MaxDepth + 1][32]byte
+
+func init() {
+	for i := 1; i <= MaxDepth; i++ {
+		zeroHashes[i] = HashPair(zeroHashes[i-1], zeroHashes[i-1])
+	}
+}
+
+// ZeroHash returns the root of a tree of the given depth filled with zero chunks
+func ZeroHash(depth int) [32]byte {
+	return zeroHashes[depth]
+}
+
+// HashPair returns sha256(left || right)
+func HashPair(left, right [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], left[:])
+	copy(buf[32:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// DepthForLimit returns the depth of the tree needed to hold limit chunks
+func DepthForLimit(limit uint64) int {
+	if limit <= 1 {
+		return 0
+	}
+	return bits.Len64(limit - 1)
+}
+
+// Merkleize computes the root of chunks padded with zero chunks up to limit,
+// following the SSZ merkleize(chunks, limit) definition. Padding is never
+// materialized, so very large limits (e.g. 2^40 list entries) are cheap.
+func Merkleize(chunks [][32]byte, limit uint64) ([32]byte, error) {
+	root, _, err := merkleize(chunks, limit, -1)
+	return root, err
+}
+
+// MerkleizeWithProof computes the same root as Merkleize and also returns the
+// sibling branch (bottom-up) for the chunk at the given position. Positions in
+// the zero padding beyond the limit are allowed up to the padded tree width.
+func MerkleizeWithProof(chunks [][32]byte, limit uint64, index uint64) ([32]byte, [][32]byte, error) {
+	if index >= uint64(1)<<uint(DepthForLimit(limit)) {
+		return [32]byte{}, nil, fmt.Errorf("index %d is out of range for limit %d", index, limit)
+	}
+	return merkleize(chunks, limit, int64(index))
+}
+
+func merkleize(chunks [][32]byte, limit uint64, index int64) ([32]byte, [][32]byte, error) {
+	if uint64(len(chunks)) > max(limit, 1) {
+		return [32]byte{}, nil, fmt.Errorf("%d chunks exceed limit %d", len(chunks), limit)
+	}
+
+	depth := DepthForLimit(limit)
+	var branch [][32]byte
+	if index >= 0 {
+		branch = make([][32]byte, 0, depth)
+	}
+
+	layer := make([][32]byte, len(chunks))
+	copy(layer, chunks)
+	pos := index
+
+	for d := 0; d < depth; d++ {
+		if index >= 0 {
+			sibling := pos ^ 1
+			if sibling < int64(len(layer)) {
+				branch = append(branch, layer[sibling])
+			} else {
+				branch = append(branch, zeroHashes[d])
+			}
+			pos /= 2
+		}
+
+		next := make([][32]byte, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			right := zeroHashes[d]
+			if i+1 < len(layer) {
+				right = layer[i+1]
+			}
+			next[i/2] = HashPair(layer[i], right)
+		}
+		layer = next
+	}
+
+	if len(layer) == 0 {
+		return zeroHashes[depth], branch, nil
+	}
+	return layer[0], branch, nil
+}
+
+// MerkleizeSubtrees computes the root of a depth-deep tree whose nodes at
+// the given height are roots, padded on the right with zero subtrees. It is
+// Merkleize for callers that already hashed the lower levels.
+func MerkleizeSubtrees(roots [][32]byte, height, depth int) ([32]byte, error) {
+	if height+depth > MaxDepth {
+		return [32]byte{}, fmt.Errorf("tree depth %d exceeds %d", height+depth, MaxDepth)
+	}
+	if depth < 64 && uint64(len(roots)) > uint64(1)<<uint(depth) {
+		return [32]byte{}, fmt.Errorf("%d subtrees exceed the %d of a depth-%d tree", len(roots), uint64(1)<<uint(depth), depth)
+	}
+
+	layer := make([][32]byte, len(roots))
+	copy(layer, roots)
+	for d := 0; d < depth; d++ {
+		next := make([][32]byte, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			right := zeroHashes[height+d]
+			if i+1 < len(layer) {
+				right = layer[i+1]
+			}
+			next[i/2] = HashPair(layer[i], right)
+		}
+		layer = next
+	}
+
+	if len(layer) == 0 {
+		return zeroHashes[height+depth], nil
+	}
+	return layer[0], nil
+}
+
+// MixInLength mixes a list length into a list data root
+func MixInLength(root [32]byte, length uint64) [32]byte {
+	var lengthChunk [32]byte
+	binary.LittleEndian.PutUint64(lengthChunk[:], length)
+	return HashPair(root, lengthChunk)
+}
+
+// LengthChunk returns the chunk that MixInLength hashes next to the data root
+func LengthChunk(length uint64) [32]byte {
+	var lengthChunk [32]byte
+	binary.LittleEndian.PutUint64(lengthChunk[:], length)
+	return lengthChunk
+}
+
+// Pack splits serialized bytes into right-padded 32-byte chunks
+func Pack(data []byte) [][32]byte {
+	chunks := make([][32]byte, (len(data)+31)/32)
+	for i := range chunks {
+		copy(chunks[i][:], data[i*32:])
+	}
+	return chunks
+}
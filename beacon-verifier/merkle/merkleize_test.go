@@ -0,0 +1,159 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMerkleizeMatchesTree(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		t.Run(fmt.Sprintf("chunks=%d", n), func(t *testing.T) {
+			chunks := make([][32]byte, n)
+			legacy := make([][]byte, n)
+			for i := range chunks {
+				chunks[i][0] = byte(i + 1)
+				legacy[i] = chunks[i][:]
+			}
+
+			tree, err := NewTree(legacy)
+			if err != nil {
+				t.Fatalf("Failed to create tree: %v", err)
+			}
+
+			root, err := Merkleize(chunks, uint64(n))
+			if err != nil {
+				t.Fatalf("Merkleize() error = %v", err)
+			}
+			if !bytes.Equal(root[:], tree.Root()) {
+				t.Errorf("Merkleize() = %x, want %x", root, tree.Root())
+			}
+		})
+	}
+}
+
+func TestMerkleizeWithLimit(t *testing.T) {
+	chunks := [][32]byte{{1}, {2}}
+
+	// A limit of 8 pads to a depth-3 tree: the right half is all zeroes
+	left := HashPair(HashPair(chunks[0], chunks[1]), ZeroHash(1))
+	want := HashPair(left, ZeroHash(2))
+
+	got, err := Merkleize(chunks, 8)
+	if err != nil {
+		t.Fatalf("Merkleize() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Merkleize() = %x, want %x", got, want)
+	}
+
+	// Huge limits only cost one hash per level
+	if _, err := Merkleize(chunks, 1<<40); err != nil {
+		t.Errorf("Merkleize() with limit 2^40 error = %v", err)
+	}
+
+	if _, err := Merkleize(chunks, 1); err == nil {
+		t.Errorf("Merkleize() expected error when chunks exceed the limit")
+	}
+}
+
+func TestMerkleizeEmpty(t *testing.T) {
+	got, err := Merkleize(nil, 16)
+	if err != nil {
+		t.Fatalf("Merkleize() error = %v", err)
+	}
+	if got != ZeroHash(4) {
+		t.Errorf("Merkleize(nil, 16) = %x, want %x", got, ZeroHash(4))
+	}
+}
+
+func TestMerkleizeWithProof(t *testing.T) {
+	chunks := make([][32]byte, 5)
+	for i := range chunks {
+		chunks[i][0] = byte(i + 1)
+	}
+	const limit = 64
+
+	root, err := Merkleize(chunks, limit)
+	if err != nil {
+		t.Fatalf("Merkleize() error = %v", err)
+	}
+
+	for i := uint64(0); i < 8; i++ {
+		proofRoot, branch, err := MerkleizeWithProof(chunks, limit, i)
+		if err != nil {
+			t.Fatalf("MerkleizeWithProof(%d) error = %v", i, err)
+		}
+		if proofRoot != root {
+			t.Errorf("MerkleizeWithProof(%d) root = %x, want %x", i, proofRoot, root)
+		}
+		if len(branch) != 6 {
+			t.Errorf("MerkleizeWithProof(%d) branch length = %d, want 6", i, len(branch))
+		}
+
+		var leaf [32]byte
+		if i < uint64(len(chunks)) {
+			leaf = chunks[i]
+		}
+		if !VerifyBranch(leaf, branch, limit+i, root) {
+			t.Errorf("branch for chunk %d does not verify", i)
+		}
+	}
+
+	if _, _, err := MerkleizeWithProof(chunks, 5, 8); err == nil {
+		t.Errorf("MerkleizeWithProof() expected error for index beyond limit")
+	}
+}
+
+func TestMerkleizeSubtrees(t *testing.T) {
+	chunks := make([][32]byte, 11)
+	for i := range chunks {
+		chunks[i] = [32]byte{byte(i + 1)}
+	}
+	want, err := Merkleize(chunks, 64)
+	if err != nil {
+		t.Fatalf("Merkleize() error = %v", err)
+	}
+
+	// Hash groups of four chunks first, then the four levels above them
+	var roots [][32]byte
+	for i := 0; i < len(chunks); i += 4 {
+		root, err := Merkleize(chunks[i:min(i+4, len(chunks))], 4)
+		if err != nil {
+			t.Fatalf("Merkleize() error = %v", err)
+		}
+		roots = append(roots, root)
+	}
+	got, err := MerkleizeSubtrees(roots, 2, 4)
+	if err != nil {
+		t.Fatalf("MerkleizeSubtrees() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("MerkleizeSubtrees() = %x, want %x", got, want)
+	}
+
+	if empty, _ := MerkleizeSubtrees(nil, 2, 4); empty != ZeroHash(6) {
+		t.Errorf("MerkleizeSubtrees(nil) = %x, want %x", empty, ZeroHash(6))
+	}
+	if _, err := MerkleizeSubtrees(make([][32]byte, 3), 0, 1); err == nil {
+		t.Error("MerkleizeSubtrees() expected error for too many subtrees, got nil")
+	}
+}
+
+func TestMixInLength(t *testing.T) {
+	root := [32]byte{7}
+	want := HashPair(root, [32]byte{3})
+	if got := MixInLength(root, 3); got != want {
+		t.Errorf("MixInLength() = %x, want %x", got, want)
+	}
+}
+
+func TestPack(t *testing.T) {
+	chunks := Pack(bytes.Repeat([]byte{0xff}, 33))
+	if len(chunks) != 2 {
+		t.Fatalf("Pack() returned %d chunks, want 2", len(chunks))
+	}
+	if chunks[1][0] != 0xff || chunks[1][1] != 0 {
+		t.Errorf("Pack() did not right-pad the last chunk: %x", chunks[1])
+	}
+}
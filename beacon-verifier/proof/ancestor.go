@@ -0,0 +1,46 @@
+package proof
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// GenerateAncestorProof proves the block root recorded for targetSlot in the
+// anchor state's block_roots and composes it with the anchor header's
+// state_root. A state at slot S records the roots of slots S-8192 to S-1;
+// for a skipped slot the entry repeats the root of the last filled slot.
+func GenerateAncestorProof(anchorHeader beacon.HeaderData, schema *beacon.Schema, anchorState any, targetSlot uint64, nextSlotTimestamp int64) (Data, error) {
+	anchorSlot, err := strconv.ParseUint(anchorHeader.Slot, 10, 64)
+	if err != nil {
+		return Data{}, fmt.Errorf("error parsing anchor slot: %w", err)
+	}
+	if err := CheckAncestorRange(targetSlot, anchorSlot); err != nil {
+		return Data{}, err
+	}
+
+	index := strconv.FormatUint(targetSlot%beacon.SlotsPerHistoricalRoot, 10)
+	proofData, err := generateNestedProof(anchorHeader, beacon.StatePathRoot, schema.BeaconState, anchorState,
+		[]string{"block_roots", index}, nextSlotTimestamp)
+	if err != nil {
+		return Data{}, fmt.Errorf("error proving block_roots[%s]: %w", index, err)
+	}
+
+	log.Printf("Block root recorded for slot %d in the state of slot %d: %s", targetSlot, anchorSlot, proofData.FieldValue)
+	return proofData, nil
+}
+
+// CheckAncestorRange reports whether the state at anchorSlot still records
+// the block root of targetSlot in its block_roots
+func CheckAncestorRange(targetSlot, anchorSlot uint64) error {
+	if targetSlot >= anchorSlot {
+		return fmt.Errorf("target slot %d is not before anchor slot %d", targetSlot, anchorSlot)
+	}
+	if anchorSlot-targetSlot > beacon.SlotsPerHistoricalRoot {
+		return fmt.Errorf("target slot %d is more than %d slots before anchor slot %d; use a historical proof",
+			targetSlot, beacon.SlotsPerHistoricalRoot, anchorSlot)
+	}
+	return nil
+}
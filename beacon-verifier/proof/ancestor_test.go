@@ -0,0 +1,82 @@
+package proof
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupAnchorState returns a Deneb state at slot 123456 with distinct
+// block_roots and a header committing to it
+func setupAnchorState(t *testing.T, blockRoots []any) (any, beacon.HeaderData) {
+	t.Helper()
+
+	state := ssz.Zero(beacon.BeaconStateDenebType).(map[string]any)
+	state["slot"] = "123456"
+	state["block_roots"] = blockRoots
+
+	stateRoot, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	headerData := setupTestHeader()
+	headerData.StateRoot = "0x" + hex.EncodeToString(stateRoot[:])
+	return state, headerData
+}
+
+func TestGenerateAncestorProof(t *testing.T) {
+	blockRoots := testBlockRoots(0)
+	state, headerData := setupAnchorState(t, blockRoots)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	var header beacon.BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		t.Fatalf("FromAPIResponse() error = %v", err)
+	}
+	blockRoot, err := header.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	for _, targetSlot := range []uint64{123455, 123456 - beacon.SlotsPerHistoricalRoot} {
+		proofData, err := GenerateAncestorProof(headerData, schema, state, targetSlot, 1634567902)
+		if err != nil {
+			t.Fatalf("GenerateAncestorProof(%d) error = %v", targetSlot, err)
+		}
+
+		want := blockRoots[targetSlot%beacon.SlotsPerHistoricalRoot]
+		if proofData.FieldValue != want {
+			t.Errorf("FieldValue = %s, want %s", proofData.FieldValue, want)
+		}
+
+		var leaf [32]byte
+		copy(leaf[:], decodeHexForTest(t, proofData.FieldValue))
+		branch := make([][32]byte, len(proofData.MerkleProof))
+		for i, node := range proofData.MerkleProof {
+			copy(branch[i][:], decodeHexForTest(t, node))
+		}
+		if !merkle.VerifyBranch(leaf, branch, proofData.GeneralizedIndex, blockRoot) {
+			t.Errorf("ancestor proof for slot %d does not verify against the anchor block root", targetSlot)
+		}
+	}
+}
+
+func TestGenerateAncestorProofRange(t *testing.T) {
+	state, headerData := setupAnchorState(t, testBlockRoots(0))
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	for _, targetSlot := range []uint64{123456, 123457, 123456 - beacon.SlotsPerHistoricalRoot - 1} {
+		if _, err := GenerateAncestorProof(headerData, schema, state, targetSlot, 0); err == nil {
+			t.Errorf("GenerateAncestorProof(%d) expected error, got nil", targetSlot)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package proof
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// HeaderChain is a compact bundle linking a target header to an anchor
+// header whose root is in the EIP-4788 buffer. Headers run from the target
+// to the anchor; each header's root is the parent_root of the next one, and
+// AnchorProof proves the anchor's parent_root against the anchor block root.
+type HeaderChain struct {
+	Headers     []beacon.HeaderData `json:"headers"`
+	AnchorProof Data                `json:"anchorProof"`
+}
+
+// NewHeaderChain checks the parent linkage of headers, ordered oldest first,
+// and proves the parent_root of the last one
+func NewHeaderChain(headers []beacon.HeaderData, nextSlotTimestamp int64) (HeaderChain, error) {
+	if len(headers) < 2 {
+		return HeaderChain{}, fmt.Errorf("a header chain needs at least two headers, got %d", len(headers))
+	}
+	if err := checkParentLinks(headers); err != nil {
+		return HeaderChain{}, err
+	}
+
+	anchor := headers[len(headers)-1]
+	anchorProof, err := GenerateHeaderProof(anchor, "parent_root", nextSlotTimestamp)
+	if err != nil {
+		return HeaderChain{}, fmt.Errorf("error proving anchor parent_root: %w", err)
+	}
+
+	log.Printf("Linked slot %s to anchor slot %s through %d parent links",
+		headers[0].Slot, anchor.Slot, len(headers)-1)
+	return HeaderChain{Headers: headers, AnchorProof: anchorProof}, nil
+}
+
+// Verify re-checks a bundle offline: the parent links and that the anchor
+// proof commits to the last header
+func (c HeaderChain) Verify() error {
+	if len(c.Headers) < 2 {
+		return fmt.Errorf("a header chain needs at least two headers, got %d", len(c.Headers))
+	}
+	if err := checkParentLinks(c.Headers); err != nil {
+		return err
+	}
+
+	anchorRoot, err := c.Headers[len(c.Headers)-1].HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(c.AnchorProof.BeaconBlockRoot, "0x"+hex.EncodeToString(anchorRoot[:])) {
+		return fmt.Errorf("anchor proof is for block root %s, but the anchor header hashes to 0x%x",
+			c.AnchorProof.BeaconBlockRoot, anchorRoot)
+	}
+	return nil
+}
+
+// checkParentLinks checks that every header's root is the parent_root of
+// its successor
+func checkParentLinks(headers []beacon.HeaderData) error {
+	for i := 0; i+1 < len(headers); i++ {
+		root, err := headers[i].HashTreeRoot()
+		if err != nil {
+			return fmt.Errorf("header at slot %s: %w", headers[i].Slot, err)
+		}
+		parent := headers[i+1].ParentRoot
+		if !strings.EqualFold(parent, "0x"+hex.EncodeToString(root[:])) {
+			return fmt.Errorf("header at slot %s hashes to 0x%x, but the parent_root of slot %s is %s",
+				headers[i].Slot, root, headers[i+1].Slot, parent)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package proof
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// setupHeaderChain returns n headers at consecutive slots, each pointing at
+// the root of the one before
+func setupHeaderChain(t *testing.T, n int) []beacon.HeaderData {
+	t.Helper()
+
+	headers := make([]beacon.HeaderData, n)
+	headers[0] = setupTestHeader()
+	for i := 1; i < n; i++ {
+		root, err := headers[i-1].HashTreeRoot()
+		if err != nil {
+			t.Fatalf("HashTreeRoot() error = %v", err)
+		}
+		headers[i] = setupTestHeader()
+		headers[i].Slot = strconv.Itoa(123456 + i)
+		headers[i].ParentRoot = "0x" + hex.EncodeToString(root[:])
+	}
+	return headers
+}
+
+func TestNewHeaderChain(t *testing.T) {
+	headers := setupHeaderChain(t, 4)
+
+	chain, err := NewHeaderChain(headers, 1634567902)
+	if err != nil {
+		t.Fatalf("NewHeaderChain() error = %v", err)
+	}
+	if err := chain.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if chain.AnchorProof.FieldIndex != FieldNames["parent_root"] {
+		t.Errorf("AnchorProof.FieldIndex = %d, want parent_root", chain.AnchorProof.FieldIndex)
+	}
+	if chain.AnchorProof.FieldValue != headers[3].ParentRoot {
+		t.Errorf("AnchorProof.FieldValue = %s, want %s", chain.AnchorProof.FieldValue, headers[3].ParentRoot)
+	}
+}
+
+func TestHeaderChainErrors(t *testing.T) {
+	t.Run("Too short", func(t *testing.T) {
+		if _, err := NewHeaderChain(setupHeaderChain(t, 1), 0); err == nil {
+			t.Error("Expected error for a single header, got nil")
+		}
+	})
+
+	t.Run("Broken link", func(t *testing.T) {
+		headers := setupHeaderChain(t, 4)
+		headers[1].ProposerIndex = "43"
+		if _, err := NewHeaderChain(headers, 0); err == nil {
+			t.Error("Expected error for a broken parent link, got nil")
+		}
+	})
+
+	t.Run("Tampered bundle", func(t *testing.T) {
+		chain, err := NewHeaderChain(setupHeaderChain(t, 3), 0)
+		if err != nil {
+			t.Fatalf("NewHeaderChain() error = %v", err)
+		}
+		chain.Headers[2].BodyRoot = chain.Headers[1].BodyRoot[:len(chain.Headers[1].BodyRoot)-1] + "0"
+		if err := chain.Verify(); err == nil {
+			t.Error("Expected error for a tampered anchor, got nil")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package proof
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// GenerateExecutionRequestProof proves a single Electra execution request
+// (a deposit, withdrawal or consolidation) against the beacon block root.
+// The leaf is the hash tree root of the request container.
+func GenerateExecutionRequestProof(headerData beacon.HeaderData, block *beacon.Block, kind string, index int, nextSlotTimestamp int64) (Data, error) {
+	if !slices.Contains(beacon.ExecutionRequestKinds, kind) {
+		return Data{}, fmt.Errorf("unknown execution request kind: %s. Must be one of %v", kind, beacon.ExecutionRequestKinds)
+	}
+
+	schema, body, err := requestsBody(block)
+	if err != nil {
+		return Data{}, err
+	}
+
+	path := []string{"execution_requests", kind, strconv.Itoa(index)}
+	return generateNestedProof(headerData, beacon.BodyPathRoot, schema.BeaconBlockBody, body, path, nextSlotTimestamp)
+}
+
+// ExecutionRequestCounts returns how many requests of each kind a block carries
+func ExecutionRequestCounts(block *beacon.Block) (map[string]int, error) {
+	_, body, err := requestsBody(block)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, ok := body["execution_requests"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("block body has no execution_requests")
+	}
+
+	counts := make(map[string]int, len(beacon.ExecutionRequestKinds))
+	for _, kind := range beacon.ExecutionRequestKinds {
+		items, _ := requests[kind].([]any)
+		counts[kind] = len(items)
+	}
+	return counts, nil
+}
+
+// requestsBody returns the fork layout and body of a block that carries
+// execution requests
+func requestsBody(block *beacon.Block) (*beacon.Schema, map[string]any, error) {
+	schema, err := beacon.SchemaFor(block.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := schema.BeaconBlockBody.FieldIndex("execution_requests"); !ok {
+		return nil, nil, fmt.Errorf("execution requests require an Electra or later block, got %q", block.Version)
+	}
+	body, err := block.Body()
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, body, nil
+}
@@ -0,0 +1,163 @@
+package proof
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupElectraBlock returns an Electra block carrying one request of each kind
+// and a header whose body_root commits to it
+func setupElectraBlock(t *testing.T) (*beacon.Block, beacon.HeaderData) {
+	t.Helper()
+
+	body := ssz.Zero(beacon.BeaconBlockBodyElectraType).(map[string]any)
+	body["execution_requests"] = map[string]any{
+		"deposits": []any{map[string]any{
+			"pubkey":                 "0x" + strings.Repeat("aa", 48),
+			"withdrawal_credentials": "0x01" + strings.Repeat("00", 11) + strings.Repeat("bb", 20),
+			"amount":                 "32000000000",
+			"signature":              "0x" + strings.Repeat("cc", 96),
+			"index":                  "7",
+		}},
+		"withdrawals": []any{map[string]any{
+			"source_address":   "0x" + strings.Repeat("dd", 20),
+			"validator_pubkey": "0x" + strings.Repeat("ee", 48),
+			"amount":           "0",
+		}},
+		"consolidations": []any{map[string]any{
+			"source_address": "0x" + strings.Repeat("dd", 20),
+			"source_pubkey":  "0x" + strings.Repeat("ee", 48),
+			"target_pubkey":  "0x" + strings.Repeat("ff", 48),
+		}},
+	}
+
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyElectraType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	headerData := setupTestHeader()
+	headerData.BodyRoot = "0x" + hex.EncodeToString(bodyRoot[:])
+
+	block := &beacon.Block{
+		Version: "electra",
+		Message: map[string]any{"body": body},
+	}
+	return block, headerData
+}
+
+func TestGenerateExecutionRequestProof(t *testing.T) {
+	block, headerData := setupElectraBlock(t)
+	nextSlotTimestamp := int64(1634567890 + 12)
+
+	var header beacon.BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		t.Fatalf("FromAPIResponse() error = %v", err)
+	}
+	blockRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockHeaderType, header.Value())
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	requestTypes := map[string]*ssz.Type{
+		"deposits":       beacon.DepositRequestType,
+		"withdrawals":    beacon.WithdrawalRequestType,
+		"consolidations": beacon.ConsolidationRequestType,
+	}
+
+	for _, kind := range beacon.ExecutionRequestKinds {
+		t.Run(kind, func(t *testing.T) {
+			proofData, err := GenerateExecutionRequestProof(headerData, block, kind, 0, nextSlotTimestamp)
+			if err != nil {
+				t.Fatalf("GenerateExecutionRequestProof() error = %v", err)
+			}
+
+			if proofData.BeaconBlockRoot != "0x"+hex.EncodeToString(blockRoot[:]) {
+				t.Errorf("BeaconBlockRoot = %s, want 0x%x", proofData.BeaconBlockRoot, blockRoot)
+			}
+			if proofData.BeaconTimestamp != nextSlotTimestamp {
+				t.Errorf("BeaconTimestamp = %d, want %d", proofData.BeaconTimestamp, nextSlotTimestamp)
+			}
+
+			// The leaf must be the hash tree root of the request itself
+			body, _ := block.Body()
+			request := body["execution_requests"].(map[string]any)[kind].([]any)[0]
+			wantLeaf, err := ssz.HashTreeRoot(requestTypes[kind], request)
+			if err != nil {
+				t.Fatalf("HashTreeRoot() error = %v", err)
+			}
+			if proofData.FieldValue != "0x"+hex.EncodeToString(wantLeaf[:]) {
+				t.Errorf("FieldValue = %s, want 0x%x", proofData.FieldValue, wantLeaf)
+			}
+
+			var leaf [32]byte
+			copy(leaf[:], decodeHexForTest(t, proofData.FieldValue))
+			branch := make([][32]byte, len(proofData.MerkleProof))
+			for i, node := range proofData.MerkleProof {
+				copy(branch[i][:], decodeHexForTest(t, node))
+			}
+			if !merkle.VerifyBranch(leaf, branch, proofData.GeneralizedIndex, blockRoot) {
+				t.Errorf("proof for %s does not verify against the block root", kind)
+			}
+		})
+	}
+}
+
+func TestGenerateExecutionRequestProofErrors(t *testing.T) {
+	block, headerData := setupElectraBlock(t)
+	nextSlotTimestamp := int64(1634567890 + 12)
+
+	t.Run("Unknown kind", func(t *testing.T) {
+		if _, err := GenerateExecutionRequestProof(headerData, block, "exits", 0, nextSlotTimestamp); err == nil {
+			t.Errorf("Expected error for unknown kind, got nil")
+		}
+	})
+
+	t.Run("Index out of range", func(t *testing.T) {
+		if _, err := GenerateExecutionRequestProof(headerData, block, "deposits", 1, nextSlotTimestamp); err == nil {
+			t.Errorf("Expected error for out of range index, got nil")
+		}
+	})
+
+	t.Run("Pre-Electra block", func(t *testing.T) {
+		deneb := &beacon.Block{Version: "deneb", Message: block.Message}
+		if _, err := GenerateExecutionRequestProof(headerData, deneb, "deposits", 0, nextSlotTimestamp); err == nil {
+			t.Errorf("Expected error for a Deneb block, got nil")
+		}
+	})
+
+	t.Run("Body does not match header", func(t *testing.T) {
+		mismatched := setupTestHeader()
+		if _, err := GenerateExecutionRequestProof(mismatched, block, "deposits", 0, nextSlotTimestamp); err == nil {
+			t.Errorf("Expected error for mismatching body root, got nil")
+		}
+	})
+}
+
+func TestExecutionRequestCounts(t *testing.T) {
+	block, _ := setupElectraBlock(t)
+
+	counts, err := ExecutionRequestCounts(block)
+	if err != nil {
+		t.Fatalf("ExecutionRequestCounts() error = %v", err)
+	}
+	for _, kind := range beacon.ExecutionRequestKinds {
+		if counts[kind] != 1 {
+			t.Errorf("counts[%s] = %d, want 1", kind, counts[kind])
+		}
+	}
+}
+
+func decodeHexForTest(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(trimHexPrefix(s))
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package proof
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// blockRootsType is the layout of state.block_roots, whose root is the
+// block_summary_root of a HistoricalSummary
+var blockRootsType = ssz.Vector(beacon.Root, beacon.SlotsPerHistoricalRoot)
+
+// GenerateHistoricalProof proves the block root of a slot older than the
+// EIP-4788 buffer against a recent anchor block. The old root is proven
+// inside the block_roots of its period, whose root is the block_summary_root
+// of state.historical_summaries[summaryIndex] in the anchor state; that in
+// turn is proven up to the anchor block root.
+func GenerateHistoricalProof(anchorHeader beacon.HeaderData, schema *beacon.Schema, anchorState any, periodBlockRoots []any, targetSlot uint64, summaryIndex uint64, nextSlotTimestamp int64) (Data, error) {
+	summaryRoot, err := ssz.HashTreeRoot(blockRootsType, periodBlockRoots)
+	if err != nil {
+		return Data{}, fmt.Errorf("error hashing period block_roots: %w", err)
+	}
+
+	rootIndex := strconv.FormatUint(targetSlot%beacon.SlotsPerHistoricalRoot, 10)
+	rootGindex, err := blockRootsType.GeneralizedIndex(rootIndex)
+	if err != nil {
+		return Data{}, err
+	}
+	leaf, rootsBranch, err := ssz.Prove(blockRootsType, periodBlockRoots, rootGindex)
+	if err != nil {
+		return Data{}, fmt.Errorf("error computing block_roots proof: %w", err)
+	}
+
+	path := []string{"historical_summaries", strconv.FormatUint(summaryIndex, 10), "block_summary_root"}
+	summaryProof, err := generateNestedProof(anchorHeader, beacon.StatePathRoot, schema.BeaconState, anchorState, path, nextSlotTimestamp)
+	if err != nil {
+		return Data{}, fmt.Errorf("error proving historical summary %d: %w", summaryIndex, err)
+	}
+	if summaryProof.FieldValue != "0x"+hex.EncodeToString(summaryRoot[:]) {
+		return Data{}, fmt.Errorf("period block_roots hash to 0x%x, but historical summary %d has block_summary_root %s",
+			summaryRoot, summaryIndex, summaryProof.FieldValue)
+	}
+
+	proofData, err := extendProof(summaryProof, leaf, rootsBranch, rootGindex)
+	if err != nil {
+		return Data{}, err
+	}
+	proofData.Path += "[" + rootIndex + "]"
+
+	log.Printf("Generated historical proof for slot %d through historical summary %d (generalized index %d)",
+		targetSlot, summaryIndex, proofData.GeneralizedIndex)
+	log.Printf("Historical block root: %s", proofData.FieldValue)
+
+	return proofData, nil
+}
+
+// extendProof continues a proof below its leaf: leaf and branch prove a node
+// at gindex inside the subtree rooted at the original leaf
+func extendProof(outer Data, leaf [32]byte, branch [][32]byte, gindex uint64) (Data, error) {
+	composed, err := merkle.ConcatGeneralizedIndices(outer.GeneralizedIndex, gindex)
+	if err != nil {
+		return Data{}, err
+	}
+
+	outerRoot, err := merkle.ComputeRootFromBranch(leaf, branch, gindex)
+	if err != nil {
+		return Data{}, err
+	}
+	if "0x"+hex.EncodeToString(outerRoot[:]) != outer.FieldValue {
+		return Data{}, fmt.Errorf("inner proof root 0x%x does not match outer leaf %s", outerRoot, outer.FieldValue)
+	}
+
+	proofData := outer
+	proofData.FieldValue = "0x" + hex.EncodeToString(leaf[:])
+	proofData.MerkleProof = append(encodeBranch(branch), outer.MerkleProof...)
+	proofData.GeneralizedIndex = composed
+	return proofData, nil
+}
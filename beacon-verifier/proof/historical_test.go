@@ -0,0 +1,123 @@
+package proof
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// testBlockRoots returns a block_roots vector with a distinct root per slot
+func testBlockRoots(seed int) []any {
+	roots := make([]any, beacon.SlotsPerHistoricalRoot)
+	for i := range roots {
+		roots[i] = fmt.Sprintf("0x%064x", seed*beacon.SlotsPerHistoricalRoot+i+1)
+	}
+	return roots
+}
+
+// setupHistoricalState returns a Deneb anchor state whose second historical
+// summary commits to periodRoots, and a header committing to the state
+func setupHistoricalState(t *testing.T, periodRoots []any) (any, beacon.HeaderData) {
+	t.Helper()
+
+	summaryRoot, err := ssz.HashTreeRoot(blockRootsType, periodRoots)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	state := ssz.Zero(beacon.BeaconStateDenebType).(map[string]any)
+	state["historical_summaries"] = []any{
+		map[string]any{
+			"block_summary_root": "0x" + strings.Repeat("01", 32),
+			"state_summary_root": "0x" + strings.Repeat("02", 32),
+		},
+		map[string]any{
+			"block_summary_root": "0x" + hex.EncodeToString(summaryRoot[:]),
+			"state_summary_root": "0x" + strings.Repeat("03", 32),
+		},
+	}
+
+	stateRoot, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	headerData := setupTestHeader()
+	headerData.StateRoot = "0x" + hex.EncodeToString(stateRoot[:])
+	return state, headerData
+}
+
+func TestGenerateHistoricalProof(t *testing.T) {
+	periodRoots := testBlockRoots(1)
+	state, headerData := setupHistoricalState(t, periodRoots)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	targetSlot := uint64(5*beacon.SlotsPerHistoricalRoot + 777)
+	proofData, err := GenerateHistoricalProof(headerData, schema, state, periodRoots, targetSlot, 1, 1634567902)
+	if err != nil {
+		t.Fatalf("GenerateHistoricalProof() error = %v", err)
+	}
+
+	if proofData.FieldValue != periodRoots[777] {
+		t.Errorf("FieldValue = %s, want %s", proofData.FieldValue, periodRoots[777])
+	}
+	if proofData.Path != "state.historical_summaries[1].block_summary_root[777]" {
+		t.Errorf("Path = %s", proofData.Path)
+	}
+
+	summaryGindex, err := schema.GeneralizedIndex("state", "historical_summaries", "1", "block_summary_root")
+	if err != nil {
+		t.Fatalf("GeneralizedIndex() error = %v", err)
+	}
+	wantGindex, _ := merkle.ConcatGeneralizedIndices(summaryGindex, beacon.SlotsPerHistoricalRoot+777)
+	if proofData.GeneralizedIndex != wantGindex {
+		t.Errorf("GeneralizedIndex = %d, want %d", proofData.GeneralizedIndex, wantGindex)
+	}
+
+	var header beacon.BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		t.Fatalf("FromAPIResponse() error = %v", err)
+	}
+	blockRoot, err := header.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	var leaf [32]byte
+	copy(leaf[:], decodeHexForTest(t, proofData.FieldValue))
+	branch := make([][32]byte, len(proofData.MerkleProof))
+	for i, node := range proofData.MerkleProof {
+		copy(branch[i][:], decodeHexForTest(t, node))
+	}
+	if !merkle.VerifyBranch(leaf, branch, proofData.GeneralizedIndex, blockRoot) {
+		t.Error("historical proof does not verify against the anchor block root")
+	}
+}
+
+func TestGenerateHistoricalProofErrors(t *testing.T) {
+	periodRoots := testBlockRoots(1)
+	state, headerData := setupHistoricalState(t, periodRoots)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	t.Run("Wrong period", func(t *testing.T) {
+		if _, err := GenerateHistoricalProof(headerData, schema, state, testBlockRoots(2), 10, 1, 0); err == nil {
+			t.Error("Expected error for block_roots of another period, got nil")
+		}
+	})
+
+	t.Run("Summary not yet recorded", func(t *testing.T) {
+		if _, err := GenerateHistoricalProof(headerData, schema, state, periodRoots, 10, 2, 0); err == nil {
+			t.Error("Expected error for a missing summary, got nil")
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// Sources holds the decoded block body and beacon state that path proofs
+// descend into; either may be nil when no requested path needs it
+type Sources struct {
+	Body  any
+	State any
+}
+
+// GeneratePathProof generates a proof for a path relative to the beacon block
+// root. Plain header fields ("slot") are proven as before; paths under "body"
+// or "state" ("body.execution_payload.fee_recipient",
+// "state.validators[123].effective_balance") are proven inside the body or
+// state and composed with the header branch.
+func GeneratePathProof(headerData beacon.HeaderData, schema *beacon.Schema, sources Sources, path string, nextSlotTimestamp int64) (Data, error) {
+	elems, err := ssz.ParsePath(path)
+	if err != nil {
+		return Data{}, err
+	}
+
+	// Resolve the whole path up front so typos fail before anything is hashed
+	if _, err := schema.Locate(elems...); err != nil {
+		return Data{}, fmt.Errorf("error resolving path %q for %s: %w", path, schema.Fork, err)
+	}
+
+	var value any
+	switch elems[0] {
+	case beacon.BodyPathRoot:
+		value = sources.Body
+	case beacon.StatePathRoot:
+		value = sources.State
+	default:
+		return GenerateHeaderProof(headerData, elems[0], nextSlotTimestamp)
+	}
+	if value == nil {
+		return Data{}, fmt.Errorf("no %s available to prove %q", elems[0], path)
+	}
+
+	return generateNestedProof(headerData, elems[0], schema.Container(elems[0]), value, elems[1:], nextSlotTimestamp)
+}
+
+// generateNestedProof proves a path inside the block body or state and
+// extends the branch through the header's body_root or state_root up to the
+// beacon block root
+func generateNestedProof(headerData beacon.HeaderData, pathRoot string, innerType *ssz.Type, value any, path []string, nextSlotTimestamp int64) (Data, error) {
+	var header beacon.BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		return Data{}, fmt.Errorf("error processing header data: %w", err)
+	}
+
+	rootField := beacon.PathRootFields[pathRoot]
+	expectedRoot := header.BodyRoot
+	if pathRoot == beacon.StatePathRoot {
+		expectedRoot = header.StateRoot
+	}
+
+	loc, err := innerType.Locate(path...)
+	if err != nil {
+		return Data{}, fmt.Errorf("error resolving %s path: %w", pathRoot, err)
+	}
+	// Locate only checks limits; make sure the value is actually present
+	if _, err := ssz.Value(innerType, value, path...); err != nil {
+		return Data{}, fmt.Errorf("error reading %s path: %w", pathRoot, err)
+	}
+
+	leaf, innerBranch, err := ssz.Prove(innerType, value, loc.GeneralizedIndex)
+	if err != nil {
+		return Data{}, fmt.Errorf("error computing %s proof: %w", pathRoot, err)
+	}
+
+	innerRoot, err := merkle.ComputeRootFromBranch(leaf, innerBranch, loc.GeneralizedIndex)
+	if err != nil {
+		return Data{}, fmt.Errorf("error computing %s root: %w", pathRoot, err)
+	}
+	if !bytes.Equal(innerRoot[:], expectedRoot) {
+		return Data{}, fmt.Errorf("%s root 0x%x does not match header %s 0x%x",
+			pathRoot, innerRoot, rootField, expectedRoot)
+	}
+
+	rootIndex := FieldNames[rootField]
+	rootGindex, err := beacon.BeaconBlockHeaderType.GeneralizedIndex(rootField)
+	if err != nil {
+		return Data{}, err
+	}
+	_, headerBranch, err := ssz.Prove(beacon.BeaconBlockHeaderType, header.Value(), rootGindex)
+	if err != nil {
+		return Data{}, fmt.Errorf("error computing header proof: %w", err)
+	}
+
+	gindex, err := merkle.ConcatGeneralizedIndices(rootGindex, loc.GeneralizedIndex)
+	if err != nil {
+		return Data{}, err
+	}
+	branch := append(innerBranch, headerBranch...)
+	blockRoot, err := merkle.ComputeRootFromBranch(leaf, branch, gindex)
+	if err != nil {
+		return Data{}, fmt.Errorf("error computing block root: %w", err)
+	}
+
+	proofData := Data{
+		BeaconTimestamp:  nextSlotTimestamp,
+		BeaconBlockRoot:  "0x" + hex.EncodeToString(blockRoot[:]),
+		FieldIndex:       rootIndex,
+		FieldValue:       "0x" + hex.EncodeToString(leaf[:]),
+		MerkleProof:      encodeBranch(branch),
+		GeneralizedIndex: gindex,
+		Path:             ssz.FormatPath(append([]string{pathRoot}, path...)),
+	}
+
+	log.Printf("Generated proof for '%s' (generalized index %d)", proofData.Path, gindex)
+	if loc.Packed {
+		log.Printf("Value is packed at byte offset %d of the leaf chunk", loc.ByteOffset)
+	}
+	log.Printf("Leaf value: %s", proofData.FieldValue)
+	log.Printf("Header root: %s", proofData.BeaconBlockRoot)
+
+	return proofData, nil
+}
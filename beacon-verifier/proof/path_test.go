@@ -0,0 +1,184 @@
+package proof
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupDenebSources returns a Deneb body and state together with a header
+// whose body_root and state_root commit to them
+func setupDenebSources(t *testing.T) (Sources, beacon.HeaderData) {
+	t.Helper()
+
+	body := ssz.Zero(beacon.BeaconBlockBodyDenebType).(map[string]any)
+	body["execution_payload"].(map[string]any)["fee_recipient"] = "0x" + strings.Repeat("ab", 20)
+	body["execution_payload"].(map[string]any)["block_number"] = "1234"
+	body["blob_kzg_commitments"] = []any{
+		"0x" + strings.Repeat("11", 48),
+		"0x" + strings.Repeat("22", 48),
+		"0x" + strings.Repeat("33", 48),
+	}
+
+	state := ssz.Zero(beacon.BeaconStateDenebType).(map[string]any)
+	validators := make([]any, 124)
+	balances := make([]any, 124)
+	for i := range validators {
+		validator := ssz.Zero(beacon.ValidatorType).(map[string]any)
+		validator["effective_balance"] = "32000000000"
+		validators[i] = validator
+		balances[i] = "32000000000"
+	}
+	validators[123].(map[string]any)["effective_balance"] = "31000000000"
+	state["validators"] = validators
+	state["balances"] = balances
+
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyDenebType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot(body) error = %v", err)
+	}
+	stateRoot, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot(state) error = %v", err)
+	}
+
+	headerData := setupTestHeader()
+	headerData.BodyRoot = "0x" + hex.EncodeToString(bodyRoot[:])
+	headerData.StateRoot = "0x" + hex.EncodeToString(stateRoot[:])
+	return Sources{Body: body, State: state}, headerData
+}
+
+func TestGeneratePathProof(t *testing.T) {
+	sources, headerData := setupDenebSources(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	nextSlotTimestamp := int64(1634567890 + 12)
+
+	var header beacon.BlockHeader
+	if err := header.FromAPIResponse(headerData); err != nil {
+		t.Fatalf("FromAPIResponse() error = %v", err)
+	}
+	blockRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockHeaderType, header.Value())
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		leaf string
+	}{
+		{"body.execution_payload.fee_recipient", "0x" + strings.Repeat("ab", 20) + strings.Repeat("00", 12)},
+		{"body.execution_payload.block_number", "0xd204" + strings.Repeat("00", 30)},
+		{"state.validators[123].effective_balance", "0x0076be3707" + strings.Repeat("00", 27)},
+		// balances are packed four to a chunk
+		{"state.balances[5]", "0x" + strings.Repeat("0040597307000000", 4)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			proofData, err := GeneratePathProof(headerData, schema, sources, tt.path, nextSlotTimestamp)
+			if err != nil {
+				t.Fatalf("GeneratePathProof() error = %v", err)
+			}
+			if proofData.Path != tt.path {
+				t.Errorf("Path = %s, want %s", proofData.Path, tt.path)
+			}
+			if proofData.FieldValue != tt.leaf {
+				t.Errorf("FieldValue = %s, want %s", proofData.FieldValue, tt.leaf)
+			}
+
+			wantGindex, err := schema.GeneralizedIndex(mustParsePath(t, tt.path)...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			if proofData.GeneralizedIndex != wantGindex {
+				t.Errorf("GeneralizedIndex = %d, want %d", proofData.GeneralizedIndex, wantGindex)
+			}
+
+			var leaf [32]byte
+			copy(leaf[:], decodeHexForTest(t, proofData.FieldValue))
+			branch := make([][32]byte, len(proofData.MerkleProof))
+			for i, node := range proofData.MerkleProof {
+				copy(branch[i][:], decodeHexForTest(t, node))
+			}
+			if !merkle.VerifyBranch(leaf, branch, proofData.GeneralizedIndex, blockRoot) {
+				t.Errorf("proof for %s does not verify against the block root", tt.path)
+			}
+		})
+	}
+
+	t.Run("blob commitment", func(t *testing.T) {
+		proofData, err := GeneratePathProof(headerData, schema, sources, "body.blob_kzg_commitments[2]", nextSlotTimestamp)
+		if err != nil {
+			t.Fatalf("GeneratePathProof() error = %v", err)
+		}
+		want, err := ssz.HashTreeRoot(beacon.KZGCommitment, "0x"+strings.Repeat("33", 48))
+		if err != nil {
+			t.Fatalf("HashTreeRoot() error = %v", err)
+		}
+		if proofData.FieldValue != "0x"+hex.EncodeToString(want[:]) {
+			t.Errorf("FieldValue = %s, want 0x%x", proofData.FieldValue, want)
+		}
+	})
+
+	t.Run("header field", func(t *testing.T) {
+		proofData, err := GeneratePathProof(headerData, schema, Sources{}, "proposer_index", nextSlotTimestamp)
+		if err != nil {
+			t.Fatalf("GeneratePathProof() error = %v", err)
+		}
+		if proofData.FieldIndex != FieldNames["proposer_index"] || proofData.GeneralizedIndex != 0 {
+			t.Errorf("header field should use verifyHeaderField, got index %d / gindex %d",
+				proofData.FieldIndex, proofData.GeneralizedIndex)
+		}
+	})
+}
+
+func TestGeneratePathProofErrors(t *testing.T) {
+	sources, headerData := setupDenebSources(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		sources Sources
+		wantErr string
+	}{
+		{"malformed", "body.blob_kzg_commitments[", sources, "unterminated index"},
+		{"unknown header field", "graffiti", sources, "valid fields: slot, proposer_index"},
+		{"unknown body field", "body.execution_payload.fee", sources, "valid fields: parent_hash, fee_recipient"},
+		{"Electra field on Deneb", "body.execution_requests", sources, "valid fields: randao_reveal"},
+		{"missing element", "body.blob_kzg_commitments[3]", sources, "out of range"},
+		{"no state", "state.slot", Sources{Body: sources.Body}, "no state available"},
+		{"state does not match header", "state.slot", Sources{State: ssz.Zero(beacon.BeaconStateDenebType)}, "does not match header state_root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GeneratePathProof(headerData, schema, tt.sources, tt.path, 0)
+			if err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func mustParsePath(t *testing.T, path string) []string {
+	t.Helper()
+	elems, err := ssz.ParsePath(path)
+	if err != nil {
+		t.Fatalf("ParsePath(%q) error = %v", path, err)
+	}
+	return elems
+}
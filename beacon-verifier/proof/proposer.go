@@ -0,0 +1,152 @@
+package proof
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/bls"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// ProposerSignature backs a header with its proposer's signature. The
+// proposer's public key is proven from state.validators under KeyBlockRoot,
+// so checking the bundle offline needs only the fork schedule and trust in
+// that root.
+type ProposerSignature struct {
+	Header      beacon.HeaderData `json:"header"`
+	Domain      string            `json:"domain"`
+	SigningRoot string            `json:"signingRoot"`
+	Pubkey      string            `json:"pubkey"`
+	PubkeyProof Data              `json:"pubkeyProof"`
+}
+
+// proposerPubkeyPath locates a validator's public key from the block root
+func proposerPubkeyPath(index string) string {
+	return fmt.Sprintf("%s.validators[%s].pubkey", beacon.StatePathRoot, index)
+}
+
+// GenerateProposerSignature bundles the signature of headerData, which must
+// carry it, with its signing root and a proof of the proposer's public key
+// from keyState, the state committed to by keyHeader. Any state after the
+// proposer's activation will do; validators never change their key.
+func GenerateProposerSignature(headerData beacon.HeaderData, schedule beacon.ForkSchedule, keyHeader beacon.HeaderData, keySchema *beacon.Schema, keyState any) (ProposerSignature, error) {
+	if headerData.Signature == "" {
+		return ProposerSignature{}, fmt.Errorf("header at slot %s has no signature", headerData.Slot)
+	}
+	domain, signingRoot, err := proposerSigningRoot(headerData, schedule)
+	if err != nil {
+		return ProposerSignature{}, err
+	}
+
+	path := proposerPubkeyPath(headerData.ProposerIndex)
+	pubkeyProof, err := GeneratePathProof(keyHeader, keySchema, Sources{State: keyState}, path, 0)
+	if err != nil {
+		return ProposerSignature{}, fmt.Errorf("error proving proposer public key: %w", err)
+	}
+	elems, err := ssz.ParsePath(path)
+	if err != nil {
+		return ProposerSignature{}, err
+	}
+	pubkey, err := ssz.Value(keySchema.BeaconState, keyState, elems[1:]...)
+	if err != nil {
+		return ProposerSignature{}, fmt.Errorf("error reading proposer public key: %w", err)
+	}
+
+	log.Printf("Proved public key of proposer %s from the state at slot %s", headerData.ProposerIndex, keyHeader.Slot)
+	return ProposerSignature{
+		Header:      headerData,
+		Domain:      "0x" + hex.EncodeToString(domain[:]),
+		SigningRoot: "0x" + hex.EncodeToString(signingRoot[:]),
+		Pubkey:      fmt.Sprint(pubkey),
+		PubkeyProof: pubkeyProof,
+	}, nil
+}
+
+// Verify recomputes the signing root of the header, checks the public key
+// proof against keySchema, the layout of the state it was proven from, and
+// verifies the signature
+func (p ProposerSignature) Verify(schedule beacon.ForkSchedule, keySchema *beacon.Schema) error {
+	domain, signingRoot, err := proposerSigningRoot(p.Header, schedule)
+	if err != nil {
+		return err
+	}
+	if p.Domain != "0x"+hex.EncodeToString(domain[:]) {
+		return fmt.Errorf("domain %s is not the proposer domain 0x%x", p.Domain, domain)
+	}
+	if p.SigningRoot != "0x"+hex.EncodeToString(signingRoot[:]) {
+		return fmt.Errorf("signing root %s does not match the header (0x%x)", p.SigningRoot, signingRoot)
+	}
+
+	elems, err := ssz.ParsePath(proposerPubkeyPath(p.Header.ProposerIndex))
+	if err != nil {
+		return err
+	}
+	gindex, err := keySchema.GeneralizedIndex(elems...)
+	if err != nil {
+		return err
+	}
+	if p.PubkeyProof.GeneralizedIndex != gindex {
+		return fmt.Errorf("public key proof has generalized index %d, want %d for validator %s",
+			p.PubkeyProof.GeneralizedIndex, gindex, p.Header.ProposerIndex)
+	}
+	leaf, err := ssz.HashTreeRoot(beacon.BLSPubkey, p.Pubkey)
+	if err != nil {
+		return fmt.Errorf("error hashing public key: %w", err)
+	}
+	if p.PubkeyProof.FieldValue != "0x"+hex.EncodeToString(leaf[:]) {
+		return fmt.Errorf("public key proof leaf %s does not commit to %s", p.PubkeyProof.FieldValue, p.Pubkey)
+	}
+	if err := p.PubkeyProof.VerifyOffline(); err != nil {
+		return fmt.Errorf("public key proof: %w", err)
+	}
+
+	pkBytes, err := hex.DecodeString(trimHexPrefix(p.Pubkey))
+	if err != nil {
+		return fmt.Errorf("error decoding public key: %w", err)
+	}
+	pubkey, err := bls.ParsePublicKey(pkBytes)
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(trimHexPrefix(p.Header.Signature))
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %w", err)
+	}
+	if err := bls.Verify(pubkey, signingRoot[:], signature); err != nil {
+		return fmt.Errorf("proposer %s signature over slot %s: %w", p.Header.ProposerIndex, p.Header.Slot, err)
+	}
+	return nil
+}
+
+// KeyBlockRoot is the block root the public key is proven against
+func (p ProposerSignature) KeyBlockRoot() string {
+	return p.PubkeyProof.BeaconBlockRoot
+}
+
+// proposerSigningRoot returns the proposer domain at the header's epoch and
+// the root the proposer signed
+func proposerSigningRoot(headerData beacon.HeaderData, schedule beacon.ForkSchedule) ([32]byte, [32]byte, error) {
+	slot, err := strconv.ParseUint(headerData.Slot, 10, 64)
+	if err != nil {
+		return [32]byte{}, [32]byte{}, fmt.Errorf("error parsing slot: %w", err)
+	}
+	if schedule.SlotsPerEpoch == 0 {
+		return [32]byte{}, [32]byte{}, fmt.Errorf("fork schedule has no slots per epoch")
+	}
+	domain, err := schedule.DomainAtEpoch(beacon.DomainBeaconProposer, slot/schedule.SlotsPerEpoch)
+	if err != nil {
+		return [32]byte{}, [32]byte{}, err
+	}
+	root, err := headerData.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, [32]byte{}, err
+	}
+	signingRoot, err := beacon.ComputeSigningRoot(root, domain)
+	if err != nil {
+		return [32]byte{}, [32]byte{}, err
+	}
+	return domain, signingRoot, nil
+}
@@ -0,0 +1,155 @@
+package proof
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/bls"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// testSchedule is a single-fork chain, so any slot has a signing domain
+var testSchedule = beacon.NewForkSchedule(beacon.SlotsPerEpoch,
+	beacon.ForkEpoch{Name: beacon.Deneb, Epoch: 0, Version: [4]byte{0x04, 0x00, 0x00, 0x00}},
+).WithGenesisValidatorsRoot("0x" + strings.Repeat("5a", 32))
+
+// testProposerKey is the key of proposer 42 in setupKeyState
+func testProposerKey(t *testing.T) *bls.SecretKey {
+	t.Helper()
+	sk, err := bls.NewSecretKey([]byte{0x42, 0x42})
+	if err != nil {
+		t.Fatalf("NewSecretKey() error = %v", err)
+	}
+	return sk
+}
+
+// setupKeyState returns a state holding the public key of proposer 42
+func setupKeyState(t *testing.T) map[string]any {
+	t.Helper()
+	state := ssz.Zero(beacon.BeaconStateDenebType).(map[string]any)
+	validators := make([]any, 50)
+	for i := range validators {
+		validators[i] = ssz.Zero(beacon.ValidatorType)
+	}
+	validators[42].(map[string]any)["pubkey"] = "0x" + hex.EncodeToString(testProposerKey(t).PublicKey().Bytes())
+	state["validators"] = validators
+	return state
+}
+
+// signHeader signs a header as its proposer would under testSchedule
+func signHeader(t *testing.T, sk *bls.SecretKey, headerData beacon.HeaderData) beacon.HeaderData {
+	t.Helper()
+	_, signingRoot, err := proposerSigningRoot(headerData, testSchedule)
+	if err != nil {
+		t.Fatalf("proposerSigningRoot() error = %v", err)
+	}
+	signature, err := sk.Sign(signingRoot[:])
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	headerData.Signature = "0x" + hex.EncodeToString(signature)
+	return headerData
+}
+
+// setupSignedHeader returns a header signed by proposer 42 and a key header
+// whose state holds the proposer's public key
+func setupSignedHeader(t *testing.T) (beacon.HeaderData, beacon.HeaderData, any) {
+	t.Helper()
+	state := setupKeyState(t)
+	stateRoot, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot(state) error = %v", err)
+	}
+	keyHeader := setupTestHeader()
+	keyHeader.Slot = "123000"
+	keyHeader.StateRoot = "0x" + hex.EncodeToString(stateRoot[:])
+
+	return signHeader(t, testProposerKey(t), setupTestHeader()), keyHeader, state
+}
+
+func TestProposerSignature(t *testing.T) {
+	headerData, keyHeader, state := setupSignedHeader(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	bundle, err := GenerateProposerSignature(headerData, testSchedule, keyHeader, schema, state)
+	if err != nil {
+		t.Fatalf("GenerateProposerSignature() error = %v", err)
+	}
+	if err := bundle.Verify(testSchedule, schema); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	keyRoot, err := keyHeader.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	if want := "0x" + hex.EncodeToString(keyRoot[:]); bundle.KeyBlockRoot() != want {
+		t.Errorf("KeyBlockRoot() = %s, want %s", bundle.KeyBlockRoot(), want)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(p *ProposerSignature)
+	}{
+		{"Header field", func(p *ProposerSignature) { p.Header.BodyRoot = p.Header.ParentRoot }},
+		{"Signing root", func(p *ProposerSignature) { p.SigningRoot = p.Domain }},
+		{"Other proposer", func(p *ProposerSignature) { p.Header.ProposerIndex = "41" }},
+		{"Pubkey", func(p *ProposerSignature) {
+			other, _ := bls.NewSecretKey([]byte{7})
+			p.Pubkey = "0x" + hex.EncodeToString(other.PublicKey().Bytes())
+		}},
+		{"Pubkey proof", func(p *ProposerSignature) {
+			p.PubkeyProof.MerkleProof = append([]string{}, p.PubkeyProof.MerkleProof...)
+			p.PubkeyProof.MerkleProof[0] = p.Domain
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := bundle
+			tt.tamper(&tampered)
+			if err := tampered.Verify(testSchedule, schema); err == nil {
+				t.Error("Verify() expected error, got nil")
+			}
+		})
+	}
+
+	t.Run("Wrong fork", func(t *testing.T) {
+		other := beacon.NewForkSchedule(beacon.SlotsPerEpoch,
+			beacon.ForkEpoch{Name: beacon.Deneb, Epoch: 0, Version: [4]byte{0x04, 0x00, 0x00, 0x01}},
+		).WithGenesisValidatorsRoot("0x" + strings.Repeat("5a", 32))
+		bundle, err := GenerateProposerSignature(headerData, other, keyHeader, schema, state)
+		if err != nil {
+			t.Fatalf("GenerateProposerSignature() error = %v", err)
+		}
+		if err := bundle.Verify(other, schema); !errors.Is(err, bls.ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+}
+
+func TestGenerateProposerSignatureErrors(t *testing.T) {
+	headerData, keyHeader, state := setupSignedHeader(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	unsigned := headerData
+	unsigned.Signature = ""
+	if _, err := GenerateProposerSignature(unsigned, testSchedule, keyHeader, schema, state); err == nil {
+		t.Error("GenerateProposerSignature() without signature expected error, got nil")
+	}
+	if _, err := GenerateProposerSignature(headerData, testSchedule, headerData, schema, state); err == nil {
+		t.Error("GenerateProposerSignature() with a foreign state expected error, got nil")
+	}
+	unknown := headerData
+	unknown.ProposerIndex = "50"
+	if _, err := GenerateProposerSignature(unknown, testSchedule, keyHeader, schema, state); err == nil {
+		t.Error("GenerateProposerSignature() for a missing validator expected error, got nil")
+	}
+}
@@ -0,0 +1,48 @@
+package proof
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// SkippedSlotProof shows that a slot was empty: a later state's block_roots
+// records the same root for it as for the slot before, which is only possible
+// when no block was proposed in between
+type SkippedSlotProof struct {
+	Slot     uint64 `json:"slot"`
+	Root     string `json:"root"`
+	Previous Data   `json:"previous"`
+	Skipped  Data   `json:"skipped"`
+}
+
+// GenerateSkippedSlotProof proves that slot was skipped with two block_roots
+// branches from the anchor state, for slot-1 and slot
+func GenerateSkippedSlotProof(anchorHeader beacon.HeaderData, schema *beacon.Schema, anchorState any, slot uint64, nextSlotTimestamp int64) (SkippedSlotProof, error) {
+	if slot == 0 {
+		return SkippedSlotProof{}, fmt.Errorf("the genesis slot cannot be skipped")
+	}
+
+	previous, err := GenerateAncestorProof(anchorHeader, schema, anchorState, slot-1, nextSlotTimestamp)
+	if err != nil {
+		return SkippedSlotProof{}, err
+	}
+	skipped, err := GenerateAncestorProof(anchorHeader, schema, anchorState, slot, nextSlotTimestamp)
+	if err != nil {
+		return SkippedSlotProof{}, err
+	}
+
+	if previous.FieldValue != skipped.FieldValue {
+		return SkippedSlotProof{}, fmt.Errorf("slot %d was not skipped: block_roots holds %s for it and %s for slot %d",
+			slot, skipped.FieldValue, previous.FieldValue, slot-1)
+	}
+
+	log.Printf("Slot %d repeats the block root of slot %d: %s", slot, slot-1, skipped.FieldValue)
+	return SkippedSlotProof{
+		Slot:     slot,
+		Root:     skipped.FieldValue,
+		Previous: previous,
+		Skipped:  skipped,
+	}, nil
+}
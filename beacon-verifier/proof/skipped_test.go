@@ -0,0 +1,42 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+func TestGenerateSkippedSlotProof(t *testing.T) {
+	// Slot 123400 was skipped, so its entry repeats the root of slot 123399
+	blockRoots := testBlockRoots(0)
+	skippedIndex := 123400 % beacon.SlotsPerHistoricalRoot
+	blockRoots[skippedIndex] = blockRoots[skippedIndex-1]
+
+	state, headerData := setupAnchorState(t, blockRoots)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	skipped, err := GenerateSkippedSlotProof(headerData, schema, state, 123400, 1634567902)
+	if err != nil {
+		t.Fatalf("GenerateSkippedSlotProof() error = %v", err)
+	}
+	if skipped.Root != blockRoots[skippedIndex-1] {
+		t.Errorf("Root = %s, want %s", skipped.Root, blockRoots[skippedIndex-1])
+	}
+	if skipped.Previous.GeneralizedIndex+1 != skipped.Skipped.GeneralizedIndex {
+		t.Errorf("branches should prove adjacent entries, got %d and %d",
+			skipped.Previous.GeneralizedIndex, skipped.Skipped.GeneralizedIndex)
+	}
+	if skipped.Previous.BeaconBlockRoot != skipped.Skipped.BeaconBlockRoot {
+		t.Error("both branches should lead to the anchor block root")
+	}
+
+	if _, err := GenerateSkippedSlotProof(headerData, schema, state, 123401, 0); err == nil {
+		t.Error("Expected error for a filled slot, got nil")
+	}
+	if _, err := GenerateSkippedSlotProof(headerData, schema, state, 0, 0); err == nil {
+		t.Error("Expected error for the genesis slot, got nil")
+	}
+}
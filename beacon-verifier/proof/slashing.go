@@ -0,0 +1,126 @@
+package proof
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// ProposerSlashingEvidence shows that a proposer slashing is valid and,
+// optionally, that it was included in a block. Everything is proven against
+// one anchor block root: the proposer's public key from the anchor's state
+// and the slashing from its body.proposer_slashings.
+type ProposerSlashingEvidence struct {
+	BlockRoot     string            `json:"blockRoot"`
+	SignedHeader1 ProposerSignature `json:"signedHeader1"`
+	SignedHeader2 ProposerSignature `json:"signedHeader2"`
+	// Inclusion proves body.proposer_slashings[InclusionIndex] of the anchor
+	// block; it is nil for slashings that were not included there
+	Inclusion      *Data `json:"inclusion,omitempty"`
+	InclusionIndex int   `json:"inclusionIndex,omitempty"`
+}
+
+// Slashing returns the slashing the evidence is about
+func (e ProposerSlashingEvidence) Slashing() beacon.ProposerSlashing {
+	return beacon.ProposerSlashing{Header1: e.SignedHeader1.Header, Header2: e.SignedHeader2.Header}
+}
+
+// GenerateProposerSlashingEvidence checks a slashing and proves the
+// proposer's public key from anchorState, the state committed to by
+// anchorHeader
+func GenerateProposerSlashingEvidence(slashing beacon.ProposerSlashing, schedule beacon.ForkSchedule, anchorHeader beacon.HeaderData, schema *beacon.Schema, anchorState any) (ProposerSlashingEvidence, error) {
+	if err := slashing.Check(); err != nil {
+		return ProposerSlashingEvidence{}, fmt.Errorf("invalid proposer slashing: %w", err)
+	}
+	signed1, err := GenerateProposerSignature(slashing.Header1, schedule, anchorHeader, schema, anchorState)
+	if err != nil {
+		return ProposerSlashingEvidence{}, fmt.Errorf("signed_header_1: %w", err)
+	}
+	signed2, err := GenerateProposerSignature(slashing.Header2, schedule, anchorHeader, schema, anchorState)
+	if err != nil {
+		return ProposerSlashingEvidence{}, fmt.Errorf("signed_header_2: %w", err)
+	}
+
+	log.Printf("Proposer %s signed two headers at slot %s", slashing.Header1.ProposerIndex, slashing.Header1.Slot)
+	return ProposerSlashingEvidence{
+		BlockRoot:     signed1.KeyBlockRoot(),
+		SignedHeader1: signed1,
+		SignedHeader2: signed2,
+	}, nil
+}
+
+// ProveInclusion proves that the anchor block carries the slashing at
+// body.proposer_slashings[index]
+func (e *ProposerSlashingEvidence) ProveInclusion(anchorHeader beacon.HeaderData, schema *beacon.Schema, body any, index int) error {
+	inclusion, err := GeneratePathProof(anchorHeader, schema, Sources{Body: body}, slashingPath(index), 0)
+	if err != nil {
+		return fmt.Errorf("error proving slashing inclusion: %w", err)
+	}
+	if inclusion.BeaconBlockRoot != e.BlockRoot {
+		return fmt.Errorf("block %s is not the anchor block %s", inclusion.BeaconBlockRoot, e.BlockRoot)
+	}
+	leaf, err := e.Slashing().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if inclusion.FieldValue != "0x"+hex.EncodeToString(leaf[:]) {
+		return fmt.Errorf("block carries a different slashing at proposer_slashings[%d]", index)
+	}
+	e.Inclusion = &inclusion
+	e.InclusionIndex = index
+	return nil
+}
+
+// Verify checks the slashing conditions, both signatures and public key
+// proofs, and the inclusion proof when present, all against BlockRoot.
+// schema is the layout of the anchor block.
+func (e ProposerSlashingEvidence) Verify(schedule beacon.ForkSchedule, schema *beacon.Schema) error {
+	if err := e.Slashing().Check(); err != nil {
+		return fmt.Errorf("invalid proposer slashing: %w", err)
+	}
+	for i, signed := range []ProposerSignature{e.SignedHeader1, e.SignedHeader2} {
+		if signed.KeyBlockRoot() != e.BlockRoot {
+			return fmt.Errorf("signed_header_%d: public key is proven against %s, not %s", i+1, signed.KeyBlockRoot(), e.BlockRoot)
+		}
+		if err := signed.Verify(schedule, schema); err != nil {
+			return fmt.Errorf("signed_header_%d: %w", i+1, err)
+		}
+	}
+	if e.Inclusion == nil {
+		return nil
+	}
+
+	if e.Inclusion.BeaconBlockRoot != e.BlockRoot {
+		return fmt.Errorf("inclusion is proven against %s, not %s", e.Inclusion.BeaconBlockRoot, e.BlockRoot)
+	}
+	elems, err := ssz.ParsePath(slashingPath(e.InclusionIndex))
+	if err != nil {
+		return err
+	}
+	gindex, err := schema.GeneralizedIndex(elems...)
+	if err != nil {
+		return err
+	}
+	if e.Inclusion.GeneralizedIndex != gindex {
+		return fmt.Errorf("inclusion proof has generalized index %d, want %d", e.Inclusion.GeneralizedIndex, gindex)
+	}
+	leaf, err := e.Slashing().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if e.Inclusion.FieldValue != "0x"+hex.EncodeToString(leaf[:]) {
+		return fmt.Errorf("inclusion proof leaf %s is not the slashing root 0x%x", e.Inclusion.FieldValue, leaf)
+	}
+	if err := e.Inclusion.VerifyOffline(); err != nil {
+		return fmt.Errorf("inclusion proof: %w", err)
+	}
+	return nil
+}
+
+// slashingPath locates a proposer slashing from the block root
+func slashingPath(index int) string {
+	return fmt.Sprintf("%s.proposer_slashings[%d]", beacon.BodyPathRoot, index)
+}
@@ -0,0 +1,122 @@
+package proof
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupSlashingBlock returns a slashing of proposer 42 and an anchor block
+// whose state holds the proposer's key and whose body includes the slashing
+// at proposer_slashings[1]
+func setupSlashingBlock(t *testing.T) (beacon.ProposerSlashing, beacon.HeaderData, any, any) {
+	t.Helper()
+	sk := testProposerKey(t)
+	header2 := setupTestHeader()
+	header2.BodyRoot = "0x" + strings.Repeat("cd", 32)
+	slashing := beacon.ProposerSlashing{
+		Header1: signHeader(t, sk, setupTestHeader()),
+		Header2: signHeader(t, sk, header2),
+	}
+
+	value, err := slashing.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	body := ssz.Zero(beacon.BeaconBlockBodyDenebType).(map[string]any)
+	body["proposer_slashings"] = []any{ssz.Zero(beacon.ProposerSlashingType), value}
+	state := setupKeyState(t)
+
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyDenebType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot(body) error = %v", err)
+	}
+	stateRoot, err := ssz.HashTreeRoot(beacon.BeaconStateDenebType, state)
+	if err != nil {
+		t.Fatalf("HashTreeRoot(state) error = %v", err)
+	}
+	anchor := setupTestHeader()
+	anchor.Slot = "123460"
+	anchor.BodyRoot = "0x" + hex.EncodeToString(bodyRoot[:])
+	anchor.StateRoot = "0x" + hex.EncodeToString(stateRoot[:])
+	return slashing, anchor, body, state
+}
+
+func TestProposerSlashingEvidence(t *testing.T) {
+	slashing, anchor, body, state := setupSlashingBlock(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	evidence, err := GenerateProposerSlashingEvidence(slashing, testSchedule, anchor, schema, state)
+	if err != nil {
+		t.Fatalf("GenerateProposerSlashingEvidence() error = %v", err)
+	}
+	if err := evidence.Verify(testSchedule, schema); err != nil {
+		t.Fatalf("Verify() without inclusion error = %v", err)
+	}
+
+	if err := evidence.ProveInclusion(anchor, schema, body, 0); err == nil {
+		t.Error("ProveInclusion() of another slashing expected error, got nil")
+	}
+	if err := evidence.ProveInclusion(anchor, schema, body, 1); err != nil {
+		t.Fatalf("ProveInclusion() error = %v", err)
+	}
+	if err := evidence.Verify(testSchedule, schema); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(e *ProposerSlashingEvidence)
+	}{
+		{"Inclusion index", func(e *ProposerSlashingEvidence) { e.InclusionIndex = 0 }},
+		{"Inclusion root", func(e *ProposerSlashingEvidence) {
+			inclusion := *e.Inclusion
+			inclusion.BeaconBlockRoot = e.SignedHeader1.SigningRoot
+			e.Inclusion = &inclusion
+		}},
+		{"Anchor root", func(e *ProposerSlashingEvidence) { e.BlockRoot = e.SignedHeader1.SigningRoot }},
+		{"Second signature", func(e *ProposerSlashingEvidence) {
+			e.SignedHeader2.Header.Signature = e.SignedHeader1.Header.Signature
+		}},
+		{"Same header", func(e *ProposerSlashingEvidence) { e.SignedHeader2 = e.SignedHeader1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := evidence
+			tt.tamper(&tampered)
+			if err := tampered.Verify(testSchedule, schema); err == nil {
+				t.Error("Verify() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestGenerateProposerSlashingEvidenceErrors(t *testing.T) {
+	slashing, anchor, _, state := setupSlashingBlock(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	otherSlot := slashing
+	otherSlot.Header2 = signHeader(t, testProposerKey(t), beacon.HeaderData{
+		Slot:          "123457",
+		ProposerIndex: slashing.Header2.ProposerIndex,
+		BodyRoot:      slashing.Header2.BodyRoot,
+	})
+	if _, err := GenerateProposerSlashingEvidence(otherSlot, testSchedule, anchor, schema, state); err == nil {
+		t.Error("GenerateProposerSlashingEvidence() for different slots expected error, got nil")
+	}
+
+	unsigned := slashing
+	unsigned.Header1.Signature = ""
+	if _, err := GenerateProposerSlashingEvidence(unsigned, testSchedule, anchor, schema, state); err == nil {
+		t.Error("GenerateProposerSlashingEvidence() for an unsigned header expected error, got nil")
+	}
+}
@@ -0,0 +1,99 @@
+package proof
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+)
+
+// TimestampProof backs the EIP-4788 lookup key used for a verified block.
+// The contract stores the verified root under the execution timestamp of the
+// next block, so both proofs are against the next block root: one shows its
+// parent_root is the verified root, the other that its
+// body.execution_payload.timestamp is Timestamp. Both are checked offline.
+type TimestampProof struct {
+	Timestamp       int64  `json:"timestamp"`
+	NextBlockRoot   string `json:"nextBlockRoot"`
+	ParentRootProof Data   `json:"parentRootProof"`
+	TimestampProof  Data   `json:"timestampProof"`
+}
+
+// timestampPath locates the execution timestamp from the block root
+var timestampPath = []string{beacon.BodyPathRoot, "execution_payload", "timestamp"}
+
+// GenerateTimestampProof proves the execution timestamp and parent_root of
+// the block that follows the verified block
+func GenerateTimestampProof(nextHeader beacon.HeaderData, schema *beacon.Schema, nextBody any, verifiedRoot [32]byte) (TimestampProof, error) {
+	parentProof, err := GenerateHeaderProof(nextHeader, "parent_root", 0)
+	if err != nil {
+		return TimestampProof{}, fmt.Errorf("error proving next parent_root: %w", err)
+	}
+	if parentProof.FieldValue != "0x"+hex.EncodeToString(verifiedRoot[:]) {
+		return TimestampProof{}, fmt.Errorf("next block at slot %s has parent_root %s, not the verified root 0x%x",
+			nextHeader.Slot, parentProof.FieldValue, verifiedRoot)
+	}
+
+	timestampProof, err := generateNestedProof(nextHeader, beacon.BodyPathRoot, schema.BeaconBlockBody, nextBody,
+		timestampPath[1:], 0)
+	if err != nil {
+		return TimestampProof{}, fmt.Errorf("error proving next execution timestamp: %w", err)
+	}
+
+	leaf, err := decodeBytes32(timestampProof.FieldValue)
+	if err != nil {
+		return TimestampProof{}, err
+	}
+	timestamp := int64(binary.LittleEndian.Uint64(leaf[:8]))
+	if timestamp != nextHeader.Timestamp {
+		return TimestampProof{}, fmt.Errorf("next block body has execution timestamp %d, but the API reported %d",
+			timestamp, nextHeader.Timestamp)
+	}
+
+	log.Printf("Proved execution timestamp %d of the next block at slot %s", timestamp, nextHeader.Slot)
+	return TimestampProof{
+		Timestamp:       timestamp,
+		NextBlockRoot:   timestampProof.BeaconBlockRoot,
+		ParentRootProof: parentProof,
+		TimestampProof:  timestampProof,
+	}, nil
+}
+
+// Verify checks that both proofs lead to the same next block root, that the
+// parent_root is verifiedRoot and that the timestamp leaf sits at
+// body.execution_payload.timestamp and encodes Timestamp
+func (p TimestampProof) Verify(schema *beacon.Schema, verifiedRoot [32]byte) error {
+	if p.ParentRootProof.BeaconBlockRoot != p.NextBlockRoot || p.TimestampProof.BeaconBlockRoot != p.NextBlockRoot {
+		return fmt.Errorf("timestamp proofs are not against next block root %s", p.NextBlockRoot)
+	}
+	if p.ParentRootProof.FieldIndex != FieldNames["parent_root"] || p.ParentRootProof.GeneralizedIndex != 0 {
+		return fmt.Errorf("parent root proof is not for the parent_root field")
+	}
+	if p.ParentRootProof.FieldValue != "0x"+hex.EncodeToString(verifiedRoot[:]) {
+		return fmt.Errorf("next block parent_root %s is not 0x%x", p.ParentRootProof.FieldValue, verifiedRoot)
+	}
+
+	gindex, err := schema.GeneralizedIndex(timestampPath...)
+	if err != nil {
+		return err
+	}
+	if p.TimestampProof.GeneralizedIndex != gindex {
+		return fmt.Errorf("timestamp proof has generalized index %d, want %d", p.TimestampProof.GeneralizedIndex, gindex)
+	}
+
+	var leaf [32]byte
+	binary.LittleEndian.PutUint64(leaf[:8], uint64(p.Timestamp))
+	if p.TimestampProof.FieldValue != "0x"+hex.EncodeToString(leaf[:]) {
+		return fmt.Errorf("timestamp leaf %s does not encode %d", p.TimestampProof.FieldValue, p.Timestamp)
+	}
+
+	if err := p.ParentRootProof.VerifyOffline(); err != nil {
+		return fmt.Errorf("parent root proof: %w", err)
+	}
+	if err := p.TimestampProof.VerifyOffline(); err != nil {
+		return fmt.Errorf("timestamp proof: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package proof
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/beacon"
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/ssz"
+)
+
+// setupNextBlock returns a verified header and the Deneb body and header of
+// the block after it
+func setupNextBlock(t *testing.T) ([32]byte, beacon.HeaderData, any) {
+	t.Helper()
+
+	verified := setupTestHeader()
+	verifiedRoot, err := verified.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	body := ssz.Zero(beacon.BeaconBlockBodyDenebType).(map[string]any)
+	body["execution_payload"].(map[string]any)["timestamp"] = "1634567902"
+	bodyRoot, err := ssz.HashTreeRoot(beacon.BeaconBlockBodyDenebType, body)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	next := setupTestHeader()
+	next.Slot = "123457"
+	next.ParentRoot = "0x" + hex.EncodeToString(verifiedRoot[:])
+	next.BodyRoot = "0x" + hex.EncodeToString(bodyRoot[:])
+	next.Timestamp = 1634567902
+	return verifiedRoot, next, body
+}
+
+func TestGenerateTimestampProof(t *testing.T) {
+	verifiedRoot, next, body := setupNextBlock(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	tsProof, err := GenerateTimestampProof(next, schema, body, verifiedRoot)
+	if err != nil {
+		t.Fatalf("GenerateTimestampProof() error = %v", err)
+	}
+	if tsProof.Timestamp != 1634567902 {
+		t.Errorf("Timestamp = %d, want 1634567902", tsProof.Timestamp)
+	}
+	if err := tsProof.Verify(schema, verifiedRoot); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	t.Run("Tampered timestamp", func(t *testing.T) {
+		tampered := tsProof
+		tampered.Timestamp++
+		if err := tampered.Verify(schema, verifiedRoot); err == nil {
+			t.Error("Expected error for a tampered timestamp, got nil")
+		}
+	})
+
+	t.Run("Other verified root", func(t *testing.T) {
+		if err := tsProof.Verify(schema, [32]byte{1}); err == nil {
+			t.Error("Expected error for another verified root, got nil")
+		}
+	})
+}
+
+func TestGenerateTimestampProofErrors(t *testing.T) {
+	verifiedRoot, next, body := setupNextBlock(t)
+	schema, err := beacon.SchemaFor(beacon.Deneb)
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	t.Run("API timestamp differs", func(t *testing.T) {
+		wrong := next
+		wrong.Timestamp = 1634567914
+		if _, err := GenerateTimestampProof(wrong, schema, body, verifiedRoot); err == nil {
+			t.Error("Expected error for a mismatching API timestamp, got nil")
+		}
+	})
+
+	t.Run("Not the child", func(t *testing.T) {
+		if _, err := GenerateTimestampProof(next, schema, body, [32]byte{1}); err == nil {
+			t.Error("Expected error for a block that is not the child, got nil")
+		}
+	})
+}
+
+func TestVerifyOffline(t *testing.T) {
+	for _, field := range []string{"slot", "parent_root", "body_root"} {
+		proofData, err := GenerateHeaderProof(setupTestHeader(), field, 0)
+		if err != nil {
+			t.Fatalf("GenerateHeaderProof() error = %v", err)
+		}
+		if err := proofData.VerifyOffline(); err != nil {
+			t.Errorf("VerifyOffline(%s) error = %v", field, err)
+		}
+		proofData.FieldIndex = (proofData.FieldIndex + 1) % 5
+		if err := proofData.VerifyOffline(); err == nil {
+			t.Errorf("VerifyOffline(%s) with the wrong index expected error, got nil", field)
+		}
+	}
+}
@@ -25,6 +25,10 @@ type Data struct {
 	FieldIndex      int      `json:"fieldIndex"`
 	FieldValue      string   `json:"fieldValue"`
 	MerkleProof     []string `json:"merkleProof"`
+	// GeneralizedIndex is set for proofs that go deeper than the header
+	// fields; they are verified with verifyProof instead of verifyHeaderField
+	GeneralizedIndex uint64 `json:"generalizedIndex,omitempty"`
+	Path             string `json:"path,omitempty"`
 }
 
 // FieldNames maps field names to their indices
@@ -36,8 +40,22 @@ var FieldNames = map[string]int{
 	"body_root":      4,
 }
 
-// BeaconHeaderVerifierABI contains the minimal ABI for the verifyHeaderField function
+// BeaconHeaderVerifierABI contains the minimal ABI for the verifyHeaderField and verifyProof functions
 const BeaconHeaderVerifierABI = `[
+  {
+    "inputs": [
+      {"internalType": "uint256", "name": "beaconTimestamp", "type": "uint256"},
+      {"internalType": "uint256", "name": "generalizedIndex", "type": "uint256"},
+      {"internalType": "bytes32", "name": "leaf", "type": "bytes32"},
+      {"internalType": "bytes32[]", "name": "merkleProof", "type": "bytes32[]"}
+    ],
+    "name": "verifyProof",
+    "outputs": [
+      {"internalType": "bool", "name": "", "type": "bool"}
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
   {
     "inputs": [
       {"internalType": "uint256", "name": "beaconTimestamp", "type": "uint256"},
@@ -138,8 +156,39 @@ func GenerateHeaderProof(headerData beacon.HeaderData, fieldName string, nextSlo
 	return proofData, nil
 }
 
-// VerifyOnChain uses Web3 to call the onchain BeaconHeaderVerifier contract
-func VerifyOnChain(client *ethclient.Client, contractAddress string, proofData Data) (bool, error) {
+// VerifyOffline checks the branch of a proof against its BeaconBlockRoot
+// without calling the contract
+func (d Data) VerifyOffline() error {
+	gindex := d.GeneralizedIndex
+	if gindex == 0 {
+		// Header field proofs are addressed by field index in the 8-leaf header tree
+		gindex = 8 + uint64(d.FieldIndex)
+	}
+
+	leaf, err := decodeBytes32(d.FieldValue)
+	if err != nil {
+		return fmt.Errorf("error decoding field value: %w", err)
+	}
+	root, err := decodeBytes32(d.BeaconBlockRoot)
+	if err != nil {
+		return fmt.Errorf("error decoding block root: %w", err)
+	}
+	branch := make([][32]byte, len(d.MerkleProof))
+	for i, node := range d.MerkleProof {
+		if branch[i], err = decodeBytes32(node); err != nil {
+			return fmt.Errorf("error decoding proof element %d: %w", i, err)
+		}
+	}
+
+	if !merkle.VerifyBranch(leaf, branch, gindex, root) {
+		return fmt.Errorf("proof for generalized index %d does not lead to block root %s", gindex, d.BeaconBlockRoot)
+	}
+	return nil
+}
+
+// VerifyOnChain uses Web3 to call the onchain BeaconHeaderVerifier contract,
+// giving up when ctx is cancelled
+func VerifyOnChain(ctx context.Context, client *ethclient.Client, contractAddress string, proofData Data) (bool, error) {
 	parsedABI, err := abi.JSON(bytes.NewReader([]byte(BeaconHeaderVerifierABI)))
 	if err != nil {
 		return false, fmt.Errorf("error parsing ABI: %w", err)
@@ -170,11 +219,23 @@ func VerifyOnChain(client *ethclient.Client, contractAddress string, proofData D
 		copy(merkleProofBytes[i][:], proofBytes)
 	}
 
-	log.Printf("Verifying field index %d with value %s...", fieldIndex, proofData.FieldValue[:10])
-	log.Printf("Using timestamp: %d", beaconTimestamp)
-	log.Printf("Merkle proof length: %d", len(merkleProofBytes))
+	method := "verifyHeaderField"
+	var input []byte
+	if proofData.GeneralizedIndex != 0 {
+		method = "verifyProof"
+		log.Printf("Verifying generalized index %d with value %s...", proofData.GeneralizedIndex, proofData.FieldValue[:10])
+		log.Printf("Using timestamp: %d", beaconTimestamp)
+		log.Printf("Merkle proof length: %d", len(merkleProofBytes))
+
+		generalizedIndex := new(big.Int).SetUint64(proofData.GeneralizedIndex)
+		input, err = parsedABI.Pack(method, beaconTimestamp, generalizedIndex, fieldValue, merkleProofBytes)
+	} else {
+		log.Printf("Verifying field index %d with value %s...", fieldIndex, proofData.FieldValue[:10])
+		log.Printf("Using timestamp: %d", beaconTimestamp)
+		log.Printf("Merkle proof length: %d", len(merkleProofBytes))
 
-	input, err := parsedABI.Pack("verifyHeaderField", beaconTimestamp, fieldIndex, fieldValue, merkleProofBytes)
+		input, err = parsedABI.Pack(method, beaconTimestamp, fieldIndex, fieldValue, merkleProofBytes)
+	}
 	if err != nil {
 		return false, fmt.Errorf("error packing input data: %w", err)
 	}
@@ -184,13 +245,13 @@ func VerifyOnChain(client *ethclient.Client, contractAddress string, proofData D
 		Data: input,
 	}
 
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := client.CallContract(ctx, msg, nil)
 	if err != nil {
 		return false, fmt.Errorf("error calling contract: %w", err)
 	}
 
 	var verificationResult bool
-	if err := parsedABI.UnpackIntoInterface(&verificationResult, "verifyHeaderField", result); err != nil {
+	if err := parsedABI.UnpackIntoInterface(&verificationResult, method, result); err != nil {
 		return false, fmt.Errorf("error unpacking result: %w", err)
 	}
 
@@ -212,6 +273,29 @@ func getMapKeys(m map[string]int) []string {
 	return keys
 }
 
+// Helper function to encode a branch as 0x-prefixed hex strings
+func encodeBranch(branch [][32]byte) []string {
+	encoded := make([]string, len(branch))
+	for i, node := range branch {
+		encoded[i] = "0x" + hex.EncodeToString(node[:])
+	}
+	return encoded
+}
+
+// Helper function to decode a 0x-prefixed 32-byte hex string
+func decodeBytes32(hexStr string) ([32]byte, error) {
+	var out [32]byte
+	b, err := hex.DecodeString(trimHexPrefix(hexStr))
+	if err != nil {
+		return out, err
+	}
+	if len(b) != 32 {
+		return out, fmt.Errorf("expected 32 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
 // Helper function to trim "0x" prefix from hex strings
 func trimHexPrefix(hexStr string) string {
 	if len(hexStr) >= 2 && hexStr[0:2] == "0x" {
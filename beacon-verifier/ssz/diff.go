@@ -0,0 +1,63 @@
+package ssz
+
+import "strconv"
+
+// Diff compares two values of type t and returns the paths of the deepest
+// subtrees whose roots differ. Containers and equally long lists of
+// composite elements are descended into; anything else is reported whole.
+func Diff(t *Type, a, b any) ([][]string, error) {
+	var diffs [][]string
+	err := diff(t, a, b, nil, &diffs)
+	return diffs, err
+}
+
+func diff(t *Type, a, b any, path []string, diffs *[][]string) error {
+	rootA, err := HashTreeRoot(t, a)
+	if err != nil {
+		return err
+	}
+	rootB, err := HashTreeRoot(t, b)
+	if err != nil {
+		return err
+	}
+	if rootA == rootB {
+		return nil
+	}
+
+	switch {
+	case t.Kind == KindContainer:
+		fieldsA, _ := a.(map[string]any)
+		fieldsB, _ := b.(map[string]any)
+		for _, f := range t.Fields {
+			if err := diff(f.Type, fieldsA[f.Name], fieldsB[f.Name], appendPath(path, f.Name), diffs); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case (t.Kind == KindList || t.Kind == KindVector) && !t.Elem.IsBasic():
+		itemsA, _ := a.([]any)
+		itemsB, _ := b.([]any)
+		if len(itemsA) != len(itemsB) {
+			break
+		}
+		for i := range itemsA {
+			if err := diff(t.Elem, itemsA[i], itemsB[i], appendPath(path, strconv.Itoa(i)), diffs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	*diffs = append(*diffs, path)
+	return nil
+}
+
+// appendPath copies path before appending so sibling paths never share
+// a backing array
+func appendPath(path []string, elem string) []string {
+	out := make([]string, len(path)+1)
+	copy(out, path)
+	out[len(path)] = elem
+	return out
+}
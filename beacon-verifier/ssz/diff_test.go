@@ -0,0 +1,53 @@
+package ssz
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := testBodyValue()
+
+	t.Run("Equal", func(t *testing.T) {
+		diffs, err := Diff(testBodyType, a, testBodyValue())
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		if len(diffs) != 0 {
+			t.Errorf("Diff() = %v, want none", diffs)
+		}
+	})
+
+	t.Run("Nested fields", func(t *testing.T) {
+		b := testBodyValue()
+		b["number"] = "6"
+		b["headers"].([]any)[1].(map[string]any)["proposer_index"] = "7"
+		b["balances"].([]any)[2] = "31"
+
+		diffs, err := Diff(testBodyType, a, b)
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		want := []string{"number", "headers[1].proposer_index", "balances"}
+		got := make([]string, len(diffs))
+		for i, d := range diffs {
+			got[i] = FormatPath(d)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Diff() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Different lengths", func(t *testing.T) {
+		b := testBodyValue()
+		b["headers"] = b["headers"].([]any)[:1]
+
+		diffs, err := Diff(testBodyType, a, b)
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		if len(diffs) != 1 || FormatPath(diffs[0]) != "headers" {
+			t.Errorf("Diff() = %v, want [headers]", diffs)
+		}
+	})
+}
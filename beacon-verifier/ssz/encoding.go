@@ -0,0 +1,351 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// offsetSize is the width of the offsets that locate variable-size parts
+const offsetSize = 4
+
+// FixedSize returns the serialized size of a fixed-size type, and false for
+// variable-size types
+func (t *Type) FixedSize() (int, bool) {
+	switch t.Kind {
+	case KindUint, KindByteVector:
+		return t.Size, true
+	case KindBoolean:
+		return 1, true
+	case KindBitvector:
+		return int((t.Length + 7) / 8), true
+	case KindVector:
+		size, ok := t.Elem.FixedSize()
+		if !ok {
+			return 0, false
+		}
+		return size * int(t.Length), true
+	case KindContainer:
+		total := 0
+		for _, f := range t.Fields {
+			size, ok := f.Type.FixedSize()
+			if !ok {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+	}
+	return 0, false
+}
+
+// isPacked reports whether the data tree of t is built from its serialized
+// bytes cut into chunks rather than from the roots of its elements
+func (t *Type) isPacked() bool {
+	switch t.Kind {
+	case KindByteVector, KindByteList, KindBitvector, KindBitlist:
+		return true
+	case KindVector, KindList:
+		return t.Elem.IsBasic()
+	}
+	return false
+}
+
+// Marshal serializes a value in the generic JSON shape into SSZ bytes
+func Marshal(t *Type, v any) ([]byte, error) {
+	switch t.Kind {
+	case KindUint, KindBoolean:
+		chunk, err := basicChunk(t, v)
+		if err != nil {
+			return nil, err
+		}
+		size, _ := t.FixedSize()
+		return chunk[:size], nil
+
+	case KindByteVector, KindByteList, KindBitvector, KindBitlist:
+		b, err := decodeHex(v, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := packed(t, b); err != nil {
+			return nil, err
+		}
+		return b, nil
+
+	case KindVector, KindList:
+		items, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a JSON array, got %T", t.Name, v)
+		}
+		if err := checkCount(t, uint64(len(items))); err != nil {
+			return nil, err
+		}
+		types := make([]*Type, len(items))
+		for i := range types {
+			types[i] = t.Elem
+		}
+		return marshalParts(t.Name, types, items)
+
+	case KindContainer:
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a JSON object, got %T", t.Name, v)
+		}
+		types := make([]*Type, len(t.Fields))
+		values := make([]any, len(t.Fields))
+		for i, f := range t.Fields {
+			value, ok := fields[f.Name]
+			if !ok {
+				return nil, fmt.Errorf("%s is missing field %q", t.Name, f.Name)
+			}
+			types[i], values[i] = f.Type, value
+		}
+		return marshalParts(t.Name, types, values)
+	}
+	return nil, fmt.Errorf("cannot serialize %s", t.Name)
+}
+
+// marshalParts serializes a sequence of values: fixed-size parts in place
+// and variable-size parts appended after the fixed part behind offsets
+func marshalParts(name string, types []*Type, values []any) ([]byte, error) {
+	parts := make([][]byte, len(values))
+	variable := make([]bool, len(values))
+	fixedLength := 0
+	for i, v := range values {
+		b, err := Marshal(types[i], v)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		parts[i] = b
+		if _, ok := types[i].FixedSize(); ok {
+			fixedLength += len(b)
+		} else {
+			variable[i] = true
+			fixedLength += offsetSize
+		}
+	}
+
+	out := make([]byte, 0, fixedLength)
+	offset := fixedLength
+	for i, b := range parts {
+		if !variable[i] {
+			out = append(out, b...)
+			continue
+		}
+		out = binary.LittleEndian.AppendUint32(out, uint32(offset))
+		offset += len(b)
+	}
+	for i, b := range parts {
+		if variable[i] {
+			out = append(out, b...)
+		}
+	}
+	return out, nil
+}
+
+// Unmarshal decodes SSZ bytes into the generic JSON shape used by the beacon
+// API, so decoded values can be hashed and proven like API responses
+func Unmarshal(t *Type, data []byte) (any, error) {
+	switch t.Kind {
+	case KindUint:
+		if len(data) != t.Size {
+			return nil, fmt.Errorf("%s has %d bytes, expected %d", t.Name, len(data), t.Size)
+		}
+		if t.Size <= 8 {
+			var buf [8]byte
+			copy(buf[:], data)
+			return strconv.FormatUint(binary.LittleEndian.Uint64(buf[:]), 10), nil
+		}
+		be := make([]byte, len(data))
+		for i := range data {
+			be[len(data)-1-i] = data[i]
+		}
+		return new(big.Int).SetBytes(be).String(), nil
+
+	case KindBoolean:
+		if len(data) != 1 || data[0] > 1 {
+			return nil, fmt.Errorf("invalid boolean %x", data)
+		}
+		return data[0] == 1, nil
+
+	case KindByteVector, KindByteList, KindBitvector, KindBitlist:
+		if _, _, err := packed(t, data); err != nil {
+			return nil, err
+		}
+		return "0x" + hex.EncodeToString(data), nil
+
+	case KindVector, KindList:
+		parts, err := splitElements(t, data)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]any, len(parts))
+		for i, part := range parts {
+			if items[i], err = Unmarshal(t.Elem, part); err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return items, nil
+
+	case KindContainer:
+		parts, err := splitContainer(t, data)
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]any, len(parts))
+		for i, f := range t.Fields {
+			if fields[f.Name], err = Unmarshal(f.Type, parts[i]); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", t.Name, f.Name, err)
+			}
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("cannot deserialize %s", t.Name)
+}
+
+// packed validates the serialized size of a packed type and returns the
+// bytes its data tree is built from and the length it mixes in
+func packed(t *Type, data []byte) ([]byte, uint64, error) {
+	switch t.Kind {
+	case KindByteVector, KindBitvector:
+		size, _ := t.FixedSize()
+		if len(data) != size {
+			return nil, 0, fmt.Errorf("%s has %d bytes, expected %d", t.Name, len(data), size)
+		}
+		return data, 0, nil
+
+	case KindByteList:
+		if uint64(len(data)) > t.Length {
+			return nil, 0, fmt.Errorf("%s has %d bytes, exceeding the limit", t.Name, len(data))
+		}
+		return data, uint64(len(data)), nil
+
+	case KindBitlist:
+		bitfield, length, err := splitBitlist(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		if length > t.Length {
+			return nil, 0, fmt.Errorf("%s has %d bits, exceeding the limit", t.Name, length)
+		}
+		return bitfield, length, nil
+
+	case KindVector, KindList:
+		if len(data)%t.Elem.Size != 0 {
+			return nil, 0, fmt.Errorf("%s has %d bytes, not a multiple of %d", t.Name, len(data), t.Elem.Size)
+		}
+		count := uint64(len(data) / t.Elem.Size)
+		if err := checkCount(t, count); err != nil {
+			return nil, 0, err
+		}
+		return data, count, nil
+	}
+	return nil, 0, fmt.Errorf("%s is not a packed type", t.Name)
+}
+
+// checkCount validates the element count of a vector or list
+func checkCount(t *Type, count uint64) error {
+	if t.Kind == KindVector && count != t.Length {
+		return fmt.Errorf("%s has %d elements, expected %d", t.Name, count, t.Length)
+	}
+	if t.Kind == KindList && count > t.Length {
+		return fmt.Errorf("%s has %d elements, exceeding the limit", t.Name, count)
+	}
+	return nil
+}
+
+// splitElements cuts a serialized vector or list into its elements
+func splitElements(t *Type, data []byte) ([][]byte, error) {
+	if size, ok := t.Elem.FixedSize(); ok {
+		if len(data)%size != 0 {
+			return nil, fmt.Errorf("%s has %d bytes, not a multiple of %d", t.Name, len(data), size)
+		}
+		parts := make([][]byte, len(data)/size)
+		if err := checkCount(t, uint64(len(parts))); err != nil {
+			return nil, err
+		}
+		for i := range parts {
+			parts[i] = data[i*size : (i+1)*size]
+		}
+		return parts, nil
+	}
+
+	// Variable-size elements start with one offset each; the first offset
+	// therefore tells the count
+	if len(data) == 0 {
+		return nil, checkCount(t, 0)
+	}
+	if len(data) < offsetSize {
+		return nil, fmt.Errorf("%s is truncated", t.Name)
+	}
+	first := int(binary.LittleEndian.Uint32(data))
+	if first == 0 || first%offsetSize != 0 || first > len(data) {
+		return nil, fmt.Errorf("%s has an invalid first offset %d", t.Name, first)
+	}
+	count := first / offsetSize
+	if err := checkCount(t, uint64(count)); err != nil {
+		return nil, err
+	}
+	offsets := make([]int, count)
+	for i := range offsets {
+		offsets[i] = int(binary.LittleEndian.Uint32(data[i*offsetSize:]))
+	}
+	return cutAtOffsets(t.Name, data, offsets, make([][]byte, count), nil)
+}
+
+// splitContainer cuts a serialized container into its fields
+func splitContainer(t *Type, data []byte) ([][]byte, error) {
+	parts := make([][]byte, len(t.Fields))
+	var variable []int
+	var offsets []int
+	pos := 0
+	for i, f := range t.Fields {
+		size, ok := f.Type.FixedSize()
+		if !ok {
+			size = offsetSize
+		}
+		if pos+size > len(data) {
+			return nil, fmt.Errorf("%s is truncated at field %q", t.Name, f.Name)
+		}
+		if ok {
+			parts[i] = data[pos : pos+size]
+		} else {
+			variable = append(variable, i)
+			offsets = append(offsets, int(binary.LittleEndian.Uint32(data[pos:])))
+		}
+		pos += size
+	}
+
+	if len(variable) == 0 {
+		if pos != len(data) {
+			return nil, fmt.Errorf("%s has %d trailing bytes", t.Name, len(data)-pos)
+		}
+		return parts, nil
+	}
+	if offsets[0] != pos {
+		return nil, fmt.Errorf("%s has first offset %d, expected %d", t.Name, offsets[0], pos)
+	}
+	return cutAtOffsets(t.Name, data, offsets, parts, variable)
+}
+
+// cutAtOffsets stores the variable-size parts delimited by offsets into
+// parts, at the positions listed in slots (or in order when slots is nil)
+func cutAtOffsets(name string, data []byte, offsets []int, parts [][]byte, slots []int) ([][]byte, error) {
+	for j, start := range offsets {
+		end := len(data)
+		if j+1 < len(offsets) {
+			end = offsets[j+1]
+		}
+		if start > end || end > len(data) {
+			return nil, fmt.Errorf("%s has invalid offsets %d..%d", name, start, end)
+		}
+		slot := j
+		if slots != nil {
+			slot = slots[j]
+		}
+		parts[slot] = data[start:end]
+	}
+	return parts, nil
+}
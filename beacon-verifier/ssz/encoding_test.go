@@ -0,0 +1,116 @@
+package ssz
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testNestedType has variable-size fields nested in a variable-size list
+var testNestedType = Container("Nested",
+	F("flag", Boolean),
+	F("items", List(Container("Item",
+		F("value", Uint256),
+		F("bits", Bitlist(16)),
+		F("data", ByteList(8)),
+	), 4)),
+	F("mask", Bitvector(10)),
+)
+
+func testNestedValue() map[string]any {
+	return map[string]any{
+		"flag": true,
+		"items": []any{
+			map[string]any{"value": "340282366920938463463374607431768211457", "bits": "0x0d", "data": "0xaabb"},
+			map[string]any{"value": "0", "bits": "0x01", "data": "0x"},
+		},
+		"mask": "0xff03",
+	}
+}
+
+func TestMarshalHeader(t *testing.T) {
+	value := testHeaderValue()
+	got, err := Marshal(testHeaderType, value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := []byte{0x40, 0xe2, 0x01, 0, 0, 0, 0, 0, 42, 0, 0, 0, 0, 0, 0, 0}
+	want = append(want, mustHex(t, value["parent_root"].(string))...)
+	want = append(want, mustHex(t, value["state_root"].(string))...)
+	want = append(want, mustHex(t, value["body_root"].(string))...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Marshal() = %x, want %x", got, want)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   *Type
+		value any
+	}{
+		{"Header", testHeaderType, testHeaderValue()},
+		{"Body", testBodyType, testBodyValue()},
+		{"Nested", testNestedType, testNestedValue()},
+		{"Empty lists", testBodyType, Zero(testBodyType)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.typ, tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			decoded, err := Unmarshal(tt.typ, data)
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			paths, err := Diff(tt.typ, tt.value, decoded)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if len(paths) != 0 {
+				t.Errorf("round trip changed %v", paths)
+			}
+			again, err := Marshal(tt.typ, decoded)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Errorf("Marshal(Unmarshal()) = %x, want %x", again, data)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	body, err := Marshal(testBodyType, testBodyValue())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	badOffset := bytes.Clone(body)
+	badOffset[8] = 0xff
+
+	tests := []struct {
+		name string
+		typ  *Type
+		data []byte
+	}{
+		{"Truncated header", testHeaderType, make([]byte, 111)},
+		{"Trailing bytes", testHeaderType, make([]byte, 113)},
+		{"Truncated body", testBodyType, body[:6]},
+		{"Invalid first offset", testBodyType, badOffset},
+		{"Invalid boolean", Boolean, []byte{2}},
+		{"Bitlist without delimiter", Bitlist(8), []byte{0x00}},
+		{"List beyond limit", List(Uint64, 2), make([]byte, 24)},
+		{"Vector length", Vector(Uint64, 2), make([]byte, 8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal(tt.typ, tt.data); err == nil {
+				t.Error("Unmarshal() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+// Values are the generic shapes produced by decoding beacon API JSON:
+// containers are map[string]any, lists and vectors are []any, integers are
+// decimal strings (or json.Number), byte strings and bitfields are 0x-prefixed
+// hex and booleans are bool.
+
+// HashTreeRoot computes the SSZ hash tree root of a value of type t
+func HashTreeRoot(t *Type, v any) ([32]byte, error) {
+	if t.IsBasic() {
+		return basicChunk(t, v)
+	}
+
+	chunks, length, err := dataChunks(t, v)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	root, err := merkle.Merkleize(chunks, t.ChunkLimit())
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("merkleizing %s: %w", t.Name, err)
+	}
+	if t.HasLength() {
+		root = merkle.MixInLength(root, length)
+	}
+	return root, nil
+}
+
+// dataChunks returns the chunks of the data tree of a composite value and,
+// for list types, the length that gets mixed in
+func dataChunks(t *Type, v any) ([][32]byte, uint64, error) {
+	switch t.Kind {
+	case KindByteVector:
+		b, err := decodeHex(v, t.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(b) != t.Size {
+			return nil, 0, fmt.Errorf("%s has %d bytes, expected %d", t.Name, len(b), t.Size)
+		}
+		return merkle.Pack(b), 0, nil
+
+	case KindByteList:
+		b, err := decodeHex(v, t.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if uint64(len(b)) > t.Length {
+			return nil, 0, fmt.Errorf("%s has %d bytes, exceeding the limit", t.Name, len(b))
+		}
+		return merkle.Pack(b), uint64(len(b)), nil
+
+	case KindBitvector:
+		b, err := decodeHex(v, t.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if uint64(len(b)) != (t.Length+7)/8 {
+			return nil, 0, fmt.Errorf("%s has %d bytes, expected %d", t.Name, len(b), (t.Length+7)/8)
+		}
+		return merkle.Pack(b), 0, nil
+
+	case KindBitlist:
+		b, err := decodeHex(v, t.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		bitfield, length, err := splitBitlist(b)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		if length > t.Length {
+			return nil, 0, fmt.Errorf("%s has %d bits, exceeding the limit", t.Name, length)
+		}
+		return merkle.Pack(bitfield), length, nil
+
+	case KindVector, KindList:
+		items, ok := v.([]any)
+		if !ok {
+			return nil, 0, fmt.Errorf("%s expects a JSON array, got %T", t.Name, v)
+		}
+		if t.Kind == KindVector && uint64(len(items)) != t.Length {
+			return nil, 0, fmt.Errorf("%s has %d elements, expected %d", t.Name, len(items), t.Length)
+		}
+		if t.Kind == KindList && uint64(len(items)) > t.Length {
+			return nil, 0, fmt.Errorf("%s has %d elements, exceeding the limit", t.Name, len(items))
+		}
+
+		if t.Elem.IsBasic() {
+			serialized := make([]byte, 0, len(items)*t.Elem.Size)
+			for i, item := range items {
+				chunk, err := basicChunk(t.Elem, item)
+				if err != nil {
+					return nil, 0, fmt.Errorf("%s[%d]: %w", t.Name, i, err)
+				}
+				serialized = append(serialized, chunk[:t.Elem.Size]...)
+			}
+			return merkle.Pack(serialized), uint64(len(items)), nil
+		}
+
+		chunks := make([][32]byte, len(items))
+		for i, item := range items {
+			root, err := HashTreeRoot(t.Elem, item)
+			if err != nil {
+				return nil, 0, fmt.Errorf("[%d]: %w", i, err)
+			}
+			chunks[i] = root
+		}
+		return chunks, uint64(len(items)), nil
+
+	case KindContainer:
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return nil, 0, fmt.Errorf("%s expects a JSON object, got %T", t.Name, v)
+		}
+		chunks := make([][32]byte, len(t.Fields))
+		for i, f := range t.Fields {
+			value, ok := fields[f.Name]
+			if !ok {
+				return nil, 0, fmt.Errorf("%s is missing field %q", t.Name, f.Name)
+			}
+			root, err := HashTreeRoot(f.Type, value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%s.%s: %w", t.Name, f.Name, err)
+			}
+			chunks[i] = root
+		}
+		return chunks, 0, nil
+	}
+
+	return nil, 0, fmt.Errorf("%s is not a composite type", t.Name)
+}
+
+// basicChunk serializes a uint or boolean into a right-padded chunk
+func basicChunk(t *Type, v any) ([32]byte, error) {
+	var chunk [32]byte
+
+	if t.Kind == KindBoolean {
+		switch b := v.(type) {
+		case bool:
+			if b {
+				chunk[0] = 1
+			}
+			return chunk, nil
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return chunk, fmt.Errorf("parsing boolean: %w", err)
+			}
+			if parsed {
+				chunk[0] = 1
+			}
+			return chunk, nil
+		}
+		return chunk, fmt.Errorf("boolean expects a JSON bool, got %T", v)
+	}
+
+	s, err := numberString(v)
+	if err != nil {
+		return chunk, err
+	}
+
+	if t.Size <= 8 {
+		n, err := strconv.ParseUint(s, 10, t.Size*8)
+		if err != nil {
+			return chunk, fmt.Errorf("parsing %s: %w", t.Name, err)
+		}
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], n)
+		copy(chunk[:t.Size], buf[:])
+		return chunk, nil
+	}
+
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > t.Size*8 {
+		return chunk, fmt.Errorf("parsing %s: invalid value %q", t.Name, s)
+	}
+	be := n.FillBytes(make([]byte, t.Size))
+	for i := 0; i < t.Size; i++ {
+		chunk[i] = be[t.Size-1-i]
+	}
+	return chunk, nil
+}
+
+// numberString normalizes the JSON representations of an integer
+func numberString(v any) (string, error) {
+	switch n := v.(type) {
+	case string:
+		return n, nil
+	case json.Number:
+		return n.String(), nil
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("expected an integer, got %T", v)
+}
+
+// decodeHex decodes a 0x-prefixed hex string value
+func decodeHex(v any, typeName string) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s expects a hex string, got %T", typeName, v)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", typeName, err)
+	}
+	return b, nil
+}
+
+// splitBitlist strips the delimiter bit from an SSZ-encoded bitlist and
+// returns the remaining bitfield bytes and the bit length
+func splitBitlist(b []byte) ([]byte, uint64, error) {
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return nil, 0, fmt.Errorf("bitlist is missing its delimiter bit")
+	}
+	last := b[len(b)-1]
+	msb := 7
+	for last>>uint(msb) == 0 {
+		msb--
+	}
+	length := uint64(len(b)-1)*8 + uint64(msb)
+
+	bitfield := make([]byte, (length+7)/8)
+	copy(bitfield, b)
+	if msb > 0 {
+		bitfield[len(bitfield)-1] &^= 1 << uint(msb)
+	}
+	return bitfield, length, nil
+}
+
+// Zero returns the default value of t in the generic JSON shape
+func Zero(t *Type) any {
+	switch t.Kind {
+	case KindUint:
+		return "0"
+	case KindBoolean:
+		return false
+	case KindByteVector:
+		return "0x" + strings.Repeat("00", t.Size)
+	case KindByteList:
+		return "0x"
+	case KindBitvector:
+		return "0x" + strings.Repeat("00", int((t.Length+7)/8))
+	case KindBitlist:
+		return "0x01"
+	case KindVector:
+		items := make([]any, t.Length)
+		for i := range items {
+			items[i] = Zero(t.Elem)
+		}
+		return items
+	case KindList:
+		return []any{}
+	case KindContainer:
+		fields := make(map[string]any, len(t.Fields))
+		for _, f := range t.Fields {
+			fields[f.Name] = Zero(f.Type)
+		}
+		return fields
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package ssz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+var testHeaderType = Container("BeaconBlockHeader",
+	F("slot", Uint64),
+	F("proposer_index", Uint64),
+	F("parent_root", Bytes(32)),
+	F("state_root", Bytes(32)),
+	F("body_root", Bytes(32)),
+)
+
+func testHeaderValue() map[string]any {
+	return map[string]any{
+		"slot":           "123456",
+		"proposer_index": "42",
+		"parent_root":    "0x4a81947b35bdc11471fc7b42350427a3b9d2b92bf21d423ded6dcc5c66caad0e",
+		"state_root":     "0x5bc9a4ef3cf09a315ffbc12872de6cc412a7abb55a5228cc21fbdb5fb797d7a8",
+		"body_root":      "0x67df26e0c9f5de4fe7b3f66f3591f84a9cf6e8cda7f5b3f23db5c3967a505c31",
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestHashTreeRootContainerMatchesTree(t *testing.T) {
+	value := testHeaderValue()
+
+	slot := make([]byte, 32)
+	slot[0], slot[1], slot[2] = 0x40, 0xe2, 0x01 // 123456 little-endian
+	proposer := make([]byte, 32)
+	proposer[0] = 42
+
+	tree, err := merkle.NewTree([][]byte{
+		slot,
+		proposer,
+		mustHex(t, value["parent_root"].(string)),
+		mustHex(t, value["state_root"].(string)),
+		mustHex(t, value["body_root"].(string)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+
+	root, err := HashTreeRoot(testHeaderType, value)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	if !bytes.Equal(root[:], tree.Root()) {
+		t.Errorf("HashTreeRoot() = %x, want %x", root, tree.Root())
+	}
+}
+
+func TestHashTreeRootBasicTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   *Type
+		value any
+		want  [32]byte
+	}{
+		{"uint64 string", Uint64, "258", [32]byte{2, 1}},
+		{"uint8", Uint8, "7", [32]byte{7}},
+		{"uint256 little-endian", Uint256, "65536", [32]byte{0, 0, 1}},
+		{"boolean", Boolean, true, [32]byte{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HashTreeRoot(tt.typ, tt.value)
+			if err != nil {
+				t.Fatalf("HashTreeRoot() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HashTreeRoot() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashTreeRootLists(t *testing.T) {
+	t.Run("List of uint64 packs four per chunk", func(t *testing.T) {
+		typ := List(Uint64, 16)
+		got, err := HashTreeRoot(typ, []any{"1", "2", "3", "4", "5"})
+		if err != nil {
+			t.Fatalf("HashTreeRoot() error = %v", err)
+		}
+
+		var first, second [32]byte
+		for i := 0; i < 4; i++ {
+			first[i*8] = byte(i + 1)
+		}
+		second[0] = 5
+		want := merkle.MixInLength(merkle.HashPair(merkle.HashPair(first, second), merkle.ZeroHash(1)), 5)
+		if got != want {
+			t.Errorf("HashTreeRoot() = %x, want %x", got, want)
+		}
+	})
+
+	t.Run("Bitlist strips the delimiter", func(t *testing.T) {
+		// 0x0d = 0b1101: bits 1,0,1 followed by the delimiter
+		got, err := HashTreeRoot(Bitlist(2048), "0x0d")
+		if err != nil {
+			t.Fatalf("HashTreeRoot() error = %v", err)
+		}
+		// 2048 bits fill 8 chunks, so the data tree has depth 3
+		dataRoot := merkle.HashPair(merkle.HashPair(merkle.HashPair([32]byte{0x05}, merkle.ZeroHash(0)), merkle.ZeroHash(1)), merkle.ZeroHash(2))
+		want := merkle.MixInLength(dataRoot, 3)
+		if got != want {
+			t.Errorf("HashTreeRoot() = %x, want %x", got, want)
+		}
+	})
+
+	t.Run("ByteList mixes in byte length", func(t *testing.T) {
+		got, err := HashTreeRoot(ByteList(32), "0xabcd")
+		if err != nil {
+			t.Fatalf("HashTreeRoot() error = %v", err)
+		}
+		want := merkle.MixInLength([32]byte{0xab, 0xcd}, 2)
+		if got != want {
+			t.Errorf("HashTreeRoot() = %x, want %x", got, want)
+		}
+	})
+}
+
+func TestHashTreeRootErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   *Type
+		value any
+	}{
+		{"Missing field", testHeaderType, map[string]any{"slot": "1"}},
+		{"Wrong byte length", Bytes(32), "0x1234"},
+		{"Invalid hex", Bytes(2), "0xzzzz"},
+		{"List over limit", List(Uint64, 1), []any{"1", "2"}},
+		{"Vector wrong length", Vector(Uint64, 3), []any{"1"}},
+		{"Bitlist without delimiter", Bitlist(8), "0x00"},
+		{"Not a number", Uint64, "abc"},
+		{"Uint8 overflow", Uint8, "256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := HashTreeRoot(tt.typ, tt.value); err == nil {
+				t.Errorf("HashTreeRoot() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestZeroValueHashes(t *testing.T) {
+	typ := Container("Nested",
+		F("a", Uint64),
+		F("b", List(testHeaderType, 4)),
+		F("c", Vector(Bytes(48), 2)),
+		F("d", Bitlist(16)),
+	)
+	if _, err := HashTreeRoot(typ, Zero(typ)); err != nil {
+		t.Errorf("HashTreeRoot(Zero()) error = %v", err)
+	}
+}
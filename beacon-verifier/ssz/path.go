@@ -0,0 +1,110 @@
+package ssz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+// ParsePath splits a dotted path with optional [i] element indices, such as
+// "validators[123].effective_balance", into the path elements Locate expects
+func ParsePath(s string) ([]string, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var path []string
+	for _, segment := range strings.Split(s, ".") {
+		name, rest, indexed := strings.Cut(segment, "[")
+		if name == "" {
+			return nil, fmt.Errorf("invalid path %q: empty element", s)
+		}
+		path = append(path, name)
+		if !indexed {
+			continue
+		}
+
+		// Each remaining "i]" or "i][j]..." is one or more element indices
+		for _, index := range strings.Split("["+rest, "[")[1:] {
+			digits, ok := strings.CutSuffix(index, "]")
+			if !ok {
+				return nil, fmt.Errorf("invalid path %q: unterminated index in %q", s, segment)
+			}
+			if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid path %q: %q is not an element index", s, digits)
+			}
+			path = append(path, digits)
+		}
+	}
+	return path, nil
+}
+
+// PathOf decodes a generalized index relative to the root of t back into
+// path elements. Decoding stops at the first node that has no named
+// children (a basic value, byte vector or bitfield); the index of the node
+// below it is returned as rest, which is 1 when gindex ends exactly there.
+func (t *Type) PathOf(gindex uint64) ([]string, uint64, error) {
+	if gindex == 0 {
+		return nil, 0, fmt.Errorf("generalized index 0 is invalid")
+	}
+
+	var path []string
+	for gindex > 1 {
+		if t.Kind != KindContainer && t.Kind != KindVector && t.Kind != KindList {
+			break
+		}
+		depth := merkle.GeneralizedIndexDepth(gindex)
+
+		if t.HasLength() {
+			// The first step picks the data tree (left) or the length (right)
+			if gindex>>uint(depth-1)&1 == 1 {
+				if depth > 1 {
+					return nil, 0, fmt.Errorf("generalized index points below the length of %s", t.Name)
+				}
+				return append(path, LengthPathElement), 1, nil
+			}
+			depth--
+			gindex = uint64(1)<<uint(depth) | gindex&(uint64(1)<<uint(depth)-1)
+		}
+
+		treeDepth := t.Depth()
+		if depth < treeDepth {
+			return nil, 0, fmt.Errorf("generalized index points at an internal node of %s below %q",
+				t.Name, FormatPath(path))
+		}
+		below := depth - treeDepth
+		position := gindex>>uint(below) - uint64(1)<<uint(treeDepth)
+		gindex = uint64(1)<<uint(below) | gindex&(uint64(1)<<uint(below)-1)
+
+		switch {
+		case t.Kind == KindContainer:
+			if position >= uint64(len(t.Fields)) {
+				return nil, 0, fmt.Errorf("generalized index points at padding after the fields of %s", t.Name)
+			}
+			path = append(path, t.Fields[position].Name)
+			t = t.Fields[position].Type
+
+		case t.Elem.IsBasic():
+			// Basic elements are packed; the chunk is named by its first element
+			index := position * uint64(32/t.Elem.Size)
+			if index >= t.Length {
+				return nil, 0, fmt.Errorf("generalized index points at padding after the elements of %s", t.Name)
+			}
+			path = append(path, strconv.FormatUint(index, 10))
+			if gindex > 1 {
+				return nil, 0, fmt.Errorf("generalized index points below the packed chunk %q", FormatPath(path))
+			}
+			return path, 1, nil
+
+		default:
+			if position >= t.Length {
+				return nil, 0, fmt.Errorf("generalized index points at padding after the elements of %s", t.Name)
+			}
+			path = append(path, strconv.FormatUint(position, 10))
+			t = t.Elem
+		}
+	}
+	return path, gindex, nil
+}
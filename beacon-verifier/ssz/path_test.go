@@ -0,0 +1,119 @@
+package ssz
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"slot", []string{"slot"}},
+		{"body.execution_payload.fee_recipient", []string{"body", "execution_payload", "fee_recipient"}},
+		{"state.validators[123].effective_balance", []string{"state", "validators", "123", "effective_balance"}},
+		{"body.blob_kzg_commitments[2]", []string{"body", "blob_kzg_commitments", "2"}},
+		{"matrix[1][2]", []string{"matrix", "1", "2"}},
+		{"validators.__len__", []string{"validators", "__len__"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePath(tt.in)
+			if err != nil {
+				t.Fatalf("ParsePath() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParsePath() = %v, want %v", got, tt.want)
+			}
+			if FormatPath(got) != tt.in {
+				t.Errorf("FormatPath() = %s, want %s", FormatPath(got), tt.in)
+			}
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, in := range []string{"", ".slot", "body..root", "list[", "list[]", "list[x]", "list[1]x", "[1]"} {
+		if _, err := ParsePath(in); err == nil {
+			t.Errorf("ParsePath(%q) expected error, got nil", in)
+		}
+	}
+}
+
+func TestPathOf(t *testing.T) {
+	tests := []struct {
+		path []string
+		rest uint64
+	}{
+		{nil, 1},
+		{[]string{"number"}, 1},
+		{[]string{"headers", "__len__"}, 1},
+		{[]string{"headers", "1"}, 1},
+		{[]string{"headers", "1", "slot"}, 1},
+		{[]string{"balances", "4"}, 1},
+		{[]string{"commitments", "1"}, 1},
+		{[]string{"extra"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(FormatPath(tt.path), func(t *testing.T) {
+			gindex, err := testBodyType.GeneralizedIndex(tt.path...)
+			if err != nil {
+				t.Fatalf("GeneralizedIndex() error = %v", err)
+			}
+			got, rest, err := testBodyType.PathOf(gindex)
+			if err != nil {
+				t.Fatalf("PathOf(%d) error = %v", gindex, err)
+			}
+			if !slices.Equal(got, tt.path) || rest != tt.rest {
+				t.Errorf("PathOf(%d) = %v, %d, want %v, %d", gindex, got, rest, tt.path, tt.rest)
+			}
+		})
+	}
+
+	t.Run("Below a leaf", func(t *testing.T) {
+		// The second chunk of a 48-byte commitment
+		gindex, _ := testBodyType.GeneralizedIndex("commitments", "1")
+		got, rest, err := testBodyType.PathOf(gindex<<1 | 1)
+		if err != nil {
+			t.Fatalf("PathOf() error = %v", err)
+		}
+		if !slices.Equal(got, []string{"commitments", "1"}) || rest != 3 {
+			t.Errorf("PathOf() = %v, %d, want [commitments 1], 3", got, rest)
+		}
+	})
+
+	t.Run("Packed chunk names its first element", func(t *testing.T) {
+		gindex, _ := testBodyType.GeneralizedIndex("balances", "6")
+		got, _, err := testBodyType.PathOf(gindex)
+		if err != nil {
+			t.Fatalf("PathOf() error = %v", err)
+		}
+		if !slices.Equal(got, []string{"balances", "4"}) {
+			t.Errorf("PathOf() = %v, want [balances 4]", got)
+		}
+	})
+}
+
+func TestPathOfErrors(t *testing.T) {
+	headers, _ := testBodyType.GeneralizedIndex("headers")
+	tests := []struct {
+		name   string
+		gindex uint64
+	}{
+		{"Zero", 0},
+		{"Internal node", 2},
+		{"Field padding", 15},
+		{"Below list length", headers<<2 | 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := testBodyType.PathOf(tt.gindex); err == nil {
+				t.Errorf("PathOf(%d) expected error, got nil", tt.gindex)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package ssz
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+// Prove returns the node at gindex in the tree of a value of type t together
+// with its bottom-up sibling branch. Only the subtrees along the path are
+// descended into; everything else is hashed once to produce siblings.
+func Prove(t *Type, v any, gindex uint64) ([32]byte, [][32]byte, error) {
+	if gindex == 0 {
+		return [32]byte{}, nil, fmt.Errorf("generalized index 0 is invalid")
+	}
+	if gindex == 1 {
+		root, err := HashTreeRoot(t, v)
+		return root, nil, err
+	}
+	if t.IsBasic() {
+		return [32]byte{}, nil, fmt.Errorf("cannot descend into basic type %s", t.Name)
+	}
+
+	chunks, length, err := dataChunks(t, v)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	if !t.HasLength() {
+		return proveData(t, v, chunks, gindex)
+	}
+
+	// Lists hang their data tree off the left of the root and the length
+	// chunk off the right
+	depth := merkle.GeneralizedIndexDepth(gindex)
+	lengthChunk := merkle.LengthChunk(length)
+	switch {
+	case gindex == 3:
+		dataRoot, err := merkle.Merkleize(chunks, t.ChunkLimit())
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+		return lengthChunk, [][32]byte{dataRoot}, nil
+	case (gindex>>uint(depth-1))&1 == 1:
+		return [32]byte{}, nil, fmt.Errorf("generalized index %d points below the length of %s", gindex, t.Name)
+	}
+
+	dataIndex := uint64(1)<<uint(depth-1) | gindex&(uint64(1)<<uint(depth-1)-1)
+	leaf, branch, err := proveData(t, v, chunks, dataIndex)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	return leaf, append(branch, lengthChunk), nil
+}
+
+// proveData proves a node of the data tree of t, whose chunks are given
+func proveData(t *Type, v any, chunks [][32]byte, gindex uint64) ([32]byte, [][32]byte, error) {
+	treeDepth := t.Depth()
+	depth := merkle.GeneralizedIndexDepth(gindex)
+
+	if depth <= treeDepth {
+		// The node is inside this type's own data tree: prove the leftmost
+		// chunk below it and fold the lower part of the branch into the node
+		extra := treeDepth - depth
+		position := merkle.GeneralizedIndexPosition(gindex) << uint(extra)
+		_, branch, err := merkle.MerkleizeWithProof(chunks, t.ChunkLimit(), position)
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+		var node [32]byte
+		if position < uint64(len(chunks)) {
+			node = chunks[position]
+		}
+		for i := 0; i < extra; i++ {
+			node = merkle.HashPair(node, branch[i])
+		}
+		return node, branch[extra:], nil
+	}
+
+	// The node is inside one of the children: prove it there first
+	below := depth - treeDepth
+	position := (gindex >> uint(below)) - uint64(1)<<uint(treeDepth)
+	childIndex := uint64(1)<<uint(below) | gindex&(uint64(1)<<uint(below)-1)
+
+	childType, childValue, err := childAt(t, v, position)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	leaf, childBranch, err := Prove(childType, childValue, childIndex)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	_, branch, err := merkle.MerkleizeWithProof(chunks, t.ChunkLimit(), position)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	return leaf, append(childBranch, branch...), nil
+}
+
+// childAt returns the composite child stored at a chunk position
+func childAt(t *Type, v any, position uint64) (*Type, any, error) {
+	switch t.Kind {
+	case KindContainer:
+		if position >= uint64(len(t.Fields)) {
+			return nil, nil, fmt.Errorf("position %d is padding in %s", position, t.Name)
+		}
+		f := t.Fields[position]
+		return f.Type, v.(map[string]any)[f.Name], nil
+
+	case KindVector, KindList:
+		if t.Elem.IsBasic() {
+			break
+		}
+		items := v.([]any)
+		if position >= uint64(len(items)) {
+			return nil, nil, fmt.Errorf("index %d is out of range for %s of length %d", position, t.Name, len(items))
+		}
+		return t.Elem, items[position], nil
+	}
+	return nil, nil, fmt.Errorf("cannot descend into a chunk of %s", t.Name)
+}
+
+// Value returns the JSON value found at a path, mirroring Locate
+func Value(t *Type, v any, path ...string) (any, error) {
+	for _, elem := range path {
+		switch {
+		case elem == LengthPathElement && t.HasLength():
+			switch x := v.(type) {
+			case []any:
+				return strconv.Itoa(len(x)), nil
+			}
+			return nil, fmt.Errorf("%s length is only available for element lists", t.Name)
+
+		case t.Kind == KindContainer:
+			i, ok := t.FieldIndex(elem)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q in %s", elem, t.Name)
+			}
+			fields, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s expects a JSON object, got %T", t.Name, v)
+			}
+			v = fields[elem]
+			t = t.Fields[i].Type
+
+		case t.Kind == KindVector || t.Kind == KindList:
+			index, err := strconv.Atoi(elem)
+			if err != nil {
+				return nil, fmt.Errorf("%s expects an element index, got %q", t.Name, elem)
+			}
+			items, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s expects a JSON array, got %T", t.Name, v)
+			}
+			if index < 0 || index >= len(items) {
+				return nil, fmt.Errorf("index %d is out of range for %s of length %d", index, t.Name, len(items))
+			}
+			v = items[index]
+			t = t.Elem
+
+		default:
+			return nil, fmt.Errorf("cannot descend into %s with %q", t.Name, elem)
+		}
+	}
+	return v, nil
+}
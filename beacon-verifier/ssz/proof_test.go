@@ -0,0 +1,173 @@
+package ssz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+var testBodyType = Container("Body",
+	F("number", Uint64),
+	F("headers", List(testHeaderType, 8)),
+	F("balances", List(Uint64, 64)),
+	F("commitments", List(Bytes(48), 4)),
+	F("extra", ByteList(64)),
+)
+
+func testBodyValue() map[string]any {
+	other := testHeaderValue()
+	other["slot"] = "99"
+	return map[string]any{
+		"number":      "5",
+		"headers":     []any{testHeaderValue(), other},
+		"balances":    []any{"10", "20", "30", "40", "50", "60"},
+		"commitments": []any{"0x" + strings.Repeat("11", 48), "0x" + strings.Repeat("22", 48)},
+		"extra":       "0x0102",
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   []string
+		gindex uint64
+		packed bool
+		offset int
+	}{
+		{"Root", nil, 1, false, 0},
+		{"Field", []string{"number"}, 8, false, 0},
+		{"List length", []string{"headers", "__len__"}, 19, false, 0},
+		{"List element", []string{"headers", "1"}, 9<<4 | 1, false, 0},
+		{"Nested field", []string{"headers", "1", "body_root"}, (9<<4|1)<<3 | 4, false, 0},
+		{"Packed element", []string{"balances", "5"}, 10<<5 | 1, true, 8},
+		{"Bytes48 element", []string{"commitments", "3"}, 11<<3 | 3, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := testBodyType.Locate(tt.path...)
+			if err != nil {
+				t.Fatalf("Locate() error = %v", err)
+			}
+			if loc.GeneralizedIndex != tt.gindex {
+				t.Errorf("Locate() gindex = %d, want %d", loc.GeneralizedIndex, tt.gindex)
+			}
+			if loc.Packed != tt.packed || loc.ByteOffset != tt.offset {
+				t.Errorf("Locate() packed = %v offset = %d, want %v %d", loc.Packed, loc.ByteOffset, tt.packed, tt.offset)
+			}
+		})
+	}
+}
+
+func TestLocateErrors(t *testing.T) {
+	_, err := testBodyType.Locate("nope")
+	if err == nil || !strings.Contains(err.Error(), "valid fields: number, headers, balances, commitments, extra") {
+		t.Errorf("Locate() error = %v, want one listing the valid fields", err)
+	}
+
+	for _, path := range [][]string{
+		{"headers", "8"},
+		{"headers", "x"},
+		{"number", "__len__"},
+		{"balances", "1", "x"},
+	} {
+		if _, err := testBodyType.Locate(path...); err == nil {
+			t.Errorf("Locate(%v) expected error, got nil", path)
+		}
+	}
+}
+
+func TestProveEveryPath(t *testing.T) {
+	value := testBodyValue()
+	root, err := HashTreeRoot(testBodyType, value)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	paths := [][]string{
+		{"number"},
+		{"headers"},
+		{"headers", "__len__"},
+		{"headers", "0"},
+		{"headers", "1", "slot"},
+		{"headers", "1", "body_root"},
+		{"balances", "5"},
+		{"commitments", "1"},
+		{"extra"},
+	}
+
+	for _, path := range paths {
+		t.Run(FormatPath(path), func(t *testing.T) {
+			loc, err := testBodyType.Locate(path...)
+			if err != nil {
+				t.Fatalf("Locate() error = %v", err)
+			}
+			leaf, branch, err := Prove(testBodyType, value, loc.GeneralizedIndex)
+			if err != nil {
+				t.Fatalf("Prove() error = %v", err)
+			}
+			if !merkle.VerifyBranch(leaf, branch, loc.GeneralizedIndex, root) {
+				t.Errorf("branch for %v does not verify", path)
+			}
+
+			if !loc.Packed {
+				v, err := Value(testBodyType, value, path...)
+				if err != nil {
+					t.Fatalf("Value() error = %v", err)
+				}
+				want, err := HashTreeRoot(loc.Type, v)
+				if err != nil {
+					t.Fatalf("HashTreeRoot() error = %v", err)
+				}
+				if leaf != want {
+					t.Errorf("leaf = %x, want hash tree root of the value %x", leaf, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProveInternalNodes(t *testing.T) {
+	value := testBodyValue()
+	root, err := HashTreeRoot(testBodyType, value)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+
+	// Every node of the top two levels and a few inside lists
+	for _, gindex := range []uint64{2, 3, 4, 5, 6, 7, 18, 36, 37} {
+		leaf, branch, err := Prove(testBodyType, value, gindex)
+		if err != nil {
+			t.Fatalf("Prove(%d) error = %v", gindex, err)
+		}
+		if !merkle.VerifyBranch(leaf, branch, gindex, root) {
+			t.Errorf("branch for gindex %d does not verify", gindex)
+		}
+	}
+}
+
+func TestProveErrors(t *testing.T) {
+	value := testBodyValue()
+
+	if _, _, err := Prove(testBodyType, value, 0); err == nil {
+		t.Errorf("Prove(0) expected error, got nil")
+	}
+
+	// headers[5] is within the limit but past the current length
+	gindex, err := testBodyType.GeneralizedIndex("headers", "5", "slot")
+	if err != nil {
+		t.Fatalf("GeneralizedIndex() error = %v", err)
+	}
+	if _, _, err := Prove(testBodyType, value, gindex); err == nil {
+		t.Errorf("Prove() expected error for an element past the list length")
+	}
+}
+
+func TestFormatPath(t *testing.T) {
+	got := FormatPath([]string{"execution_requests", "deposits", "0", "amount"})
+	want := "execution_requests.deposits[0].amount"
+	if got != want {
+		t.Errorf("FormatPath() = %q, want %q", got, want)
+	}
+}
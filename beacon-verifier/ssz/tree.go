@@ -0,0 +1,291 @@
+package ssz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+// segmentDepth sets how many chunks of a packed type, or elements of a list
+// or vector of fixed-size values, share one cached root: 2^segmentDepth.
+// Larger segments cache fewer roots; smaller ones rehash less per change.
+const segmentDepth = 8
+
+// Tree is a serialized SSZ value that remembers the roots of its subtrees.
+// It holds the serialized bytes plus one root per container field and per
+// segment of a long list, never a node per chunk, so a mainnet state costs
+// little more than its own size. Built from a previous tree of the same
+// type, typically the state at an adjacent slot, it only rehashes the
+// segments whose bytes changed.
+type Tree struct {
+	typ      *Type
+	data     []byte
+	root     [32]byte
+	children []*Tree    // container fields and variable-size elements
+	segments [][32]byte // segment roots of packed types and fixed-size elements
+	stats    TreeStats
+}
+
+// TreeStats counts the segments a tree hashed and those it took unchanged
+// from the previous tree
+type TreeStats struct {
+	Hashed int `json:"hashed"`
+	Reused int `json:"reused"`
+}
+
+// NewTree reads serialized data of type t and computes its root, reusing
+// the roots of unchanged segments of prev when prev is not nil. The tree
+// keeps a reference to data, which must not be modified afterwards; prev
+// can be dropped once NewTree returns.
+func NewTree(t *Type, data []byte, prev *Tree) (*Tree, error) {
+	if prev != nil && prev.typ != t {
+		prev = nil
+	}
+	tr := &Tree{typ: t, data: data}
+
+	var err error
+	switch {
+	case t.IsBasic():
+		var chunk [32]byte
+		if size, _ := t.FixedSize(); len(data) != size {
+			return nil, fmt.Errorf("%s has %d bytes, expected %d", t.Name, len(data), size)
+		}
+		copy(chunk[:], data)
+		tr.root = chunk
+		return tr, nil
+
+	case t.isPacked():
+		err = tr.hashSegments(prev, 32, func(b []byte) ([32]byte, error) {
+			return merkle.Merkleize(merkle.Pack(b), uint64(1)<<segmentDepthOf(t))
+		})
+
+	case t.Kind == KindContainer:
+		var parts [][]byte
+		if parts, err = splitContainer(t, data); err == nil {
+			err = tr.hashChildren(prev, parts, func(i int) *Type { return t.Fields[i].Type })
+		}
+
+	default:
+		if size, fixed := t.Elem.FixedSize(); fixed {
+			err = tr.hashSegments(prev, size, func(b []byte) ([32]byte, error) {
+				return hashElements(t.Elem, size, b, segmentDepthOf(t))
+			})
+			break
+		}
+		var parts [][]byte
+		if parts, err = splitElements(t, data); err == nil {
+			err = tr.hashChildren(prev, parts, func(int) *Type { return t.Elem })
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// hashSegments hashes the data of t in segments of 2^segmentDepth units of
+// unitSize bytes: chunks for packed types, elements otherwise
+func (tr *Tree) hashSegments(prev *Tree, unitSize int, hash func([]byte) ([32]byte, error)) error {
+	t := tr.typ
+	content, length, err := tr.content()
+	if err != nil {
+		return err
+	}
+	var previous []byte
+	if prev != nil {
+		previous, _, _ = prev.content()
+	}
+
+	height := segmentDepthOf(t)
+	segmentSize := unitSize << uint(height)
+	if prev != nil && bytes.Equal(previous, content) {
+		tr.segments = prev.segments
+		tr.stats.Reused = len(prev.segments)
+	} else {
+		tr.segments = make([][32]byte, (len(content)+segmentSize-1)/segmentSize)
+		for i := range tr.segments {
+			start, end := i*segmentSize, min((i+1)*segmentSize, len(content))
+			// A segment is reused only if it covered exactly the same bytes
+			if prev != nil && i < len(prev.segments) && min((i+1)*segmentSize, len(previous)) == end &&
+				bytes.Equal(previous[start:end], content[start:end]) {
+				tr.segments[i] = prev.segments[i]
+				tr.stats.Reused++
+				continue
+			}
+			if tr.segments[i], err = hash(content[start:end]); err != nil {
+				return fmt.Errorf("%s: %w", t.Name, err)
+			}
+			tr.stats.Hashed++
+		}
+	}
+
+	tr.root, err = merkle.MerkleizeSubtrees(tr.segments, height, t.Depth()-height)
+	if err != nil {
+		return fmt.Errorf("merkleizing %s: %w", t.Name, err)
+	}
+	if t.HasLength() {
+		tr.root = merkle.MixInLength(tr.root, length)
+	}
+	return nil
+}
+
+// content validates the data of a segmented tree and returns the bytes its
+// segments cover and the length it mixes in
+func (tr *Tree) content() ([]byte, uint64, error) {
+	t := tr.typ
+	if t.isPacked() {
+		return packed(t, tr.data)
+	}
+	size, _ := t.Elem.FixedSize()
+	if len(tr.data)%size != 0 {
+		return nil, 0, fmt.Errorf("%s has %d bytes, not a multiple of %d", t.Name, len(tr.data), size)
+	}
+	count := uint64(len(tr.data) / size)
+	if err := checkCount(t, count); err != nil {
+		return nil, 0, err
+	}
+	return tr.data, count, nil
+}
+
+// hashChildren builds a subtree per part, reusing the matching subtrees of
+// prev, and merkleizes their roots
+func (tr *Tree) hashChildren(prev *Tree, parts [][]byte, typeOf func(int) *Type) error {
+	t := tr.typ
+	tr.children = make([]*Tree, len(parts))
+	roots := make([][32]byte, len(parts))
+	for i, part := range parts {
+		var previous *Tree
+		if prev != nil && i < len(prev.children) {
+			previous = prev.children[i]
+		}
+		child, err := NewTree(typeOf(i), part, previous)
+		if err != nil {
+			if t.Kind == KindContainer {
+				return fmt.Errorf("%s.%s: %w", t.Name, t.Fields[i].Name, err)
+			}
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+		tr.children[i] = child
+		roots[i] = child.root
+		tr.stats.Hashed += child.stats.Hashed
+		tr.stats.Reused += child.stats.Reused
+	}
+
+	root, err := merkle.Merkleize(roots, t.ChunkLimit())
+	if err != nil {
+		return fmt.Errorf("merkleizing %s: %w", t.Name, err)
+	}
+	if t.HasLength() {
+		root = merkle.MixInLength(root, uint64(len(parts)))
+	}
+	tr.root = root
+	return nil
+}
+
+// segmentDepthOf returns the height of the segments of t, which never
+// exceeds its data tree
+func segmentDepthOf(t *Type) int {
+	return min(segmentDepth, t.Depth())
+}
+
+// hashElements returns the root of one segment of fixed-size elements
+func hashElements(elem *Type, size int, data []byte, height int) ([32]byte, error) {
+	roots := make([][32]byte, len(data)/size)
+	for i := range roots {
+		root, err := hashSerialized(elem, data[i*size:(i+1)*size])
+		if err != nil {
+			return [32]byte{}, err
+		}
+		roots[i] = root
+	}
+	return merkle.Merkleize(roots, uint64(1)<<uint(height))
+}
+
+// hashSerialized computes the hash tree root of a small serialized value
+// without building or caching a tree
+func hashSerialized(t *Type, data []byte) ([32]byte, error) {
+	if t.IsBasic() {
+		tr, err := NewTree(t, data, nil)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		return tr.root, nil
+	}
+
+	var chunks [][32]byte
+	var length uint64
+	if t.isPacked() {
+		content, n, err := packed(t, data)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		chunks, length = merkle.Pack(content), n
+	} else {
+		var parts [][]byte
+		var err error
+		var typeOf func(int) *Type
+		if t.Kind == KindContainer {
+			parts, err = splitContainer(t, data)
+			typeOf = func(i int) *Type { return t.Fields[i].Type }
+		} else {
+			parts, err = splitElements(t, data)
+			typeOf = func(int) *Type { return t.Elem }
+		}
+		if err != nil {
+			return [32]byte{}, err
+		}
+		chunks, length = make([][32]byte, len(parts)), uint64(len(parts))
+		for i, part := range parts {
+			if chunks[i], err = hashSerialized(typeOf(i), part); err != nil {
+				return [32]byte{}, err
+			}
+		}
+	}
+
+	root, err := merkle.Merkleize(chunks, t.ChunkLimit())
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("merkleizing %s: %w", t.Name, err)
+	}
+	if t.HasLength() {
+		root = merkle.MixInLength(root, length)
+	}
+	return root, nil
+}
+
+// Root returns the hash tree root of the value
+func (tr *Tree) Root() [32]byte {
+	return tr.root
+}
+
+// Type returns the type of the value
+func (tr *Tree) Type() *Type {
+	return tr.typ
+}
+
+// Bytes returns the serialized value
+func (tr *Tree) Bytes() []byte {
+	return tr.data
+}
+
+// Stats returns how many segments were hashed and reused building the tree
+func (tr *Tree) Stats() TreeStats {
+	return tr.stats
+}
+
+// Field returns the subtree of a container field
+func (tr *Tree) Field(name string) (*Tree, error) {
+	if tr.typ.Kind != KindContainer {
+		return nil, fmt.Errorf("%s is not a container", tr.typ.Name)
+	}
+	i, ok := tr.typ.FieldIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no field %q", tr.typ.Name, name)
+	}
+	return tr.children[i], nil
+}
+
+// Value decodes the value into the generic JSON shape
+func (tr *Tree) Value() (any, error) {
+	return Unmarshal(tr.typ, tr.data)
+}
@@ -0,0 +1,134 @@
+package ssz
+
+import (
+	"strconv"
+	"testing"
+)
+
+// testLargeType has lists long enough to span several segments
+var testLargeType = Container("Large",
+	F("slot", Uint64),
+	F("balances", List(Uint64, 1<<40)),
+	F("headers", List(testHeaderType, 1<<20)),
+	F("mixes", Vector(Bytes(32), 1024)),
+	F("bodies", List(testBodyType, 4)),
+)
+
+func testLargeValue(balances, headers int) map[string]any {
+	value := Zero(testLargeType).(map[string]any)
+	items := make([]any, balances)
+	for i := range items {
+		items[i] = strconv.Itoa(32000000000 + i)
+	}
+	value["balances"] = items
+	list := make([]any, headers)
+	for i := range list {
+		header := testHeaderValue()
+		header["slot"] = strconv.Itoa(i)
+		list[i] = header
+	}
+	value["headers"] = list
+	value["bodies"] = []any{testBodyValue(), Zero(testBodyType)}
+	return value
+}
+
+// newTestTree serializes value and checks that the tree root matches
+// HashTreeRoot of the JSON value
+func newTestTree(t *testing.T, typ *Type, value any, prev *Tree) *Tree {
+	t.Helper()
+	data, err := Marshal(typ, value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	tree, err := NewTree(typ, data, prev)
+	if err != nil {
+		t.Fatalf("NewTree() error = %v", err)
+	}
+	want, err := HashTreeRoot(typ, value)
+	if err != nil {
+		t.Fatalf("HashTreeRoot() error = %v", err)
+	}
+	if tree.Root() != want {
+		t.Errorf("Root() = %x, want %x", tree.Root(), want)
+	}
+	return tree
+}
+
+func TestTreeRoot(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   *Type
+		value any
+	}{
+		{"Basic", Uint64, "7"},
+		{"Header", testHeaderType, testHeaderValue()},
+		{"Body", testBodyType, testBodyValue()},
+		{"Nested", testNestedType, testNestedValue()},
+		{"Empty", testLargeType, Zero(testLargeType)},
+		{"Large", testLargeType, testLargeValue(2000, 700)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestTree(t, tt.typ, tt.value, nil)
+		})
+	}
+}
+
+func TestTreeReuse(t *testing.T) {
+	value := testLargeValue(2000, 700)
+	first := newTestTree(t, testLargeType, value, nil)
+	// 2000 balances fill 500 chunks (2 segments), 700 headers 3 segments,
+	// 1024 mixes 4 segments and the lists of the first body 4 more
+	if got := first.Stats(); got.Hashed != 13 || got.Reused != 0 {
+		t.Errorf("first Stats() = %+v, want 13 hashed", got)
+	}
+
+	unchanged := newTestTree(t, testLargeType, value, first)
+	if got := unchanged.Stats(); got.Hashed != 0 || got.Reused != 13 {
+		t.Errorf("unchanged Stats() = %+v, want 13 reused", got)
+	}
+
+	value["slot"] = "1"
+	value["balances"].([]any)[1500] = "1"
+	value["headers"].([]any)[300].(map[string]any)["proposer_index"] = "7"
+	changed := newTestTree(t, testLargeType, value, unchanged)
+	if got := changed.Stats(); got.Hashed != 2 || got.Reused != 11 {
+		t.Errorf("changed Stats() = %+v, want 2 hashed and 11 reused", got)
+	}
+
+	// Growing a list rehashes its last segment; the header changed back
+	grown := newTestTree(t, testLargeType, testLargeValue(2001, 700), changed)
+	if got := grown.Stats(); got.Hashed != 2 || got.Reused != 11 {
+		t.Errorf("grown Stats() = %+v, want 2 hashed and 11 reused", got)
+	}
+	newTestTree(t, testLargeType, testLargeValue(1000, 10), grown)
+}
+
+func TestTreeField(t *testing.T) {
+	tree := newTestTree(t, testBodyType, testBodyValue(), nil)
+
+	extra, err := tree.Field("extra")
+	if err != nil {
+		t.Fatalf("Field() error = %v", err)
+	}
+	value, err := extra.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if value != "0x0102" {
+		t.Errorf("Field(extra).Value() = %v, want 0x0102", value)
+	}
+	if _, err := tree.Field("missing"); err == nil {
+		t.Error("Field(missing) expected error, got nil")
+	}
+}
+
+func TestTreeErrors(t *testing.T) {
+	if _, err := NewTree(testHeaderType, make([]byte, 100), nil); err == nil {
+		t.Error("NewTree() expected error for a truncated header, got nil")
+	}
+	if _, err := NewTree(List(Uint64, 4), make([]byte, 40), nil); err == nil {
+		t.Error("NewTree() expected error for a list beyond its limit, got nil")
+	}
+}
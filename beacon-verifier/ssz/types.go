@@ -0,0 +1,262 @@
+// Package ssz describes SSZ types and computes hash tree roots, generalized
+// indices and Merkle proofs for values decoded from beacon API JSON
+package ssz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mikelle/beacon-root-verification/beacon-verifier/merkle"
+)
+
+// Kind identifies the SSZ shape of a Type
+type Kind int
+
+const (
+	KindUint Kind = iota
+	KindBoolean
+	KindByteVector
+	KindByteList
+	KindBitvector
+	KindBitlist
+	KindVector
+	KindList
+	KindContainer
+)
+
+// LengthPathElement addresses the length mix-in of a list
+const LengthPathElement = "__len__"
+
+// Type describes an SSZ type
+type Type struct {
+	Name   string
+	Kind   Kind
+	Size   int    // byte width of a uint, length of a byte vector
+	Length uint64 // vector or bitvector length, list, byte list or bitlist limit
+	Elem   *Type
+	Fields []Field
+}
+
+// Field is a named member of a container
+type Field struct {
+	Name string
+	Type *Type
+}
+
+// Basic types
+var (
+	Uint8   = &Type{Name: "uint8", Kind: KindUint, Size: 1}
+	Uint64  = &Type{Name: "uint64", Kind: KindUint, Size: 8}
+	Uint256 = &Type{Name: "uint256", Kind: KindUint, Size: 32}
+	Boolean = &Type{Name: "boolean", Kind: KindBoolean, Size: 1}
+)
+
+// F is shorthand for declaring a container field
+func F(name string, t *Type) Field {
+	return Field{Name: name, Type: t}
+}
+
+// Bytes returns the ByteVector[n] type
+func Bytes(n int) *Type {
+	return &Type{Name: fmt.Sprintf("Bytes%d", n), Kind: KindByteVector, Size: n}
+}
+
+// ByteList returns the ByteList[limit] type
+func ByteList(limit uint64) *Type {
+	return &Type{Name: fmt.Sprintf("ByteList[%d]", limit), Kind: KindByteList, Length: limit}
+}
+
+// Bitvector returns the Bitvector[n] type
+func Bitvector(n uint64) *Type {
+	return &Type{Name: fmt.Sprintf("Bitvector[%d]", n), Kind: KindBitvector, Length: n}
+}
+
+// Bitlist returns the Bitlist[limit] type
+func Bitlist(limit uint64) *Type {
+	return &Type{Name: fmt.Sprintf("Bitlist[%d]", limit), Kind: KindBitlist, Length: limit}
+}
+
+// Vector returns the Vector[elem, n] type
+func Vector(elem *Type, n uint64) *Type {
+	return &Type{Name: fmt.Sprintf("Vector[%s, %d]", elem.Name, n), Kind: KindVector, Length: n, Elem: elem}
+}
+
+// List returns the List[elem, limit] type
+func List(elem *Type, limit uint64) *Type {
+	return &Type{Name: fmt.Sprintf("List[%s, %d]", elem.Name, limit), Kind: KindList, Length: limit, Elem: elem}
+}
+
+// Container returns a container type with the given fields in order
+func Container(name string, fields ...Field) *Type {
+	return &Type{Name: name, Kind: KindContainer, Fields: fields}
+}
+
+// Extend returns a new container with extra fields appended, the way forks
+// grow containers
+func (t *Type) Extend(name string, fields ...Field) *Type {
+	all := make([]Field, 0, len(t.Fields)+len(fields))
+	all = append(all, t.Fields...)
+	all = append(all, fields...)
+	return Container(name, all...)
+}
+
+// IsBasic reports whether the type is a uint or boolean
+func (t *Type) IsBasic() bool {
+	return t.Kind == KindUint || t.Kind == KindBoolean
+}
+
+// HasLength reports whether the root of the type mixes in a length
+func (t *Type) HasLength() bool {
+	return t.Kind == KindList || t.Kind == KindByteList || t.Kind == KindBitlist
+}
+
+// ChunkLimit returns the number of chunks in the data tree of the type
+func (t *Type) ChunkLimit() uint64 {
+	switch t.Kind {
+	case KindUint, KindBoolean:
+		return 1
+	case KindByteVector:
+		return (uint64(t.Size) + 31) / 32
+	case KindByteList:
+		return (t.Length + 31) / 32
+	case KindBitvector, KindBitlist:
+		return (t.Length + 255) / 256
+	case KindVector, KindList:
+		if t.Elem.IsBasic() {
+			return (t.Length*uint64(t.Elem.Size) + 31) / 32
+		}
+		return t.Length
+	case KindContainer:
+		return uint64(len(t.Fields))
+	}
+	return 0
+}
+
+// Depth returns the depth of the data tree of the type, not counting the
+// length mix-in of lists
+func (t *Type) Depth() int {
+	return merkle.DepthForLimit(t.ChunkLimit())
+}
+
+// FieldIndex returns the position of a container field
+func (t *Type) FieldIndex(name string) (int, bool) {
+	for i, f := range t.Fields {
+		if f.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// FieldNames returns the container field names in order
+func (t *Type) FieldNames() []string {
+	names := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Location describes where a path ends up inside the Merkle tree of a type
+type Location struct {
+	GeneralizedIndex uint64
+	Type             *Type
+	// Packed is set for basic values sharing a chunk with their neighbours;
+	// ByteOffset is then the position of the value inside the leaf chunk
+	Packed     bool
+	ByteOffset int
+}
+
+// Locate resolves a path of field names, element indices and __len__ into a
+// generalized index relative to the root of t
+func (t *Type) Locate(path ...string) (Location, error) {
+	loc := Location{GeneralizedIndex: 1, Type: t}
+	for _, elem := range path {
+		if loc.Packed {
+			return Location{}, fmt.Errorf("cannot descend into %s value with %q", loc.Type.Name, elem)
+		}
+		child, err := loc.Type.child(elem)
+		if err != nil {
+			return Location{}, err
+		}
+		gindex, err := merkle.ConcatGeneralizedIndices(loc.GeneralizedIndex, child.GeneralizedIndex)
+		if err != nil {
+			return Location{}, err
+		}
+		child.GeneralizedIndex = gindex
+		loc = child
+	}
+	return loc, nil
+}
+
+// GeneralizedIndex is a convenience wrapper around Locate
+func (t *Type) GeneralizedIndex(path ...string) (uint64, error) {
+	loc, err := t.Locate(path...)
+	if err != nil {
+		return 0, err
+	}
+	return loc.GeneralizedIndex, nil
+}
+
+// child resolves one path element relative to the root of t
+func (t *Type) child(elem string) (Location, error) {
+	if elem == LengthPathElement {
+		if !t.HasLength() {
+			return Location{}, fmt.Errorf("%s has no %s", t.Name, LengthPathElement)
+		}
+		return Location{GeneralizedIndex: 3, Type: Uint64}, nil
+	}
+
+	base := uint64(1) << uint(t.Depth())
+	if t.HasLength() {
+		base *= 2
+	}
+
+	switch t.Kind {
+	case KindContainer:
+		i, ok := t.FieldIndex(elem)
+		if !ok {
+			return Location{}, fmt.Errorf("unknown field %q in %s; valid fields: %s",
+				elem, t.Name, strings.Join(t.FieldNames(), ", "))
+		}
+		return Location{GeneralizedIndex: base + uint64(i), Type: t.Fields[i].Type}, nil
+
+	case KindVector, KindList:
+		index, err := strconv.ParseUint(elem, 10, 64)
+		if err != nil {
+			return Location{}, fmt.Errorf("%s expects an element index, got %q", t.Name, elem)
+		}
+		if index >= t.Length {
+			return Location{}, fmt.Errorf("index %d is out of range for %s", index, t.Name)
+		}
+		if t.Elem.IsBasic() {
+			perChunk := uint64(32 / t.Elem.Size)
+			return Location{
+				GeneralizedIndex: base + index/perChunk,
+				Type:             t.Elem,
+				Packed:           true,
+				ByteOffset:       int(index%perChunk) * t.Elem.Size,
+			}, nil
+		}
+		return Location{GeneralizedIndex: base + index, Type: t.Elem}, nil
+	}
+
+	return Location{}, fmt.Errorf("cannot descend into %s with %q", t.Name, elem)
+}
+
+// FormatPath renders path elements as a dotted path with [i] for indices
+func FormatPath(path []string) string {
+	var b strings.Builder
+	for _, elem := range path {
+		if _, err := strconv.ParseUint(elem, 10, 64); err == nil {
+			b.WriteString("[" + elem + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(elem)
+	}
+	return b.String()
+}
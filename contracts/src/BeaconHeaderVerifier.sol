@@ -59,6 +59,32 @@ contract BeaconHeaderVerifier {
         return verifySSZMerkleProof(beaconBlockRoot, fieldIndex, fieldValue, merkleProof);
     }
     
+    /**
+     * @dev Verifies any node below the beacon block root, e.g. an execution
+     *      request inside the block body, using its generalized index
+     * @param beaconTimestamp The timestamp of the beacon block
+     * @param generalizedIndex The generalized index of the leaf relative to the block root
+     * @param leaf The leaf value (hash tree root of the proven object)
+     * @param merkleProof The merkle proof from the leaf to the block root
+     * @return True if the proof is valid
+     */
+    function verifyProof(
+        uint256 beaconTimestamp,
+        uint256 generalizedIndex,
+        bytes32 leaf,
+        bytes32[] calldata merkleProof
+    ) public view returns (bool) {
+        require(generalizedIndex > 1, "Invalid generalized index");
+        require(
+            merkleProof.length == generalizedIndexDepth(generalizedIndex),
+            "Proof length does not match generalized index"
+        );
+
+        bytes32 beaconBlockRoot = getBeaconBlockRoot(beaconTimestamp);
+
+        return verifySSZMerkleProof(beaconBlockRoot, generalizedIndex, leaf, merkleProof);
+    }
+
     /**
      * @dev Verifies the slot of a beacon block header
      * @param beaconTimestamp The timestamp of the beacon block
@@ -140,6 +166,18 @@ contract BeaconHeaderVerifier {
         return verifyHeaderField(beaconTimestamp, BODY_ROOT_INDEX, bodyRoot, merkleProof);
     }
     
+    /**
+     * @dev Returns the depth of a generalized index (floor(log2(index)))
+     * @param generalizedIndex The generalized index
+     * @return depth The number of proof elements needed for the index
+     */
+    function generalizedIndexDepth(uint256 generalizedIndex) internal pure returns (uint256 depth) {
+        while (generalizedIndex > 1) {
+            generalizedIndex >>= 1;
+            depth++;
+        }
+    }
+
     /**
      * @dev Compute the hash of two nodes in a merkle tree
      * @param left The left node
@@ -1,8 +1,6 @@
 // SPDX-License-Identifier: MIT
 pragma solidity ^0.8.26;
 
-import {SSZMerkleProof} from "./lib/ssz.sol";
-
 /**
  * @title BeaconRANDAOVerifier
  * @dev Contract to verify RANDAO values from the beacon chain
@@ -13,6 +11,9 @@ contract BeaconRANDAOVerifier {
 
     // Constants for verification
     uint256 private constant RANDAO_MIXES_INDEX = 13; // Index of randao_mixes in BeaconState
+    uint64 private constant STATE_ROOT_GINDEX = 11; // state_root in BeaconBlockHeader
+    uint64 private constant EPOCHS_PER_HISTORICAL_VECTOR = 65536; // 2^16
+    uint8 private constant RANDAO_MIXES_DEPTH = 16; // log2(EPOCHS_PER_HISTORICAL_VECTOR)
 
     /**
      * @dev Constructor
@@ -97,10 +98,10 @@ contract BeaconRANDAOVerifier {
 
         // Verify the SSZ Merkle proof
         return
-            SSZMerkleProof._verify(
-                merkleProof,
+            verifySSZMerkleProof(
                 beaconRoot,
                 randaoMix,
+                merkleProof,
                 generalizedIndex
             );
     }
@@ -147,32 +148,32 @@ contract BeaconRANDAOVerifier {
      * @param index The generalized index of the leaf
      * @return True if the proof is valid, false otherwise
      */
-    // function verifySSZMerkleProof(
-    //     bytes32 root,
-    //     bytes32 leaf,
-    //     bytes32[] calldata proof,
-    //     uint64 index
-    // ) internal pure returns (bool) {
-    //     // Start with the leaf node
-    //     bytes32 node = leaf;
-
-    //     // Iterate through each proof element
-    //     for (uint256 i = 0; i < proof.length; i++) {
-    //         // Determine if we're dealing with a left or right node
-    //         bool isLeft = (index & 1) == 0;
-    //         index = index / 2;
-
-    //         // Combine the node with the proof element according to its position
-    //         if (isLeft) {
-    //             node = sha256(abi.encodePacked(node, proof[i]));
-    //         } else {
-    //             node = sha256(abi.encodePacked(proof[i], node));
-    //         }
-    //     }
-
-    //     // Check if the computed root matches the expected root
-    //     return node == root;
-    // }
+    function verifySSZMerkleProof(
+        bytes32 root,
+        bytes32 leaf,
+        bytes32[] calldata proof,
+        uint64 index
+    ) internal pure returns (bool) {
+        // Start with the leaf node
+        bytes32 node = leaf;
+
+        // Iterate through each proof element
+        for (uint256 i = 0; i < proof.length; i++) {
+            // Determine if we're dealing with a left or right node
+            bool isLeft = (index & 1) == 0;
+            index = index / 2;
+
+            // Combine the node with the proof element according to its position
+            if (isLeft) {
+                node = sha256(abi.encodePacked(node, proof[i]));
+            } else {
+                node = sha256(abi.encodePacked(proof[i], node));
+            }
+        }
+
+        // Check if the computed root matches the expected root
+        return node == root;
+    }
 
     /**
      * @dev Calculates the slot timestamp
@@ -204,21 +205,36 @@ contract BeaconRANDAOVerifier {
      * @return The index in the RANDAO_MIXES array
      */
     function epochToRandaoIndex(uint64 epoch) public pure returns (uint64) {
-        uint64 EPOCHS_PER_HISTORICAL_VECTOR = 65536; // 2^16
         return epoch % EPOCHS_PER_HISTORICAL_VECTOR;
     }
 
     /**
-     * @dev Calculates the generalized index for a RANDAO mix
+     * @dev Calculates the generalized index of a RANDAO mix relative to the
+     *      beacon block root (block header -> state_root -> randao_mixes[i])
      * @param randaoIndex The index in the RANDAO_MIXES array
-     * @return The generalized index in the beacon state tree
+     * @param stateTreeDepth Depth of the BeaconState field tree: 5 for Capella
+     *        and Deneb (28 fields), 6 for Electra and Fulu (37 and 38 fields)
+     * @return The generalized index of the mix under the beacon block root
      */
     function calculateGeneralizedIndex(
-        uint64 randaoIndex
+        uint64 randaoIndex,
+        uint8 stateTreeDepth
     ) public pure returns (uint64) {
-        // This is a simplified calculation and may need adjustment based on
-        // the exact tree structure of the beacon state
-        uint64 TREE_DEPTH = 5; // This value depends on the state structure
-        return uint64((1 << TREE_DEPTH) * RANDAO_MIXES_INDEX + randaoIndex);
+        require(
+            randaoIndex < EPOCHS_PER_HISTORICAL_VECTOR,
+            "RANDAO index out of range"
+        );
+        require(
+            stateTreeDepth == 5 || stateTreeDepth == 6,
+            "Unsupported state tree depth"
+        );
+
+        // state_root is field 3 of the 5-field header: 2^3 + 3
+        uint64 stateRootIndex = STATE_ROOT_GINDEX;
+        // randao_mixes is field 13 of the state
+        uint64 mixesIndex = (stateRootIndex << stateTreeDepth) |
+            uint64(RANDAO_MIXES_INDEX);
+        // Vector[Bytes32, 65536] has depth 16
+        return (mixesIndex << RANDAO_MIXES_DEPTH) | randaoIndex;
     }
 }
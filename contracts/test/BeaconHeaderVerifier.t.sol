@@ -174,6 +174,30 @@ contract BeaconHeaderVerifierTest is Test {
         assertFalse(result, "Should return false for invalid proof");
     }
     
+    function test_VerifyProof() public {
+        // Generalized index 12 is body_root in the header, the same node as field index 4
+        bytes32 expectedRoot = _computeExpectedRoot(4, TEST_BODY_ROOT, mockProof);
+        
+        vm.mockCall(
+            BEACON_ROOTS_ADDRESS,
+            abi.encode(TEST_TIMESTAMP),
+            abi.encode(expectedRoot)
+        );
+        
+        bool result = verifier.verifyProof(TEST_TIMESTAMP, 12, TEST_BODY_ROOT, mockProof);
+        assertTrue(result, "Should verify the body root by generalized index");
+    }
+    
+    function test_VerifyProof_LengthMismatch() public {
+        vm.expectRevert("Proof length does not match generalized index");
+        verifier.verifyProof(TEST_TIMESTAMP, 24, TEST_BODY_ROOT, mockProof);
+    }
+    
+    function test_VerifyProof_InvalidIndex() public {
+        vm.expectRevert("Invalid generalized index");
+        verifier.verifyProof(TEST_TIMESTAMP, 1, TEST_BODY_ROOT, new bytes32[](0));
+    }
+    
     function testFuzz_VerifySlot(uint64 slot) public {
         // Convert slot to bytes32 as the contract would
         bytes32 slotBytes = bytes32(uint256(slot));
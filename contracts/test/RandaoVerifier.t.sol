@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.26;
+
+import "forge-std/Test.sol";
+import "../src/RandaoVerifier.sol";
+
+contract RandaoVerifierTest is Test {
+    address constant BEACON_ROOTS_ADDRESS = 0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02;
+
+    BeaconRANDAOVerifier private verifier;
+
+    function setUp() public {
+        verifier = new BeaconRANDAOVerifier(BEACON_ROOTS_ADDRESS);
+    }
+
+    // Expected values are the output of
+    //   beacon-verifier gindex -fork deneb state.randao_mixes[i]
+    // and the same with -fork electra
+    function test_CalculateGeneralizedIndex_Depth5() public view {
+        assertEq(verifier.calculateGeneralizedIndex(0, 5), 23920640, "Deneb randao_mixes[0]");
+        assertEq(verifier.calculateGeneralizedIndex(5, 5), 23920645, "Deneb randao_mixes[5]");
+        assertEq(verifier.calculateGeneralizedIndex(65535, 5), 23986175, "Deneb randao_mixes[65535]");
+    }
+
+    function test_CalculateGeneralizedIndex_Depth6() public view {
+        assertEq(verifier.calculateGeneralizedIndex(0, 6), 46989312, "Electra randao_mixes[0]");
+        assertEq(verifier.calculateGeneralizedIndex(5, 6), 46989317, "Electra randao_mixes[5]");
+        assertEq(verifier.calculateGeneralizedIndex(65535, 6), 47054847, "Electra randao_mixes[65535]");
+    }
+
+    function test_CalculateGeneralizedIndex_UnsupportedDepth() public {
+        vm.expectRevert("Unsupported state tree depth");
+        verifier.calculateGeneralizedIndex(5, 4);
+
+        vm.expectRevert("Unsupported state tree depth");
+        verifier.calculateGeneralizedIndex(5, 7);
+    }
+
+    function test_CalculateGeneralizedIndex_IndexOutOfRange() public {
+        vm.expectRevert("RANDAO index out of range");
+        verifier.calculateGeneralizedIndex(65536, 6);
+    }
+}